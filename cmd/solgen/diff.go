@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/otherview/solgen/internal/abidiff"
+	"github.com/otherview/solgen/internal/parse"
+	"github.com/otherview/solgen/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func diffCmd() *cobra.Command {
+	flags := &ProcessFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "diff <old.json> <new.json>",
+		Short: "Report ABI changes between two compilations",
+		Long:  "Compares the methods, events and errors of two compiler outputs and fails if any was removed or changed.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(flags, args[0], args[1], cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.InputFormat, "input-format", "combined", "Input format of both files: combined (solc --combined-json), standard-json (solc --standard-json), etherscan (getabi response) or archive (zip of Foundry or Hardhat artifacts)")
+	cmd.Flags().StringVar(&flags.Name, "name", "", "Contract name used with --input-format etherscan")
+
+	return cmd
+}
+
+func runDiff(flags *ProcessFlags, oldPath, newPath string, out io.Writer) error {
+	oldContracts, err := readContracts(oldPath, flags)
+	if err != nil {
+		return err
+	}
+	newContracts, err := readContracts(newPath, flags)
+	if err != nil {
+		return err
+	}
+
+	breaking := 0
+	for _, change := range abidiff.Compare(oldContracts, newContracts) {
+		fmt.Fprintln(out, change)
+		if change.Breaking() {
+			breaking++
+		}
+	}
+	if breaking > 0 {
+		return fmt.Errorf("%d breaking ABI changes between %s and %s", breaking, oldPath, newPath)
+	}
+	return nil
+}
+
+// readContracts parses the compiler output stored in a file
+func readContracts(path string, flags *ProcessFlags) ([]*types.Contract, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	standardResult, solcVersion, err := readInput(data, flags)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	contracts, err := parse.ResultWithVersion(standardResult, solcVersion)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return contracts, nil
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/otherview/solgen/internal/parse"
+	"github.com/otherview/solgen/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func listCmd() *cobra.Command {
+	flags := &ProcessFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the contracts in the input without generating code",
+		Long:  "Reads compiler output from stdin and prints a summary of every contract it contains.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(flags, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.InputFormat, "input-format", "combined", "Input format: combined (solc --combined-json), standard-json (solc --standard-json), etherscan (getabi response) or archive (zip of Foundry or Hardhat artifacts)")
+	cmd.Flags().StringVar(&flags.Name, "name", "", "Contract name used with --input-format etherscan")
+
+	return cmd
+}
+
+func runList(flags *ProcessFlags, out io.Writer) error {
+	jsonData, err := readStdin()
+	if err != nil {
+		return err
+	}
+
+	standardResult, solcVersion, err := readInput(jsonData, flags)
+	if err != nil {
+		return err
+	}
+
+	contracts, err := parse.ResultWithVersion(standardResult, solcVersion)
+	if err != nil {
+		return fmt.Errorf("parsing failed: %w", err)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTRACT\tSOURCE\tMETHODS\tEVENTS\tERRORS\tBYTECODE")
+	for _, contract := range contracts {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n",
+			contract.Name,
+			contract.SourceFile,
+			len(contract.Methods),
+			len(contract.Events),
+			len(contract.Errors),
+			bytecodeSummary(contract),
+		)
+	}
+	return w.Flush()
+}
+
+// bytecodeSummary reports whether a contract can be deployed from the input
+func bytecodeSummary(contract *types.Contract) string {
+	if contract.Bytecode == "" || contract.Bytecode.Hex() == "0x" {
+		return "no"
+	}
+	return "yes"
+}
+
+// readStdin reads the compiler output piped to solgen
+func readStdin() ([]byte, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("reading from stdin: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no JSON data provided on stdin")
+	}
+	return data, nil
+}
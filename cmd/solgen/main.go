@@ -4,23 +4,57 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/otherview/solgen/internal/gen"
+	"github.com/otherview/solgen/internal/input"
 	"github.com/otherview/solgen/internal/parse"
 	"github.com/otherview/solgen/internal/types"
 	"github.com/spf13/cobra"
 )
 
 type ProcessFlags struct {
-	Output  string
-	Verbose bool
+	Outputs        []string
+	Contracts      []string
+	Verbose        bool
+	SingleFile     bool
+	Package        string
+	WithBind       bool
+	InputFormat    string
+	Name           string
+	Manifest       string
+	TypeMap        string
+	RuntimeOnly    bool
+	MinSolc        string
+	MaxSolc        string
+	Strict         bool
+	WithEqual      bool
+	Clean          bool
+	RuntimePackage string
+	Check          bool
+	NoFormat       bool
+	HeaderFile     string
+	WithKeccak     bool
+	EmbedABI       bool
+	Layout         string
+	SolcVersion    string
+	TinyGo         bool
+	WithFound      bool
+	WithProxy      bool
+	Qualified      bool
+	Prune          bool
+	MaxDepth       int
+	QualifyStructs bool
+	StrictDecode   bool
+	Verify         bool
+	NativeTypes    bool
+	DumpModel      string
 }
 
-
 func main() {
 	if err := rootCmd().Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -41,71 +75,326 @@ func rootCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&flags.Output, "out", "", "Output directory for generated Go packages")
+	cmd.Flags().StringArrayVar(&flags.Outputs, "out", nil, "Output directory for generated Go packages, repeat it with --contracts to route contracts to several directories")
+	cmd.Flags().StringArrayVar(&flags.Contracts, "contracts", nil, "Comma-separated names of the contracts generated into the --out given at the same position, e.g. Token,Vault")
 	cmd.Flags().BoolVarP(&flags.Verbose, "verbose", "v", false, "Verbose output")
+	cmd.Flags().BoolVar(&flags.SingleFile, "single-file", false, "Generate all contracts into a single Go file and package")
+	cmd.Flags().StringVar(&flags.Package, "package", gen.DefaultSingleFilePackage, "Package name used with --single-file")
+	cmd.Flags().StringVar(&flags.InputFormat, "input-format", "combined", "Input format: combined (solc --combined-json), standard-json (solc --standard-json), etherscan (getabi response) or archive (zip of Foundry or Hardhat artifacts)")
+	cmd.Flags().StringVar(&flags.Name, "name", "", "Contract name used with --input-format etherscan")
+	cmd.Flags().StringVar(&flags.Manifest, "manifest", "", "Write the list of generated files to this path, relative to --out")
+	cmd.Flags().StringVar(&flags.TypeMap, "type-map", "", "JSON file overriding the Go types of struct fields, keyed by Solidity type")
+	cmd.Flags().BoolVar(&flags.RuntimeOnly, "runtime-only", false, "Omit creation bytecode and constructor helpers, keeping only the ABI, decoders and DeployedBytecode")
+	cmd.Flags().StringVar(&flags.MinSolc, "min-solc", "", "Warn when the input was compiled with a solc version older than this, e.g. 0.8.4")
+	cmd.Flags().StringVar(&flags.SolcVersion, "solc-version", "", "Compiler version written to the generated code and checked against --min-solc/--max-solc, overriding the one read from the input")
+	cmd.Flags().StringVar(&flags.MaxSolc, "max-solc", "", "Warn when the input was compiled with a solc version newer than this")
+	cmd.Flags().BoolVar(&flags.Strict, "strict", false, "Fail instead of warning when the solc version is outside --min-solc/--max-solc")
+	cmd.Flags().BoolVar(&flags.WithEqual, "with-equal", false, "Generate Equal and IsZero methods on decoded structs")
+	cmd.Flags().BoolVar(&flags.Clean, "clean", false, "Remove previously generated files from --out before generating, keeping hand-written files")
+	cmd.Flags().StringVar(&flags.RuntimePackage, "runtime-package", "", "Import path of a package, written under --out, receiving the shared runtime that contract packages then import")
+	cmd.Flags().BoolVar(&flags.Check, "check", false, "Do not write anything, list the files in --out that are out of date and fail if there are any")
+	cmd.Flags().BoolVar(&flags.WithBind, "with-bind", false, "Generate go-ethereum interop helpers (requires go-ethereum in the consuming module)")
+	cmd.Flags().StringVar(&flags.HeaderFile, "header-file", "", "File whose contents are written as comments after the generated-by marker of every generated file, replacing the default SPDX line")
+	cmd.Flags().BoolVar(&flags.NoFormat, "no-format", false, "Skip gofmt on the generated code, for faster regeneration when it is formatted separately")
+	cmd.Flags().BoolVar(&flags.WithKeccak, "with-keccak", false, "Generate a dependency-free Keccak256 and SelectorOf to compute selectors at runtime")
+	cmd.Flags().BoolVar(&flags.WithFound, "getters-with-found", false, "Generate DecodeFound on mapping getters returning structs, reporting whether the returned value is set (implies --with-equal)")
+	cmd.Flags().BoolVar(&flags.WithProxy, "with-proxy-helpers", false, "Generate ImplementationSlot and AddressFromStorage to read the implementation behind an ERC-1967 proxy")
+	cmd.Flags().BoolVar(&flags.Qualified, "qualified-errors", false, "Prefix decode errors with the contract and method, event or error name, e.g. \"simpletoken.transfer: insufficient data for return value\"")
+	cmd.Flags().BoolVar(&flags.StrictDecode, "strict-decode", false, "Reject decoded addresses whose 12 padding bytes are not zero and bools other than 0 and 1, to catch malformed data")
+	cmd.Flags().BoolVar(&flags.Prune, "prune-unused-structs", false, "Omit structs, and their decoders, that no generated method, event, error or constructor refers to")
+	cmd.Flags().IntVar(&flags.MaxDepth, "max-depth", parse.DefaultMaxDepth, "Maximum nesting of arrays and tuples in a parameter type, deeper types fail instead of generating unbounded decoders")
+	cmd.Flags().BoolVar(&flags.QualifyStructs, "qualify-structs", false, "Keep the contract name in the names of structs declared with the same name by several contracts, e.g. AUser and BUser for A.User and B.User")
+	cmd.Flags().BoolVar(&flags.NativeTypes, "native-types", false, "Use go-ethereum's common.Address and common.Hash as the generated Address and Hash types, so values need no conversion (requires --with-bind)")
+	cmd.Flags().BoolVar(&flags.TinyGo, "tinygo", false, "Generate decoders that build under TinyGo, decoding arrays without interface{} values (cannot be used with --with-bind)")
+	cmd.Flags().BoolVar(&flags.EmbedABI, "embed-abi", false, "Write each ABI to an abi.json file next to the generated code and go:embed it instead of inlining it")
+	cmd.Flags().BoolVar(&flags.Verify, "verify", false, "Run go build on the generated packages and fail when they do not compile, with a temporary go.mod when --out is not inside a module")
+	cmd.Flags().StringVar(&flags.DumpModel, "dump-model", "", "Write the parsed contracts, with their Go types, selectors and structs, as JSON to this file, to snapshot parser changes")
+	cmd.Flags().StringVar(&flags.Layout, "layout", "", "JSON file setting the output subdirectory and package name of contracts, keyed by contract name")
+	// --raw is the older name of --no-format, kept for debugging templates
+	cmd.Flags().BoolVar(&flags.NoFormat, "raw", false, "Alias of --no-format")
+	cmd.Flags().MarkHidden("raw")
 
 	cmd.MarkFlagRequired("out")
 
+	cmd.AddCommand(listCmd(), diffCmd())
+
 	return cmd
 }
 
 func runProcessJSON(flags *ProcessFlags) error {
-	// Validate output directory
-	if flags.Output == "" {
+	// Validate output directories
+	if len(flags.Outputs) == 0 || slices.Contains(flags.Outputs, "") {
 		return fmt.Errorf("output directory cannot be empty")
 	}
-	if err := os.MkdirAll(flags.Output, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	if (len(flags.Outputs) > 1 || len(flags.Contracts) > 0) && len(flags.Contracts) != len(flags.Outputs) {
+		return fmt.Errorf("--contracts must be given once per --out, got %d for %d output directories", len(flags.Contracts), len(flags.Outputs))
+	}
+	if flags.Layout != "" && flags.SingleFile {
+		return fmt.Errorf("--layout cannot be used with --single-file")
+	}
+	if flags.Verify && flags.Check {
+		return fmt.Errorf("--verify cannot be used with --check, which writes nothing to build")
+	}
+	if flags.TinyGo && flags.WithBind {
+		return fmt.Errorf("--tinygo cannot be used with --with-bind, go-ethereum does not build under TinyGo")
+	}
+	if flags.NativeTypes && !flags.WithBind {
+		return fmt.Errorf("--native-types requires --with-bind")
+	}
+	if flags.MaxDepth < 1 {
+		return fmt.Errorf("--max-depth must be at least 1")
+	}
+	// --check writes nothing, the files of a missing directory are reported as stale
+	if !flags.Check {
+		for _, output := range flags.Outputs {
+			if err := os.MkdirAll(output, 0755); err != nil {
+				return fmt.Errorf("failed to create output directory: %w", err)
+			}
+		}
 	}
 
 	// Read combined JSON from stdin
-	jsonData, err := io.ReadAll(os.Stdin)
+	jsonData, err := readStdin()
 	if err != nil {
-		return fmt.Errorf("reading from stdin: %w", err)
+		return err
 	}
 
-	if len(jsonData) == 0 {
-		return fmt.Errorf("no JSON data provided on stdin")
+	standardResult, solcVersion, err := readInput(jsonData, flags)
+	if err != nil {
+		return err
+	}
+	if flags.SolcVersion != "" {
+		solcVersion = flags.SolcVersion
 	}
 
-	// Parse combined JSON
-	var combinedJSON types.CombinedJSON
-	if err := json.Unmarshal(jsonData, &combinedJSON); err != nil {
-		return fmt.Errorf("parsing combined JSON: %w", err)
+	if err := parse.CheckSolcVersion(solcVersion, flags.MinSolc, flags.MaxSolc); err != nil {
+		if flags.Strict || !errors.Is(err, parse.ErrSolcVersionOutOfRange) {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
 
-	if len(combinedJSON.Contracts) == 0 {
-		return fmt.Errorf("no contracts found in JSON output")
+	parseOptions := parse.Options{MaxDepth: flags.MaxDepth, QualifyStructs: flags.QualifyStructs}
+	if flags.TypeMap != "" {
+		typeMapData, err := os.ReadFile(flags.TypeMap)
+		if err != nil {
+			return fmt.Errorf("reading type map: %w", err)
+		}
+		parseOptions.TypeMap, err = parse.LoadTypeMap(typeMapData)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Convert combined JSON to standard format
-	standardResult, err := convertCombinedToStandard(combinedJSON, flags.Verbose)
-	if err != nil {
-		return fmt.Errorf("converting JSON format: %w", err)
+	if flags.Layout != "" {
+		layoutData, err := os.ReadFile(flags.Layout)
+		if err != nil {
+			return fmt.Errorf("reading layout: %w", err)
+		}
+		parseOptions.Layout, err = parse.LoadLayout(layoutData)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Extract solc version, fallback to unknown if not available
-	solcVersion := combinedJSON.Version
-	if solcVersion == "" {
-		solcVersion = "unknown"
+	var header string
+	if flags.HeaderFile != "" {
+		headerData, err := os.ReadFile(flags.HeaderFile)
+		if err != nil {
+			return fmt.Errorf("reading header file: %w", err)
+		}
+		header = string(headerData)
 	}
 
 	// Parse compilation result (reuse existing logic)
-	contracts, err := parse.ResultWithVersion(standardResult, solcVersion)
+	contracts, err := parse.ResultWithOptions(standardResult, solcVersion, parseOptions)
 	if err != nil {
 		return fmt.Errorf("parsing failed: %w", err)
 	}
+	if flags.Verbose {
+		reportRenamedMethods(contracts)
+	}
+	if flags.DumpModel != "" {
+		if err := writeModel(flags.DumpModel, contracts); err != nil {
+			return err
+		}
+	}
+	targets, err := outputTargets(flags, contracts)
+	if err != nil {
+		return err
+	}
+
+	// Generate Go packages into every output directory
+	options := gen.Options{
+		SingleFile:     flags.SingleFile,
+		PackageName:    flags.Package,
+		WithBind:       flags.WithBind,
+		Manifest:       flags.Manifest,
+		RuntimeOnly:    flags.RuntimeOnly,
+		WithEqual:      flags.WithEqual,
+		Clean:          flags.Clean,
+		RuntimePackage: flags.RuntimePackage,
+		NoFormat:       flags.NoFormat,
+		Header:         header,
+		WithKeccak:     flags.WithKeccak,
+		EmbedABI:       flags.EmbedABI,
+		TinyGo:         flags.TinyGo,
+		WithFound:      flags.WithFound,
+		WithProxy:      flags.WithProxy,
+		Qualified:      flags.Qualified,
+		Prune:          flags.Prune,
+		NativeTypes:    flags.NativeTypes,
+		StrictDecode:   flags.StrictDecode,
+	}
 
-	// Generate Go packages (reuse existing logic)
-	generator := gen.NewGenerator(flags.Output)
-	if err := generator.Generate(contracts); err != nil {
-		return fmt.Errorf("code generation failed: %w", err)
+	if flags.Check {
+		var stale []string
+		for _, target := range targets {
+			targetStale, err := gen.NewGeneratorWithOptions(target.dir, options).Check(target.contracts)
+			if err != nil {
+				return fmt.Errorf("code generation failed: %w", err)
+			}
+			stale = append(stale, targetStale...)
+		}
+		for _, filePath := range stale {
+			fmt.Println(filePath)
+		}
+		if len(stale) > 0 {
+			return fmt.Errorf("%d generated files in %s are out of date", len(stale), strings.Join(flags.Outputs, ", "))
+		}
+		return nil
+	}
+
+	for _, target := range targets {
+		if err := gen.NewGeneratorWithOptions(target.dir, options).Generate(target.contracts); err != nil {
+			return fmt.Errorf("code generation failed: %w", err)
+		}
+		if flags.Verify {
+			if err := verifyBuild(target.dir, flags.RuntimePackage); err != nil {
+				return err
+			}
+		}
+
+		if flags.SingleFile {
+			fmt.Printf("Successfully generated %d contracts into package %s in %s\n", len(target.contracts), flags.Package, target.dir)
+			continue
+		}
+		fmt.Printf("Successfully generated %d contract packages in %s\n", len(target.contracts), target.dir)
 	}
+	return nil
+}
 
-	fmt.Printf("Successfully generated %d contract packages in %s\n", len(contracts), flags.Output)
+// writeModel writes the parsed contracts as indented JSON. Contracts are sorted
+// and map keys ordered by encoding/json, so the output is stable across runs.
+func writeModel(path string, contracts []*types.Contract) error {
+	data, err := json.MarshalIndent(contracts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding model: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing model: %w", err)
+	}
 	return nil
 }
 
+// outputTarget is an output directory and the contracts generated into it
+type outputTarget struct {
+	dir       string
+	contracts []*types.Contract
+}
+
+// outputTargets pairs every --out with the contracts its --contracts names,
+// or a single --out with all contracts
+func outputTargets(flags *ProcessFlags, contracts []*types.Contract) ([]outputTarget, error) {
+	if len(flags.Contracts) == 0 {
+		return []outputTarget{{dir: flags.Outputs[0], contracts: contracts}}, nil
+	}
+
+	byName := make(map[string]*types.Contract, len(contracts))
+	for _, contract := range contracts {
+		byName[contract.Name] = contract
+	}
+
+	targets := make([]outputTarget, len(flags.Outputs))
+	for i, dir := range flags.Outputs {
+		targets[i].dir = dir
+		for _, name := range strings.Split(flags.Contracts[i], ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			contract, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("--contracts for %s: no contract named %q", dir, name)
+			}
+			targets[i].contracts = append(targets[i].contracts, contract)
+		}
+		if len(targets[i].contracts) == 0 {
+			return nil, fmt.Errorf("--contracts for %s names no contract", dir)
+		}
+	}
+	return targets, nil
+}
+
+// reportRenamedMethods notes the methods whose accessor is not named after their Solidity name
+func reportRenamedMethods(contracts []*types.Contract) {
+	for _, contract := range contracts {
+		for _, method := range contract.Methods {
+			if method.Name == method.BaseName {
+				continue
+			}
+			accessor := strings.ToUpper(method.Name[:1]) + method.Name[1:] + "Method()"
+			note := fmt.Sprintf("Note: %s.%s is generated as %s because %s is overloaded", contract.Name, method.Signature, accessor, method.BaseName)
+			if method.DefaultOverload {
+				// The overload with the fewest parameters also keeps the plain accessor
+				note += fmt.Sprintf(", %s returns this default overload", strings.ToUpper(method.BaseName[:1])+method.BaseName[1:]+"Method()")
+			}
+			fmt.Println(note)
+		}
+	}
+}
+
+// readInput converts the raw input into a standard compile result according to the input format
+func readInput(data []byte, flags *ProcessFlags) (*types.CompileResult, string, error) {
+	switch flags.InputFormat {
+	case "", "combined":
+		// Parse combined JSON
+		var combinedJSON types.CombinedJSON
+		if err := json.Unmarshal(data, &combinedJSON); err != nil {
+			return nil, "", fmt.Errorf("parsing combined JSON: %w", err)
+		}
+
+		if len(combinedJSON.Contracts) == 0 {
+			return nil, "", fmt.Errorf("no contracts found in JSON output")
+		}
+
+		// Convert combined JSON to standard format
+		standardResult, err := convertCombinedToStandard(combinedJSON, flags.Verbose)
+		if err != nil {
+			return nil, "", fmt.Errorf("converting JSON format: %w", err)
+		}
+
+		// Extract solc version, fallback to unknown if not available
+		solcVersion := combinedJSON.Version
+		if solcVersion == "" {
+			solcVersion = "unknown"
+		}
+		return standardResult, solcVersion, nil
+	case "etherscan":
+		standardResult, err := input.Etherscan(data, flags.Name)
+		if err != nil {
+			return nil, "", err
+		}
+		return standardResult, "unknown", nil
+	case "standard-json":
+		return input.StandardJSON(data)
+	case "archive":
+		return input.Archive(data)
+	default:
+		return nil, "", fmt.Errorf("unsupported input format %q (expected combined, standard-json, etherscan or archive)", flags.InputFormat)
+	}
+}
+
 // convertCombinedToStandard converts combined JSON format to standard JSON format.
 // This conversion layer provides compatibility with the existing parser infrastructure
 // and allows for potential future support of solc's --standard-json format.
@@ -133,18 +422,30 @@ func convertCombinedToStandard(combinedJSON types.CombinedJSON, verbose bool) (*
 			result.Contracts[filename] = make(map[string]types.ContractResult)
 		}
 
+		// Combined JSON only carries library placeholders, link references are derived from them
 		result.Contracts[filename][contractName] = types.ContractResult{
 			ABI: contract.ABI,
 			EVM: types.EVMResult{
 				Bytecode: types.BytecodeResult{
-					Object: contract.Bin,
+					Object:         contract.Bin,
+					LinkReferences: input.LinkReferences(contract.Bin),
 				},
 				DeployedBytecode: types.BytecodeResult{
-					Object: contract.BinRuntime,
+					Object:         contract.BinRuntime,
+					LinkReferences: input.LinkReferences(contract.BinRuntime),
 				},
 				MethodIdentifiers: contract.Hashes,
 			},
+			DevDoc: contract.DevDoc,
+		}
+	}
+
+	// Source ASTs, present when ast was selected, describe the enums
+	for sourceFile, source := range combinedJSON.Sources {
+		if result.Sources == nil {
+			result.Sources = make(map[string]types.SourceResult)
 		}
+		result.Sources[sourceFile] = types.SourceResult{AST: source.AST}
 	}
 
 	return result, nil
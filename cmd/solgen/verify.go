@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// verifyModule is the module path of the go.mod written to build generated code
+// outside of any module
+const verifyModule = "solgen-verify"
+
+// verifyBuild runs go build on the packages generated into dir. Outside of a
+// module a temporary go.mod is written for the build and removed afterwards,
+// named after the parent of runtimePackage so that its import resolves.
+func verifyBuild(dir, runtimePackage string) error {
+	inModule, err := insideModule(dir)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"build", "./..."}
+	if !inModule {
+		module := verifyModule
+		if runtimePackage != "" {
+			module = path.Dir(runtimePackage)
+		}
+		goMod := filepath.Join(dir, "go.mod")
+		if err := os.WriteFile(goMod, []byte(fmt.Sprintf("module %s\n\ngo 1.21\n", module)), 0644); err != nil {
+			return fmt.Errorf("writing temporary go.mod: %w", err)
+		}
+		defer os.Remove(goMod)
+		// -mod=mod resolves the go-ethereum imports of --with-bind, writing a go.sum
+		goSum := filepath.Join(dir, "go.sum")
+		if _, err := os.Stat(goSum); errors.Is(err, os.ErrNotExist) {
+			defer os.Remove(goSum)
+		}
+		args = []string{"build", "-mod=mod", "./..."}
+	}
+
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("generated code in %s does not build: %w\n%s", dir, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// insideModule reports whether dir belongs to a Go module
+func insideModule(dir string) (bool, error) {
+	cmd := exec.Command("go", "env", "GOMOD")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return false, fmt.Errorf("go env GOMOD: %w\n%s", err, exitErr.Stderr)
+		}
+		return false, fmt.Errorf("running go: %w", err)
+	}
+	goMod := strings.TrimSpace(string(output))
+	return goMod != "" && goMod != os.DevNull, nil
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: MIT
+
+// Package abidiff compares the ABIs of two compilations of the same contracts
+package abidiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/otherview/solgen/internal/types"
+)
+
+// Kind classifies a change between two ABIs
+type Kind string
+
+const (
+	Added   Kind = "+"
+	Removed Kind = "-"
+	Changed Kind = "~"
+)
+
+// Change is a single difference between the old and the new ABI of a contract.
+// Old is empty for added items and New is empty for removed ones.
+type Change struct {
+	Kind     Kind
+	Contract string
+	Item     string // contract, method, event or error
+	Old      string
+	New      string
+}
+
+// Breaking reports whether the change can break existing callers or decoders
+func (c Change) Breaking() bool {
+	return c.Kind != Added
+}
+
+// String formats the change as a single line, e.g.
+// "~ Token: method transfer(address,uint256) [0xa9059cbb] -> transfer(address,uint128) [0x...]"
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s %s: %s %s", c.Kind, c.Contract, c.Item, c.New)
+	case Removed:
+		return fmt.Sprintf("%s %s: %s %s", c.Kind, c.Contract, c.Item, c.Old)
+	default:
+		return fmt.Sprintf("%s %s: %s %s -> %s", c.Kind, c.Contract, c.Item, c.Old, c.New)
+	}
+}
+
+// entry is the projection of a method, event or error that is compared
+type entry struct {
+	name        string // Solidity name, pairs a removed and an added overload as a change
+	key         string // signature identifying the entry
+	description string // signature, selector and anything else callers depend on
+}
+
+// Compare returns the changes from the old to the new contracts. Contracts are
+// matched by name, and changes are ordered by contract, then item kind.
+func Compare(oldContracts, newContracts []*types.Contract) []Change {
+	oldByName := contractsByName(oldContracts)
+	newByName := contractsByName(newContracts)
+
+	var names []string
+	for name := range oldByName {
+		names = append(names, name)
+	}
+	for name := range newByName {
+		if _, ok := oldByName[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []Change
+	for _, name := range names {
+		oldContract, newContract := oldByName[name], newByName[name]
+		switch {
+		case newContract == nil:
+			changes = append(changes, Change{Kind: Removed, Contract: name, Item: "contract", Old: oldContract.SourceFile})
+		case oldContract == nil:
+			changes = append(changes, Change{Kind: Added, Contract: name, Item: "contract", New: newContract.SourceFile})
+		default:
+			changes = append(changes, compareEntries(name, "method", methodEntries(oldContract), methodEntries(newContract))...)
+			changes = append(changes, compareEntries(name, "event", eventEntries(oldContract), eventEntries(newContract))...)
+			changes = append(changes, compareEntries(name, "error", errorEntries(oldContract), errorEntries(newContract))...)
+		}
+	}
+	return changes
+}
+
+func contractsByName(contracts []*types.Contract) map[string]*types.Contract {
+	byName := make(map[string]*types.Contract, len(contracts))
+	for _, contract := range contracts {
+		byName[contract.Name] = contract
+	}
+	return byName
+}
+
+// compareEntries compares entries by signature. An entry whose signature is
+// only in one of the ABIs is reported as changed when it is the only overload
+// of its name on both sides, e.g. a parameter type changed, and as added or
+// removed otherwise.
+func compareEntries(contract, item string, oldEntries, newEntries []entry) []Change {
+	oldByKey := entriesByKey(oldEntries)
+	newByKey := entriesByKey(newEntries)
+
+	var changes []Change
+	removedByName := make(map[string][]entry)
+	addedByName := make(map[string][]entry)
+	for _, old := range oldEntries {
+		updated, ok := newByKey[old.key]
+		if !ok {
+			removedByName[old.name] = append(removedByName[old.name], old)
+			continue
+		}
+		if updated.description != old.description {
+			changes = append(changes, Change{Kind: Changed, Contract: contract, Item: item, Old: old.description, New: updated.description})
+		}
+	}
+	for _, updated := range newEntries {
+		if _, ok := oldByKey[updated.key]; !ok {
+			addedByName[updated.name] = append(addedByName[updated.name], updated)
+		}
+	}
+
+	for _, old := range oldEntries {
+		removed := removedByName[old.name]
+		if len(removed) == 0 || removed[0].key != old.key {
+			continue
+		}
+		if added := addedByName[old.name]; len(removed) == 1 && len(added) == 1 {
+			changes = append(changes, Change{Kind: Changed, Contract: contract, Item: item, Old: old.description, New: added[0].description})
+			delete(addedByName, old.name)
+			continue
+		}
+		for _, entry := range removed {
+			changes = append(changes, Change{Kind: Removed, Contract: contract, Item: item, Old: entry.description})
+		}
+	}
+	for _, updated := range newEntries {
+		added := addedByName[updated.name]
+		if len(added) == 0 || added[0].key != updated.key {
+			continue
+		}
+		for _, entry := range added {
+			changes = append(changes, Change{Kind: Added, Contract: contract, Item: item, New: entry.description})
+		}
+	}
+	return changes
+}
+
+func entriesByKey(entries []entry) map[string]entry {
+	byKey := make(map[string]entry, len(entries))
+	for _, e := range entries {
+		byKey[e.key] = e
+	}
+	return byKey
+}
+
+func methodEntries(contract *types.Contract) []entry {
+	var entries []entry
+	for _, method := range contract.Methods {
+		description := fmt.Sprintf("%s [%s]", method.Signature, method.Selector.Hex())
+		if len(method.Outputs) > 0 {
+			description = fmt.Sprintf("%s returns (%s) [%s]", method.Signature, abiTypes(method.Outputs, false), method.Selector.Hex())
+		}
+		entries = append(entries, entry{name: method.Name, key: method.Signature, description: description})
+	}
+	return entries
+}
+
+func eventEntries(contract *types.Contract) []entry {
+	var entries []entry
+	for _, event := range contract.Events {
+		signature := fmt.Sprintf("%s(%s)", event.Name, abiTypes(event.Inputs, false))
+		description := fmt.Sprintf("%s(%s) [%s]", event.Name, abiTypes(event.Inputs, true), event.Topic)
+		entries = append(entries, entry{name: event.Name, key: signature, description: description})
+	}
+	return entries
+}
+
+func errorEntries(contract *types.Contract) []entry {
+	var entries []entry
+	for _, contractError := range contract.Errors {
+		description := fmt.Sprintf("%s [%s]", contractError.Signature, contractError.Selector.Hex())
+		entries = append(entries, entry{name: contractError.Name, key: contractError.Signature, description: description})
+	}
+	return entries
+}
+
+// abiTypes joins the Solidity types of the parameters, marking indexed event
+// parameters when withIndexed is set
+func abiTypes(params []types.Parameter, withIndexed bool) string {
+	parts := make([]string, len(params))
+	for i, param := range params {
+		parts[i] = param.ABIType
+		if withIndexed && param.Indexed {
+			parts[i] += " indexed"
+		}
+	}
+	return strings.Join(parts, ",")
+}
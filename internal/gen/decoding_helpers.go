@@ -37,24 +37,6 @@ func decodeInt256(data []byte) (*big.Int, error) {
 	return result, nil
 }
 
-// decodeAddress decodes an address from 32 bytes
-func decodeAddress(data []byte) (Address, error) {
-	if len(data) < 32 {
-		return Address{}, errors.New("insufficient data for address")
-	}
-	var addr Address
-	copy(addr[:], data[12:32])
-	return addr, nil
-}
-
-// decodeBool decodes a boolean from 32 bytes
-func decodeBool(data []byte) (bool, error) {
-	if len(data) < 32 {
-		return false, errors.New("insufficient data for bool")
-	}
-	return data[31] != 0, nil
-}
-
 // decodeBytes decodes dynamic bytes
 func decodeBytes(data []byte, offset int) ([]byte, int, error) {
 	if len(data) < offset+32 {
@@ -112,54 +94,41 @@ func decodeBytes32(data []byte) ([32]byte, error) {
 	return result, nil
 }
 
-// decodeArray decodes dynamic arrays 
-func decodeArray(data []byte, offset int, elemDecoder func([]byte) (interface{}, error)) ([]interface{}, int, error) {
+// decodeArrayLength decodes the length of a dynamic array at offset, checking
+// that data holds its 32-byte elements
+func decodeArrayLength(data []byte, offset int) (int, error) {
 	if len(data) < offset+32 {
-		return nil, 0, errors.New("insufficient data for array length")
+		return 0, errors.New("insufficient data for array length")
 	}
-	
 	lengthBig, err := decodeUint256(data[offset : offset+32])
 	if err != nil {
-		return nil, 0, fmt.Errorf("decoding array length: %w", err)
+		return 0, fmt.Errorf("decoding array length: %w", err)
 	}
 	if !lengthBig.IsUint64() {
-		return nil, 0, errors.New("array length too large")
+		return 0, errors.New("array length too large")
 	}
-	length := int(lengthBig.Uint64())
-	
-	currentOffset := offset + 32
-	result := make([]interface{}, length)
-	
-	for i := 0; i < length; i++ {
-		if len(data) < currentOffset+32 {
-			return nil, 0, fmt.Errorf("insufficient data for array element %d", i)
-		}
-		elem, err := elemDecoder(data[currentOffset : currentOffset+32])
-		if err != nil {
-			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
-		}
-		result[i] = elem
-		currentOffset += 32
+	length := lengthBig.Uint64()
+	if length > uint64(len(data)-offset-32)/32 {
+		return 0, fmt.Errorf("insufficient data for %d array elements", length)
 	}
-	
-	return result, currentOffset, nil
-}
-
-// Array element decoders (internal use)
-func decodeUint256ArrayElement(data []byte) (interface{}, error) {
-	return decodeUint256(data)
-}
-
-func decodeInt256ArrayElement(data []byte) (interface{}, error) {
-	return decodeInt256(data)
-}
-
-func decodeAddressArrayElement(data []byte) (interface{}, error) {
-	return decodeAddress(data)
+	return int(length), nil
 }
 
-func decodeBoolArrayElement(data []byte) (interface{}, error) {
-	return decodeBool(data)
+// decodeFixedBytesElems decodes the length bytesN elements of a bytesN array,
+// each left-aligned in its own 32-byte word from offset
+func decodeFixedBytesElems(data []byte, offset, length, size int) ([][]byte, error) {
+	if len(data) < offset+length*32 {
+		return nil, fmt.Errorf("insufficient data for %d fixed bytes elements", length)
+	}
+	elems := make([][]byte, length)
+	for i := range elems {
+		elem, err := decodeFixedBytes(data[offset+i*32:], size)
+		if err != nil {
+			return nil, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+		elems[i] = elem
+	}
+	return elems, nil
 }
 
 // decodeUint8 decodes a uint8 from 32 bytes
@@ -247,19 +216,51 @@ func decodeInt64(data []byte) (int64, error) {
 		}
 	}
 	
+	// The sign bit of the low 8 bytes must match the sign extension
+	if (data[24]&0x80 != 0) != isNegative {
+		return 0, errors.New("value exceeds int64 range")
+	}
+
+	// The low 8 bytes are already the two's complement int64
 	var result int64
 	for i := 24; i < 32; i++ {
 		result = (result << 8) | int64(data[i])
 	}
-	
-	// Sign extend if necessary
-	if isNegative {
-		result |= ^((1 << 32) - 1) // Set upper 32 bits
-	}
-	
+
 	return result, nil
 }
 
+// decodeInt8 decodes a int8 from 32 bytes
+func decodeInt8(data []byte) (int8, error) {
+	val, err := decodeSmallInt(data, 8)
+	return int8(val), err
+}
+
+// decodeInt16 decodes a int16 from 32 bytes
+func decodeInt16(data []byte) (int16, error) {
+	val, err := decodeSmallInt(data, 16)
+	return int16(val), err
+}
+
+// decodeInt32 decodes a int32 from 32 bytes
+func decodeInt32(data []byte) (int32, error) {
+	val, err := decodeSmallInt(data, 32)
+	return int32(val), err
+}
+
+// decodeSmallInt decodes a signed integer of the given bits from 32 bytes,
+// rejecting values that do not fit, e.g. 200 as an int8
+func decodeSmallInt(data []byte, bits int) (int64, error) {
+	if len(data) < 32 {
+		return 0, fmt.Errorf("insufficient data for int%d", bits)
+	}
+	val, err := decodeInt64(data)
+	if err != nil || val < -1<<(bits-1) || val >= 1<<(bits-1) {
+		return 0, fmt.Errorf("value exceeds int%d range", bits)
+	}
+	return val, nil
+}
+
 // decodeHash decodes a 32-byte hash
 func decodeHash(data []byte) (Hash, error) {
 	if len(data) < 32 {
@@ -277,4 +278,311 @@ func decodeString(data []byte, offset int) (string, int, error) {
 		return "", 0, err
 	}
 	return string(bytes), nextOffset, nil
-}`
\ No newline at end of file
+}
+
+// decodeOffset reads the offset word at headOffset and returns the position it
+// points to, relative to base, as used for dynamic values in a tuple head
+func decodeOffset(data []byte, base, headOffset int) (int, error) {
+	if len(data) < headOffset+32 {
+		return 0, errors.New("insufficient data for offset")
+	}
+	offset, err := decodeUint256(data[headOffset : headOffset+32])
+	if err != nil {
+		return 0, fmt.Errorf("decoding offset: %w", err)
+	}
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-base) {
+		return 0, errors.New("offset out of bounds")
+	}
+	return base + int(offset.Uint64()), nil
+}
+
+// decodeBytesReader reads the encoding of a single bytes or string return value
+// from r, and returns a reader streaming its content from r along with its length
+func decodeBytesReader(r io.Reader) (io.Reader, uint64, error) {
+	word := make([]byte, 32)
+	if _, err := io.ReadFull(r, word); err != nil {
+		return nil, 0, fmt.Errorf("reading offset: %w", err)
+	}
+	offset, err := decodeUint256(word)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decoding offset: %w", err)
+	}
+	if !offset.IsInt64() || offset.Int64() < 32 {
+		return nil, 0, errors.New("offset out of bounds")
+	}
+	// Skip anything between the head and the length word
+	if _, err := io.CopyN(io.Discard, r, offset.Int64()-32); err != nil {
+		return nil, 0, fmt.Errorf("skipping to bytes length: %w", err)
+	}
+
+	if _, err := io.ReadFull(r, word); err != nil {
+		return nil, 0, fmt.Errorf("reading bytes length: %w", err)
+	}
+	length, err := decodeUint256(word)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decoding bytes length: %w", err)
+	}
+	if !length.IsInt64() {
+		return nil, 0, errors.New("bytes length too large")
+	}
+	return &exactReader{r: r, n: length.Int64()}, length.Uint64(), nil
+}
+
+// exactReader reads n bytes from r, reporting io.ErrUnexpectedEOF if r ends early
+type exactReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read implements io.Reader
+func (e *exactReader) Read(p []byte) (int, error) {
+	if e.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > e.n {
+		p = p[:e.n]
+	}
+	n, err := e.r.Read(p)
+	e.n -= int64(n)
+	if err == io.EOF && e.n > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// DecodeMulticall decodes the bytes[] returned by multicall(bytes[]) into the
+// return data of every call, to pass to the Decode of the matching method
+func DecodeMulticall(data []byte) ([]HexData, error) {
+	arrayOffset, err := decodeOffset(data, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("decoding multicall results: %w", err)
+	}
+	length, err := decodeArrayLength(data, arrayOffset)
+	if err != nil {
+		return nil, fmt.Errorf("decoding multicall results: %w", err)
+	}
+
+	// Element offsets are relative to the end of the array length
+	base := arrayOffset + 32
+	results := make([]HexData, length)
+	for i := range results {
+		elemOffset, err := decodeOffset(data, base, base+32*i)
+		if err != nil {
+			return nil, fmt.Errorf("decoding multicall result %d: %w", i, err)
+		}
+		result, _, err := decodeBytes(data, elemOffset)
+		if err != nil {
+			return nil, fmt.Errorf("decoding multicall result %d: %w", i, err)
+		}
+		results[i] = HexData("0x" + hex.EncodeToString(result))
+	}
+	return results, nil
+}`
+// arrayDecodingTemplate contains the generic array decoder, whose elements are
+// decoded into interface{} values and asserted back by the generated code
+const arrayDecodingTemplate = `// decodeArray decodes dynamic arrays 
+func decodeArray(data []byte, offset int, elemDecoder func([]byte) (interface{}, error)) ([]interface{}, int, error) {
+	length, err := decodeArrayLength(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	currentOffset := offset + 32
+	result := make([]interface{}, length)
+	
+	for i := 0; i < length; i++ {
+		elem, err := elemDecoder(data[currentOffset : currentOffset+32])
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+		result[i] = elem
+		currentOffset += 32
+	}
+	
+	return result, currentOffset, nil
+}
+
+// Array element decoders (internal use)
+func decodeUint256ArrayElement(data []byte) (interface{}, error) {
+	return decodeUint256(data)
+}
+
+func decodeInt256ArrayElement(data []byte) (interface{}, error) {
+	return decodeInt256(data)
+}
+
+func decodeAddressArrayElement(data []byte) (interface{}, error) {
+	return decodeAddress(data)
+}
+
+func decodeBoolArrayElement(data []byte) (interface{}, error) {
+	return decodeBool(data)
+}
+
+func decodeBytes32ArrayElement(data []byte) (interface{}, error) {
+	return decodeBytes32(data)
+}`
+
+// typedArrayDecodingTemplate contains one array decoder per element type, used
+// with --tinygo instead of arrayDecodingTemplate so that decoding does not go
+// through interface{} values and type assertions
+const typedArrayDecodingTemplate = `// decodeUint256Array decodes a dynamic uint256[] array
+func decodeUint256Array(data []byte, offset int) ([]*big.Int, int, error) {
+	length, err := decodeArrayLength(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	currentOffset := offset + 32
+	result := make([]*big.Int, length)
+	for i := range result {
+		result[i], err = decodeUint256(data[currentOffset : currentOffset+32])
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+		currentOffset += 32
+	}
+	return result, currentOffset, nil
+}
+
+// decodeInt256Array decodes a dynamic int256[] array
+func decodeInt256Array(data []byte, offset int) ([]*big.Int, int, error) {
+	length, err := decodeArrayLength(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	currentOffset := offset + 32
+	result := make([]*big.Int, length)
+	for i := range result {
+		result[i], err = decodeInt256(data[currentOffset : currentOffset+32])
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+		currentOffset += 32
+	}
+	return result, currentOffset, nil
+}
+
+// decodeUint64Array decodes a dynamic uint64[] array
+func decodeUint64Array(data []byte, offset int) ([]uint64, int, error) {
+	length, err := decodeArrayLength(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	currentOffset := offset + 32
+	result := make([]uint64, length)
+	for i := range result {
+		result[i], err = decodeUint64(data[currentOffset : currentOffset+32])
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+		currentOffset += 32
+	}
+	return result, currentOffset, nil
+}
+
+// decodeAddressArray decodes a dynamic address[] array
+func decodeAddressArray(data []byte, offset int) ([]Address, int, error) {
+	length, err := decodeArrayLength(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	currentOffset := offset + 32
+	result := make([]Address, length)
+	for i := range result {
+		result[i], err = decodeAddress(data[currentOffset : currentOffset+32])
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+		currentOffset += 32
+	}
+	return result, currentOffset, nil
+}
+
+// decodeBoolArray decodes a dynamic bool[] array
+func decodeBoolArray(data []byte, offset int) ([]bool, int, error) {
+	length, err := decodeArrayLength(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	currentOffset := offset + 32
+	result := make([]bool, length)
+	for i := range result {
+		result[i], err = decodeBool(data[currentOffset : currentOffset+32])
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+		currentOffset += 32
+	}
+	return result, currentOffset, nil
+}
+
+// decodeBytes32Array decodes a dynamic bytes32[] array
+func decodeBytes32Array(data []byte, offset int) ([][32]byte, int, error) {
+	length, err := decodeArrayLength(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	currentOffset := offset + 32
+	result := make([][32]byte, length)
+	for i := range result {
+		result[i], err = decodeBytes32(data[currentOffset : currentOffset+32])
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+		currentOffset += 32
+	}
+	return result, currentOffset, nil
+}`
+
+// valueDecodingTemplate contains the address and bool decoders, which accept any
+// padding like most ABI decoders
+const valueDecodingTemplate = `// decodeAddress decodes an address from 32 bytes
+func decodeAddress(data []byte) (Address, error) {
+	if len(data) < 32 {
+		return Address{}, errors.New("insufficient data for address")
+	}
+	var addr Address
+	copy(addr[:], data[12:32])
+	return addr, nil
+}
+
+// decodeBool decodes a boolean from 32 bytes
+func decodeBool(data []byte) (bool, error) {
+	if len(data) < 32 {
+		return false, errors.New("insufficient data for bool")
+	}
+	return data[31] != 0, nil
+}`
+
+// strictValueDecodingTemplate replaces valueDecodingTemplate with --strict-decode,
+// rejecting the dirty padding valid ABI encoding never produces
+const strictValueDecodingTemplate = `// decodeAddress decodes an address from 32 bytes, whose first 12 bytes must be zero
+func decodeAddress(data []byte) (Address, error) {
+	if len(data) < 32 {
+		return Address{}, errors.New("insufficient data for address")
+	}
+	for _, b := range data[:12] {
+		if b != 0 {
+			return Address{}, errors.New("address has non-zero padding")
+		}
+	}
+	var addr Address
+	copy(addr[:], data[12:32])
+	return addr, nil
+}
+
+// decodeBool decodes a boolean from 32 bytes, which must encode 0 or 1
+func decodeBool(data []byte) (bool, error) {
+	if len(data) < 32 {
+		return false, errors.New("insufficient data for bool")
+	}
+	for _, b := range data[:31] {
+		if b != 0 {
+			return false, errors.New("bool value out of range")
+		}
+	}
+	if data[31] > 1 {
+		return false, errors.New("bool value out of range")
+	}
+	return data[31] == 1, nil
+}`
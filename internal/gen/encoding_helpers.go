@@ -43,8 +43,12 @@ func encodeInt256(val interface{}) ([]byte, error) {
 	result := make([]byte, 32)
 	switch v := val.(type) {
 	case *big.Int:
-		// Check if value fits in 256 bits (considering sign)
-		if v.BitLen() >= 256 {
+		// Check the value is within [-2^255, 2^255-1]
+		magnitude := new(big.Int).Set(v)
+		if v.Sign() < 0 {
+			magnitude.Neg(v).Sub(magnitude, big.NewInt(1))
+		}
+		if magnitude.BitLen() > 255 {
 			return nil, errors.New("value too large for int256")
 		}
 		
@@ -109,4 +113,207 @@ func encodeBytes(data []byte) ([]byte, error) {
 // encodeString encodes a string as dynamic bytes
 func encodeString(str string) ([]byte, error) {
 	return encodeBytes([]byte(str))
+}
+
+// encodeBytesArray encodes a bytes[] value: its length, the offset of every
+// element relative to the end of the length, then the elements
+func encodeBytesArray(elems [][]byte) ([]byte, error) {
+	result, err := encodeUint256(uint64(len(elems)))
+	if err != nil {
+		return nil, err
+	}
+
+	var tail []byte
+	for _, elem := range elems {
+		offset, err := encodeUint256(uint64(32*len(elems) + len(tail)))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, offset...)
+
+		data, err := encodeBytes(elem)
+		if err != nil {
+			return nil, err
+		}
+		tail = append(tail, data...)
+	}
+	return append(result, tail...), nil
+}
+
+// encodeFixedBytes encodes a bytesN value, right-padded to 32 bytes
+func encodeFixedBytes(data []byte) ([]byte, error) {
+	if len(data) > 32 {
+		return nil, fmt.Errorf("%d bytes too large for a fixed bytes value", len(data))
+	}
+	result := make([]byte, 32)
+	copy(result, data)
+	return result, nil
+}
+
+// encodeTuple lays out encoded values as an ABI tuple: static values in the
+// head, dynamic ones as an offset in the head, relative to the start of the
+// tuple, to their data in the tail
+func encodeTuple(values [][]byte, dynamic []bool) []byte {
+	headSize := 0
+	for i, value := range values {
+		if dynamic[i] {
+			headSize += 32
+		} else {
+			headSize += len(value)
+		}
+	}
+
+	var head, tail []byte
+	for i, value := range values {
+		if !dynamic[i] {
+			head = append(head, value...)
+			continue
+		}
+		offset, _ := encodeUint256(uint64(headSize + len(tail)))
+		head = append(head, offset...)
+		tail = append(tail, value...)
+	}
+	return append(head, tail...)
+}
+
+// encodeArray encodes n elements as a fixed-size array, which is laid out as a
+// tuple of its elements
+func encodeArray(n int, elem func(i int) ([]byte, error), dynamic bool) ([]byte, error) {
+	values := make([][]byte, n)
+	flags := make([]bool, n)
+	for i := range values {
+		data, err := elem(i)
+		if err != nil {
+			return nil, fmt.Errorf("encoding element %d: %w", i, err)
+		}
+		values[i], flags[i] = data, dynamic
+	}
+	return encodeTuple(values, flags), nil
+}
+
+// encodeSlice encodes n elements as a dynamic array: its length followed by
+// the elements laid out as a fixed-size array
+func encodeSlice(n int, elem func(i int) ([]byte, error), dynamic bool) ([]byte, error) {
+	data, err := encodeArray(n, elem, dynamic)
+	if err != nil {
+		return nil, err
+	}
+	length, _ := encodeUint256(uint64(n))
+	return append(length, data...), nil
+}
+
+// TupleEncoder is implemented by the generated structs, so that Pack and the
+// helpers built on it encode them, and slices and arrays of them, as ABI tuples
+type TupleEncoder interface {
+	EncodeTuple() ([]byte, error)
+	IsDynamicTuple() bool
+}
+
+// encodeArg encodes a single argument of the types accepted by Pack
+func encodeArg(arg any) ([]byte, error) {
+	switch v := arg.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, errors.New("encoding big.Int: nil value")
+		}
+		encode := encodeUint256
+		if v.Sign() < 0 {
+			encode = encodeInt256
+		}
+		data, err := encode(v)
+		if err != nil {
+			return nil, fmt.Errorf("encoding big.Int: %w", err)
+		}
+		return data, nil
+	case uint8, uint16, uint32, uint64:
+		data, err := encodeUint256(widenUint(v))
+		if err != nil {
+			return nil, fmt.Errorf("encoding %T: %w", v, err)
+		}
+		return data, nil
+	case int8, int16, int32, int64, int:
+		data, err := encodeInt256(widenInt(v))
+		if err != nil {
+			return nil, fmt.Errorf("encoding %T: %w", v, err)
+		}
+		return data, nil
+	case Address:
+		return encodeAddress(v)
+	case bool:
+		return encodeBool(v)
+	case [32]byte:
+		return v[:], nil
+	case Hash:
+		return v[:], nil
+	case string:
+		return encodeString(v)
+	case []byte:
+		return encodeBytes(v)
+	case []*big.Int:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, false)
+	case []uint64:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, false)
+	case []Address:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, false)
+	case []bool:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, false)
+	case [][32]byte:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, false)
+	case []string:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, true)
+	case TupleEncoder:
+		return v.EncodeTuple()
+	default:
+		if tuples, ok := tupleArray(arg); ok {
+			elem := func(i int) ([]byte, error) { return tuples.Index(i).Interface().(TupleEncoder).EncodeTuple() }
+			if tuples.Kind() == reflect.Slice {
+				return encodeSlice(tuples.Len(), elem, isDynamicArg(reflect.Zero(tuples.Type().Elem()).Interface()))
+			}
+			return encodeArray(tuples.Len(), elem, isDynamicArg(reflect.Zero(tuples.Type().Elem()).Interface()))
+		}
+		return nil, fmt.Errorf("unsupported argument type: %T", arg)
+	}
+}
+
+// tupleArray returns arg as a slice or array of generated structs, whose
+// types the argument type switch cannot name
+func tupleArray(arg any) (reflect.Value, bool) {
+	v := reflect.ValueOf(arg)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return v, false
+	}
+	if v.Type().Elem().Kind() != reflect.Struct {
+		return v, false
+	}
+	_, ok := reflect.Zero(v.Type().Elem()).Interface().(TupleEncoder)
+	return v, ok
+}
+
+// isDynamicArg reports whether an argument accepted by Pack is encoded behind an offset
+func isDynamicArg(arg any) bool {
+	switch v := arg.(type) {
+	case string, []byte, []*big.Int, []uint64, []Address, []bool, [][32]byte, []string:
+		return true
+	case TupleEncoder:
+		return v.IsDynamicTuple()
+	}
+	if tuples, ok := tupleArray(arg); ok {
+		return tuples.Kind() == reflect.Slice || isDynamicArg(reflect.Zero(tuples.Type().Elem()).Interface())
+	}
+	return false
+}
+
+// EncodeMulticall encodes calldatas, e.g. the outputs of Pack, as the bytes[]
+// argument of the common multicall(bytes[]) function. The result is the
+// calldata without the selector, to append to the multicall selector.
+func EncodeMulticall(calls ...HexData) HexData {
+	elems := make([][]byte, len(calls))
+	for i, call := range calls {
+		elems[i] = call.Bytes()
+	}
+
+	// A single dynamic argument, so its data starts right after its offset
+	offset, _ := encodeUint256(uint64(32))
+	encoded, _ := encodeBytesArray(elems)
+	return HexData("0x" + hex.EncodeToString(append(offset, encoded...)))
 }`
\ No newline at end of file
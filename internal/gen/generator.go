@@ -5,80 +5,504 @@ package gen
 import (
 	"fmt"
 	"go/format"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/otherview/solgen/internal/types"
 )
 
+// DefaultSingleFilePackage is the package name used in single-file mode when none is set
+const DefaultSingleFilePackage = "bindings"
+
+// generatedHeader is the first line of every file written by solgen
+const generatedHeader = "// Code generated by github.com/otherview/solgen. DO NOT EDIT."
+
+// DefaultHeader follows the generated-by marker when no header is set
+const DefaultHeader = "// SPDX-License-Identifier: MIT"
+
+// Options holds optional settings for code generation
+type Options struct {
+	SingleFile   bool   // Generate all contracts into a single file and package
+	PackageName  string // Package name used in single-file mode
+	WithBind     bool   // Generate go-ethereum interop helpers
+	Hooks        Hooks  // Optional progress callbacks for embedders
+	Manifest     string // Optional manifest file, relative to the output directory, listing the generated files
+	RuntimeOnly  bool   // Omit creation bytecode and constructor helpers, keeping DeployedBytecode
+	WithEqual    bool   // Generate Equal and IsZero methods on decoded structs
+	Clean        bool   // Remove previously generated files from the output directory first
+	NoFormat     bool   // Write the template output verbatim, skipping gofmt, for fast regeneration or to debug templates
+	WithKeccak   bool   // Generate a dependency-free Keccak256 and SelectorOf
+	EmbedABI     bool   // Write the ABI to a JSON file next to the code and go:embed it
+	TinyGo       bool   // Decode arrays with typed decoders instead of interface{} values, for TinyGo
+	WithFound    bool   // Generate DecodeFound on mapping getters, reporting whether the value is set
+	WithProxy    bool   // Generate ImplementationSlot and AddressFromStorage for ERC-1967 proxies
+	Qualified    bool   // Prefix decode errors with the contract and member name, e.g. "simpletoken.transfer: ..."
+	Prune        bool   // Omit structs no method, event, error or constructor refers to
+	NativeTypes  bool   // Declare Address and Hash as aliases of go-ethereum's common types, requires WithBind
+	StrictDecode bool   // Reject addresses with non-zero padding and bools other than 0 and 1 while decoding
+	Header       string // Written after the generated-by marker instead of DefaultHeader, lines are made comments
+
+	// RuntimePackage is the import path of a package receiving the shared
+	// runtime, which contract packages then import instead of declaring it
+	RuntimePackage string
+}
+
+// Hooks receives progress notifications while contracts are generated
+type Hooks interface {
+	// OnContract is called for each contract, in order, before its code is
+	// written into the given package. Contract packages are rendered
+	// concurrently beforehand, so it does not mark the start of rendering.
+	OnContract(name, packageName string)
+	// OnFileWritten is called after a generated file has been written
+	OnFileWritten(path string)
+}
+
 // Generator handles Go code generation from parsed contracts
 type Generator struct {
 	outputDir string
+	options   Options
+
+	checking bool     // Compare with the files on disk instead of writing
+	stale    []string // Files found out of date while checking
 }
 
 // NewGenerator creates a new code generator
 func NewGenerator(outputDir string) *Generator {
+	return NewGeneratorWithOptions(outputDir, Options{})
+}
+
+// NewGeneratorWithOptions creates a new code generator with the given options
+func NewGeneratorWithOptions(outputDir string, options Options) *Generator {
+	if options.PackageName == "" {
+		options.PackageName = DefaultSingleFilePackage
+	}
+	options.Header = commentHeader(options.Header)
 	return &Generator{
 		outputDir: outputDir,
+		options:   options,
 	}
 }
 
 // Generate creates Go packages for all contracts
 func (g *Generator) Generate(contracts []*types.Contract) error {
-	// Ensure output directory exists
-	if err := os.MkdirAll(g.outputDir, 0755); err != nil {
-		return fmt.Errorf("creating output directory: %w", err)
+	_, err := g.GenerateWithManifest(contracts)
+	return err
+}
+
+// Check regenerates the code for all contracts in memory and returns the
+// files on disk that are missing or differ from it, without writing anything
+func (g *Generator) Check(contracts []*types.Contract) ([]string, error) {
+	g.checking = true
+	g.stale = nil
+	defer func() { g.checking = false }()
+
+	if _, err := g.GenerateWithManifest(contracts); err != nil {
+		return nil, err
+	}
+	return g.stale, nil
+}
+
+// GenerateWithManifest creates Go packages for all contracts and returns the
+// paths of the written files, in generation order
+func (g *Generator) GenerateWithManifest(contracts []*types.Contract) ([]string, error) {
+	if !g.checking {
+		// Ensure output directory exists
+		if err := os.MkdirAll(g.outputDir, 0755); err != nil {
+			return nil, fmt.Errorf("creating output directory: %w", err)
+		}
+	}
+
+	if g.options.Clean && !g.checking {
+		if err := g.clean(); err != nil {
+			return nil, fmt.Errorf("cleaning output directory: %w", err)
+		}
 	}
 
-	// Generate package for each contract
-	for _, contract := range contracts {
-		if err := g.generateContractPackage(contract); err != nil {
-			return fmt.Errorf("generating package for contract %s: %w", contract.Name, err)
+	var written []string
+	if g.options.RuntimePackage != "" {
+		filePath, err := g.generateRuntimePackage()
+		if err != nil {
+			return nil, fmt.Errorf("generating runtime package %s: %w", g.options.RuntimePackage, err)
 		}
+		written = append(written, filePath)
 	}
 
+	if g.options.SingleFile {
+		filePath, err := g.generateSingleFile(contracts)
+		if err != nil {
+			return nil, fmt.Errorf("generating single file package %s: %w", g.options.PackageName, err)
+		}
+		written = append(written, filePath)
+		for _, contract := range contracts {
+			abiPath, err := g.writeABIFile(g.options.PackageName, contract)
+			if err != nil {
+				return nil, fmt.Errorf("generating single file package %s: %w", g.options.PackageName, err)
+			}
+			if abiPath != "" {
+				written = append(written, abiPath)
+			}
+		}
+	} else {
+		// Render the packages concurrently, then write them in order
+		files := g.renderContractPackages(contracts)
+		for i, contract := range contracts {
+			g.onContract(contract.Name, contract.PackageName)
+			if files[i].err != nil {
+				return nil, fmt.Errorf("generating package for contract %s: %w", contract.Name, files[i].err)
+			}
+			if err := g.writeFile(files[i].path, files[i].content); err != nil {
+				return nil, fmt.Errorf("generating package for contract %s: writing file: %w", contract.Name, err)
+			}
+			written = append(written, files[i].path)
+
+			abiPath, err := g.writeABIFile(packageDir(contract), contract)
+			if err != nil {
+				return nil, fmt.Errorf("generating package for contract %s: %w", contract.Name, err)
+			}
+			if abiPath != "" {
+				written = append(written, abiPath)
+			}
+		}
+	}
+
+	if g.options.Manifest != "" {
+		if err := g.writeManifest(written); err != nil {
+			return nil, fmt.Errorf("writing manifest: %w", err)
+		}
+	}
+
+	return written, nil
+}
+
+// clean removes the Go files carrying the solgen header from the output
+// directory, along with the files they embed, such as the ABI written by
+// EmbedABI. Directories left empty by doing so are removed, other directories
+// and hand-written files are kept.
+func (g *Generator) clean() error {
+	var cleaned []string
+	err := filepath.WalkDir(g.outputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".go" {
+			return nil
+		}
+		generated, err := isGeneratedFile(path)
+		if err != nil || !generated {
+			return err
+		}
+		embedded, err := embeddedFiles(path)
+		if err != nil {
+			return err
+		}
+		for _, name := range embedded {
+			if err := os.Remove(filepath.Join(filepath.Dir(path), name)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		cleaned = append(cleaned, filepath.Dir(path))
+		return os.Remove(path)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Remove the cleaned directories, and their parents, once they are empty
+	root := filepath.Clean(g.outputDir)
+	for _, dir := range cleaned {
+		for ; dir != root; dir = filepath.Dir(dir) {
+			entries, err := os.ReadDir(dir)
+			if os.IsNotExist(err) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if len(entries) > 0 {
+				break
+			}
+			if err := os.Remove(dir); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
-// generateContractPackage creates a single Go package for a contract
-func (g *Generator) generateContractPackage(contract *types.Contract) error {
-	// Create package directory
-	pkgDir := filepath.Join(g.outputDir, contract.PackageName)
-	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+// isGeneratedFile reports whether the file starts with the solgen header
+func isGeneratedFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	header := make([]byte, len(generatedHeader))
+	if _, err := io.ReadFull(f, header); err != nil {
+		// Shorter than the header, so not generated
+		return false, nil
+	}
+	return string(header) == generatedHeader, nil
+}
+
+// embeddedFiles returns the names of the files a generated file embeds with
+// go:embed directives, limited to files next to it
+func embeddedFiles(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if !strings.HasPrefix(line, "//go:embed ") {
+			continue
+		}
+		for _, name := range strings.Fields(strings.TrimPrefix(line, "//go:embed ")) {
+			if name == filepath.Base(name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// writeManifest writes the generated file paths, relative to the output directory, one per line
+func (g *Generator) writeManifest(written []string) error {
+	var buf strings.Builder
+	for _, filePath := range written {
+		rel, err := filepath.Rel(g.outputDir, filePath)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(filepath.ToSlash(rel))
+		buf.WriteString("\n")
+	}
+
+	return g.writeFile(filepath.Join(g.outputDir, g.options.Manifest), []byte(buf.String()))
+}
+
+// writeFile writes a generated file, creating its directory, and notifies the
+// hooks. When checking, the file is instead compared with the one on disk and
+// recorded as stale if they differ.
+func (g *Generator) writeFile(filePath string, content []byte) error {
+	if g.checking {
+		existing, err := os.ReadFile(filePath)
+		if err != nil || normalizeContent(string(existing)) != normalizeContent(string(content)) {
+			g.stale = append(g.stale, filePath)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
 		return fmt.Errorf("creating package directory: %w", err)
 	}
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return err
+	}
+	g.onFileWritten(filePath)
+	return nil
+}
+
+// normalizeContent normalizes line endings and trailing whitespace for comparison
+func normalizeContent(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// abiFileName returns the name of the ABI file embedded with EmbedABI, unique
+// per contract when several share the single-file package
+func (g *Generator) abiFileName(contract *types.Contract) string {
+	if g.options.SingleFile {
+		return contract.PackageName + ".abi.json"
+	}
+	return "abi.json"
+}
+
+// writeABIFile writes the ABI of the contract next to its code in the given
+// package directory when EmbedABI is set, and returns the written path
+func (g *Generator) writeABIFile(dir string, contract *types.Contract) (string, error) {
+	if !g.options.EmbedABI {
+		return "", nil
+	}
+	filePath := filepath.Join(g.outputDir, filepath.FromSlash(dir), g.abiFileName(contract))
+	if err := g.writeFile(filePath, []byte(contract.ABIJson)); err != nil {
+		return "", fmt.Errorf("writing ABI file: %w", err)
+	}
+	return filePath, nil
+}
+
+// packageDir returns the slash-separated directory of a contract package,
+// relative to the output directory
+func packageDir(contract *types.Contract) string {
+	if contract.Dir != "" {
+		return contract.Dir
+	}
+	return contract.PackageName
+}
+
+// contractFile is a rendered contract package file waiting to be written
+type contractFile struct {
+	path    string
+	content []byte
+	err     error
+}
+
+// renderContractPackages renders the package file of every contract, using up
+// to GOMAXPROCS workers. Results are returned in the order of the contracts.
+func (g *Generator) renderContractPackages(contracts []*types.Contract) []contractFile {
+	files := make([]contractFile, len(contracts))
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(contracts) {
+		workers = len(contracts)
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				files[i] = g.renderContractPackage(contracts[i])
+			}
+		}()
+	}
+	for i := range contracts {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return files
+}
+
+// renderContractPackage renders and formats the Go package file of a contract
+func (g *Generator) renderContractPackage(contract *types.Contract) contractFile {
+	filePath := filepath.Join(g.outputDir, filepath.FromSlash(packageDir(contract)), contract.PackageName+".go")
 
-	// Generate the main package file
-	filePath := filepath.Join(pkgDir, contract.PackageName+".go")
-	
 	// Render template
 	content, err := g.renderContract(contract)
 	if err != nil {
-		return fmt.Errorf("rendering contract template: %w", err)
+		return contractFile{err: fmt.Errorf("rendering contract template: %w", err)}
+	}
+
+	if g.options.RuntimePackage != "" {
+		content, err = g.useRuntimePackage(content, contract.PackageName+".go")
+		if err != nil {
+			return contractFile{err: fmt.Errorf("importing runtime package: %w", err)}
+		}
 	}
 
 	// Format the generated Go code
+	return contractFile{path: filePath, content: g.formatSource(content, contract.Name)}
+}
+
+// commentHeader turns a header into Go line comments, keeping lines that
+// already are comments. An empty header yields DefaultHeader.
+func commentHeader(header string) string {
+	header = strings.TrimRight(header, " \t\r\n")
+	if header == "" {
+		return DefaultHeader
+	}
+
+	lines := strings.Split(strings.ReplaceAll(header, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		switch {
+		case strings.HasPrefix(line, "//"):
+			lines[i] = line
+		case line == "":
+			lines[i] = "//"
+		default:
+			lines[i] = "// " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatSource formats generated code. The code is returned unformatted when
+// formatting was turned off, and with a warning when formatting fails.
+func (g *Generator) formatSource(content, name string) []byte {
+	if g.options.NoFormat {
+		return []byte(content)
+	}
 	formatted, err := format.Source([]byte(content))
 	if err != nil {
 		// If formatting fails, write unformatted code for debugging
-		fmt.Printf("Warning: failed to format generated code for %s: %v\n", contract.Name, err)
-		formatted = []byte(content)
+		fmt.Printf("Warning: failed to format generated code for %s: %v\n", name, err)
+		return []byte(content)
+	}
+	return formatted
+}
+
+// hasBytecode reports whether the bytecode is present, matching the template conditions
+func hasBytecode(bytecode types.HexData) bool {
+	return bytecode != "" && bytecode.Hex() != "0x"
+}
+
+// hasPayableMethod reports whether the contract has a payable method, which gets a Transact helper with WithBind
+func hasPayableMethod(contract *types.Contract) bool {
+	for _, method := range contract.Methods {
+		if method.Payable {
+			return true
+		}
 	}
+	return false
+}
 
-	// Write to file
-	if err := os.WriteFile(filePath, formatted, 0644); err != nil {
-		return fmt.Errorf("writing file: %w", err)
+// onContract notifies the hooks, if any, that a contract is being generated
+func (g *Generator) onContract(name, packageName string) {
+	if g.options.Hooks != nil {
+		g.options.Hooks.OnContract(name, packageName)
 	}
+}
 
-	return nil
+// onFileWritten notifies the hooks, if any, that a file has been written
+func (g *Generator) onFileWritten(path string) {
+	if g.options.Hooks != nil {
+		g.options.Hooks.OnFileWritten(path)
+	}
+}
+
+// parseContractTemplate parses the contract template once. Templates are safe
+// for concurrent execution, so the result is shared by all renders.
+var parseContractTemplate = sync.OnceValues(func() (*template.Template, error) {
+	return template.New("contract").Funcs(templateFuncs()).Parse(contractTemplate)
+})
+
+// generatedContract returns the contract as generated with the options, which
+// may leave out its deployment code or unreferenced structs
+func (g *Generator) generatedContract(contract *types.Contract) *types.Contract {
+	if g.options.RuntimeOnly {
+		contract = runtimeOnlyContract(contract)
+	}
+	if g.options.Prune {
+		contract = prunedContract(contract)
+	}
+	return contract
 }
 
 // renderContract renders the Go code for a contract using templates
 func (g *Generator) renderContract(contract *types.Contract) (string, error) {
-	tmpl, err := template.New("contract").Funcs(templateFuncs()).Parse(contractTemplate)
+	contract = g.generatedContract(contract)
+
+	tmpl, err := parseContractTemplate()
 	if err != nil {
 		return "", fmt.Errorf("parsing template: %w", err)
 	}
@@ -87,6 +511,8 @@ func (g *Generator) renderContract(contract *types.Contract) (string, error) {
 	data := &TemplateData{
 		Contract: contract,
 		Imports:  g.calculateImports(contract),
+		Options:  g.options,
+		ABIFile:  g.abiFileName(contract),
 	}
 
 	if err := tmpl.Execute(&buf, data); err != nil {
@@ -96,6 +522,81 @@ func (g *Generator) renderContract(contract *types.Contract) (string, error) {
 	return buf.String(), nil
 }
 
+// runtimeOnlyContract returns a copy of the contract without its creation
+// bytecode and constructor, so no deployment code is generated for it
+func runtimeOnlyContract(contract *types.Contract) *types.Contract {
+	runtimeOnly := *contract
+	runtimeOnly.Bytecode = ""
+	runtimeOnly.Constructor = nil
+	return &runtimeOnly
+}
+
+// prunedContract returns a copy of the contract without the structs that its
+// methods, events, errors and constructor do not refer to, directly or through
+// the fields of other structs
+func prunedContract(contract *types.Contract) *types.Contract {
+	structs := make(map[string]types.Struct, len(contract.Structs))
+	for _, s := range contract.Structs {
+		structs[s.Name] = s
+	}
+
+	used := make(map[string]bool)
+	var markType func(goType types.GoType)
+	markType = func(goType types.GoType) {
+		name := elemTypeName(goType.TypeName)
+		s, ok := structs[name]
+		if !ok || used[name] {
+			return
+		}
+		used[name] = true
+		for _, field := range s.Fields {
+			markType(field.Type)
+		}
+	}
+	markParams := func(params []types.Parameter) {
+		for _, param := range params {
+			markType(param.Type)
+		}
+	}
+
+	for _, method := range contract.Methods {
+		markParams(method.Inputs)
+		markParams(method.Outputs)
+	}
+	for _, event := range contract.Events {
+		markParams(event.Inputs)
+	}
+	for _, contractError := range contract.Errors {
+		markParams(contractError.Inputs)
+	}
+	if contract.Constructor != nil {
+		markParams(contract.Constructor.Inputs)
+	}
+
+	if len(used) == len(contract.Structs) {
+		return contract
+	}
+	pruned := *contract
+	pruned.Structs = nil
+	for _, s := range contract.Structs {
+		if used[s.Name] {
+			pruned.Structs = append(pruned.Structs, s)
+		}
+	}
+	return &pruned
+}
+
+// templateImports are imported by the contract template itself
+var templateImports = map[string]bool{
+	"encoding/hex": true,
+	"errors":       true,
+	"fmt":          true,
+	"io":           true,
+	"math/big":     true,
+	"reflect":      true,
+	"strings":      true,
+}
+
 // calculateImports determines which imports are needed for the contract
 func (g *Generator) calculateImports(contract *types.Contract) []string {
 	importSet := make(map[string]bool)
@@ -153,14 +654,58 @@ func (g *Generator) calculateImports(contract *types.Contract) []string {
 		}
 	}
 
+	// Check standalone structs, whose fields may use type map overrides
+	for _, s := range contract.Structs {
+		for _, field := range s.Fields {
+			checkGoType(field.Type)
+		}
+	}
+
 	if needsBigInt {
 		importSet["math/big"] = true
 	}
 
-	// Convert to sorted slice
+	if g.options.WithBind {
+		importSet["github.com/ethereum/go-ethereum"] = true
+		importSet["github.com/ethereum/go-ethereum/common"] = true
+		// EncodeArgs, the abi package is also used by the Deploy and Transact helpers
+		importSet["github.com/ethereum/go-ethereum/accounts/abi"] = true
+		importSet["github.com/ethereum/go-ethereum/crypto"] = true
+		if len(contract.Methods) > 0 {
+			// Call helpers
+			importSet["context"] = true
+		}
+		if hasBytecode(contract.Bytecode) || hasPayableMethod(contract) {
+			// Deploy and Transact helpers
+			importSet["github.com/ethereum/go-ethereum/accounts/abi/bind"] = true
+			importSet["github.com/ethereum/go-ethereum/core/types"] = true
+		}
+		if hasBytecode(contract.DeployedBytecode) {
+			// VerifyDeployedCode
+			importSet["context"] = true
+			importSet["github.com/ethereum/go-ethereum/accounts/abi/bind"] = true
+		}
+		if len(contract.Events) > 0 {
+			// DecodeReceiptLogs
+			importSet["github.com/ethereum/go-ethereum/core/types"] = true
+		}
+	}
+
+	if g.options.EmbedABI {
+		importSet["embed"] = true
+	}
+	if g.options.WithKeccak {
+		importSet["encoding/binary"] = true
+		importSet["math/bits"] = true
+	}
+
+	// Convert to sorted slice, leaving out the imports the template always
+	// declares so the output is valid even when it is not formatted
 	var imports []string
 	for imp := range importSet {
-		imports = append(imports, imp)
+		if !templateImports[imp] {
+			imports = append(imports, imp)
+		}
 	}
 	
 	sort.Strings(imports)
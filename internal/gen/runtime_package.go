@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: MIT
+
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// runtimeSource returns the shared runtime declarations as Go source, without package clause
+func runtimeSource(options Options) string {
+	source := valueTypesTemplate
+	if options.NativeTypes {
+		source = nativeValueTypesTemplate
+	}
+	source += "\n\n" + runtimeTemplate
+	if options.StrictDecode {
+		source += "\n\n" + strictValueDecodingTemplate
+	} else {
+		source += "\n\n" + valueDecodingTemplate
+	}
+	if options.TinyGo {
+		source += "\n\n" + typedArrayDecodingTemplate
+	} else {
+		source += "\n\n" + arrayDecodingTemplate
+	}
+	if options.WithBind && !options.NativeTypes {
+		source += "\n\n" + bindConversionsTemplate
+	}
+	if options.WithBind {
+		source += "\n\n" + bindRuntimeTemplate
+	}
+	if options.WithKeccak {
+		source += "\n\n" + keccakRuntimeTemplate
+	}
+	if options.WithProxy {
+		source += "\n\n" + proxyRuntimeTemplate
+	}
+	return source
+}
+
+// runtimeImports returns the imports used by the shared runtime declarations
+func runtimeImports(options Options) []string {
+	imports := []string{"encoding/hex", "errors", "fmt", "io", "math/big", "reflect", "strings"}
+	if options.WithBind {
+		imports = append(imports, "github.com/ethereum/go-ethereum", "github.com/ethereum/go-ethereum/accounts/abi",
+			"github.com/ethereum/go-ethereum/common", "github.com/ethereum/go-ethereum/crypto")
+	}
+	if options.WithKeccak {
+		imports = append(imports, "encoding/binary", "math/bits")
+	}
+	return imports
+}
+
+// runtimePackageName returns the package name of the --runtime-package import path
+func (g *Generator) runtimePackageName() (string, error) {
+	name := path.Base(g.options.RuntimePackage)
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("invalid runtime package %q: %q is not a valid package name", g.options.RuntimePackage, name)
+	}
+	return name, nil
+}
+
+// generateRuntimePackage writes the shared runtime into its own package and returns the written file path
+func (g *Generator) generateRuntimePackage() (string, error) {
+	name, err := g.runtimePackageName()
+	if err != nil {
+		return "", err
+	}
+
+	content, err := g.renderRuntimePackage(name)
+	if err != nil {
+		return "", err
+	}
+
+	formatted := g.formatSource(content, "package "+name)
+
+	filePath := filepath.Join(g.outputDir, name, name+".go")
+	if err := g.writeFile(filePath, formatted); err != nil {
+		return "", fmt.Errorf("writing file: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// renderRuntimePackage renders the shared runtime as a standalone package.
+// Unexported helpers are exported so that contract packages can alias them.
+func (g *Generator) renderRuntimePackage(name string) (string, error) {
+	var buf strings.Builder
+	buf.WriteString(generatedHeader + "\n")
+	buf.WriteString(g.options.Header + "\n")
+	buf.WriteString("// Shared runtime for solgen contract bindings\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", name)
+	buf.WriteString("import (\n")
+	for _, imp := range runtimeImports(g.options) {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	buf.WriteString(")\n\n")
+	buf.WriteString(runtimeSource(g.options))
+	buf.WriteString("\n")
+
+	src := buf.String()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name+".go", src, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("parsing runtime package: %w", err)
+	}
+
+	rc := &renderedContract{src: src, fset: fset, file: file}
+	rc.edits = renameEdits(rc, func(name string) (string, bool) {
+		if ast.IsExported(name) {
+			return "", false
+		}
+		return exportedRuntimeName(name), true
+	})
+
+	var out strings.Builder
+	pos := 0
+	for _, edit := range rc.edits {
+		out.WriteString(src[pos:edit.start])
+		out.WriteString(edit.text)
+		pos = edit.end
+	}
+	out.WriteString(src[pos:])
+	return out.String(), nil
+}
+
+// exportedRuntimeName returns the exported name of a runtime declaration
+func exportedRuntimeName(name string) string {
+	if ast.IsExported(name) {
+		return name
+	}
+	return titleCase(strings.TrimLeft(name, "_"))
+}
+
+// useRuntimePackage rewrites a rendered contract to import the shared runtime
+// package instead of declaring the runtime itself. The runtime names stay
+// available in the contract package as aliases, so the rest of the rendered
+// code is untouched.
+func (g *Generator) useRuntimePackage(content, filename string) (string, error) {
+	kinds, err := runtimeDeclKinds(g.options)
+	if err != nil {
+		return "", err
+	}
+	runtimeNames := make(map[string]bool)
+	for name := range kinds {
+		runtimeNames[name] = true
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, content, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("parsing rendered contract: %w", err)
+	}
+	rc := &renderedContract{src: content, fset: fset, file: file}
+
+	var importDecl ast.Decl
+	var decls []ast.Decl
+	for _, decl := range file.Decls {
+		if isImportDecl(decl) {
+			if importDecl == nil {
+				importDecl = decl
+			}
+			continue
+		}
+		if !isRuntimeDecl(decl, runtimeNames) {
+			decls = append(decls, decl)
+		}
+	}
+	if importDecl == nil {
+		return "", fmt.Errorf("rendered contract has no imports")
+	}
+
+	imports, err := g.contractImports([]*ast.File{file}, decls)
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	buf.WriteString(content[:rc.offset(importDecl.Pos())])
+	writeImports(&buf, imports)
+	if err := g.writeRuntimeAliases(&buf, kinds); err != nil {
+		return "", err
+	}
+	for _, decl := range decls {
+		buf.WriteString(rc.declSource(decl))
+		buf.WriteString("\n\n")
+	}
+	return buf.String(), nil
+}
+
+// contractImports returns the imports of the given files still needed by decls
+// once the runtime lives in its own package, plus the runtime package itself.
+// Imports other than the runtime's own are always kept.
+func (g *Generator) contractImports(files []*ast.File, decls []ast.Decl) ([]string, error) {
+	used := make(map[string]bool)
+	for _, decl := range decls {
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+					used[ident.Name] = true
+				}
+			}
+			return true
+		})
+	}
+
+	runtimeOnly := make(map[string]bool)
+	for _, imp := range runtimeImports(g.options) {
+		runtimeOnly[imp] = true
+	}
+
+	importSet := map[string]bool{g.options.RuntimePackage: true}
+	for _, file := range files {
+		for _, imp := range file.Imports {
+			importPath, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid import: %w", err)
+			}
+			if runtimeOnly[importPath] && !used[importName(importPath)] {
+				continue
+			}
+			importSet[importPath] = true
+		}
+	}
+
+	var imports []string
+	for imp := range importSet {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+// importName returns the package name of one of the runtime imports
+func importName(importPath string) string {
+	if importPath == "github.com/ethereum/go-ethereum" {
+		return "ethereum"
+	}
+	return path.Base(importPath)
+}
+
+// writeImports writes a sorted import block
+func writeImports(buf *strings.Builder, imports []string) {
+	buf.WriteString("import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(buf, "\t%q\n", imp)
+	}
+	buf.WriteString(")\n\n")
+}
+
+// writeRuntimeAliases writes the declarations that make the runtime package
+// names available under their usual names in a contract package
+func (g *Generator) writeRuntimeAliases(buf *strings.Builder, kinds map[string]ast.ObjKind) error {
+	pkg, err := g.runtimePackageName()
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Functions are aliased as variables, which keeps them callable unchanged
+	groups := []struct {
+		keyword string
+		kinds   []ast.ObjKind
+	}{
+		{"type", []ast.ObjKind{ast.Typ}},
+		{"const", []ast.ObjKind{ast.Con}},
+		{"var", []ast.ObjKind{ast.Var, ast.Fun}},
+	}
+
+	fmt.Fprintf(buf, "// Shared runtime declarations from %s\n", g.options.RuntimePackage)
+	for _, group := range groups {
+		var specs []string
+		for _, name := range names {
+			for _, kind := range group.kinds {
+				if kinds[name] == kind {
+					specs = append(specs, fmt.Sprintf("%s = %s.%s", name, pkg, exportedRuntimeName(name)))
+				}
+			}
+		}
+		if len(specs) == 0 {
+			continue
+		}
+		fmt.Fprintf(buf, "%s (\n", group.keyword)
+		for _, spec := range specs {
+			fmt.Fprintf(buf, "\t%s\n", spec)
+		}
+		buf.WriteString(")\n\n")
+	}
+	return nil
+}
@@ -0,0 +1,491 @@
+// SPDX-License-Identifier: MIT
+
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/otherview/solgen/internal/types"
+)
+
+// renderedContract holds a parsed contract rendering used to assemble a single file
+type renderedContract struct {
+	contract *types.Contract
+	src      string
+	fset     *token.FileSet
+	file     *ast.File
+	edits    []sourceEdit
+}
+
+// sourceEdit replaces the source bytes in [start, end) with text
+type sourceEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// generateSingleFile writes all contracts into a single Go file and package and returns its path
+func (g *Generator) generateSingleFile(contracts []*types.Contract) (string, error) {
+	content, err := g.renderSingleFile(contracts)
+	if err != nil {
+		return "", err
+	}
+
+	formatted := g.formatSource(content, "package "+g.options.PackageName)
+
+	filePath := filepath.Join(g.outputDir, g.options.PackageName, g.options.PackageName+".go")
+	if err := g.writeFile(filePath, formatted); err != nil {
+		return "", fmt.Errorf("writing file: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// renderSingleFile renders every contract and merges them into one source file.
+// Runtime declarations (Address, HexData, the ABI helpers, ...) are emitted once,
+// while each contract-scoped declaration is prefixed with its contract name so
+// that identically named types from different contracts do not collide.
+func (g *Generator) renderSingleFile(contracts []*types.Contract) (string, error) {
+	if len(contracts) == 0 {
+		return "", fmt.Errorf("no contracts to generate")
+	}
+
+	// Shared structs are found among the structs that are generated
+	generated := make([]*types.Contract, len(contracts))
+	for i, contract := range contracts {
+		generated[i] = g.generatedContract(contract)
+	}
+	contracts = generated
+
+	runtimeNames, err := runtimeDeclNames()
+	if err != nil {
+		return "", err
+	}
+
+	sharedDecls := sharedStructDecls(contracts, runtimeNames)
+	unscoped := make(map[string]bool)
+	for name := range runtimeNames {
+		unscoped[name] = true
+	}
+	for name := range sharedDecls {
+		unscoped[name] = true
+	}
+
+	importSet := make(map[string]bool)
+	var rendered []*renderedContract
+	for _, contract := range contracts {
+		g.onContract(contract.Name, g.options.PackageName)
+		content, err := g.renderContract(contract)
+		if err != nil {
+			return "", fmt.Errorf("rendering contract %s: %w", contract.Name, err)
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, contract.PackageName+".go", content, parser.ParseComments)
+		if err != nil {
+			return "", fmt.Errorf("parsing rendered contract %s: %w", contract.Name, err)
+		}
+
+		for _, imp := range file.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				return "", fmt.Errorf("invalid import in contract %s: %w", contract.Name, err)
+			}
+			importSet[path] = true
+		}
+
+		rc := &renderedContract{contract: contract, src: content, fset: fset, file: file}
+		rc.edits = contractScopeRenames(rc, titleCase(contract.Name), unscoped)
+		rendered = append(rendered, rc)
+	}
+
+	var names []string
+	for _, rc := range rendered {
+		names = append(names, rc.contract.Name)
+	}
+
+	var buf strings.Builder
+	buf.WriteString(generatedHeader + "\n")
+	buf.WriteString(g.options.Header + "\n")
+	solcVersion := contracts[0].SolcVersion
+	if solcVersion == "" {
+		solcVersion = "unknown"
+	}
+	fmt.Fprintf(&buf, "// Contracts: %s (solc %s)\n\n", strings.Join(names, ", "), solcVersion)
+	fmt.Fprintf(&buf, "package %s\n\n", g.options.PackageName)
+
+	if g.options.RuntimePackage != "" {
+		if err := g.writeSingleFileRuntimeImport(&buf, rendered, runtimeNames); err != nil {
+			return "", err
+		}
+	} else {
+		var imports []string
+		for imp := range importSet {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		writeImports(&buf, imports)
+
+		// Shared runtime is taken from the first contract, it is identical in all of them
+		for _, decl := range rendered[0].file.Decls {
+			if isImportDecl(decl) || !isRuntimeDecl(decl, runtimeNames) {
+				continue
+			}
+			buf.WriteString(rendered[0].declSource(decl))
+			buf.WriteString("\n\n")
+		}
+	}
+
+	// Shared structs are declared by the first contract defining them
+	sharedOwners := make(map[string]string)
+	for _, rc := range rendered {
+		fmt.Fprintf(&buf, "// %s bindings (%s)\n\n", rc.contract.Name, rc.contract.SourceFile)
+		for _, decl := range rc.file.Decls {
+			if isImportDecl(decl) || isRuntimeDecl(decl, runtimeNames) {
+				continue
+			}
+			if structName, ok := sharedStruct(decl, sharedDecls); ok {
+				if owner, seen := sharedOwners[structName]; seen && owner != rc.contract.Name {
+					continue
+				}
+				sharedOwners[structName] = rc.contract.Name
+			}
+			buf.WriteString(rc.declSource(decl))
+			buf.WriteString("\n\n")
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// writeSingleFileRuntimeImport writes the imports and runtime aliases of a
+// single file whose runtime lives in the --runtime-package
+func (g *Generator) writeSingleFileRuntimeImport(buf *strings.Builder, rendered []*renderedContract, runtimeNames map[string]bool) error {
+	var files []*ast.File
+	var decls []ast.Decl
+	for _, rc := range rendered {
+		files = append(files, rc.file)
+		for _, decl := range rc.file.Decls {
+			if !isImportDecl(decl) && !isRuntimeDecl(decl, runtimeNames) {
+				decls = append(decls, decl)
+			}
+		}
+	}
+
+	imports, err := g.contractImports(files, decls)
+	if err != nil {
+		return err
+	}
+	writeImports(buf, imports)
+
+	kinds, err := runtimeDeclKinds(g.options)
+	if err != nil {
+		return err
+	}
+	return g.writeRuntimeAliases(buf, kinds)
+}
+
+// runtimeDeclNames returns the names of all package-level runtime declarations
+func runtimeDeclNames() (map[string]bool, error) {
+	names := make(map[string]bool)
+	for _, tinyGo := range []bool{false, true} {
+		kinds, err := runtimeDeclKinds(Options{WithBind: true, WithKeccak: true, WithProxy: true, TinyGo: tinyGo})
+		if err != nil {
+			return nil, err
+		}
+		for name := range kinds {
+			names[name] = true
+		}
+	}
+	return names, nil
+}
+
+// runtimeDeclKinds returns the kind of every package-level runtime declaration, by name
+func runtimeDeclKinds(options Options) (map[string]ast.ObjKind, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "runtime.go", "package runtime\n\n"+runtimeSource(options), 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing runtime template: %w", err)
+	}
+
+	kinds := make(map[string]ast.ObjKind)
+	for name, obj := range file.Scope.Objects {
+		kinds[name] = obj.Kind
+	}
+	return kinds, nil
+}
+
+// sharedStructDecls returns the declarations of the standalone structs that
+// several contracts define with the same name and shape, keyed by declared
+// name (the type and its decoder and encoder) to the struct name. A single
+// file declares these once, unprefixed, while same-named structs of different
+// shapes keep their contract prefix.
+func sharedStructDecls(contracts []*types.Contract, runtimeNames map[string]bool) map[string]string {
+	defs := make(map[string][]types.Struct)
+	scoped := make(map[string]bool)
+	for _, contract := range contracts {
+		for _, s := range contract.Structs {
+			defs[s.Name] = append(defs[s.Name], s)
+			scoped[s.Name] = true
+		}
+		for _, e := range contract.Enums {
+			scoped[e.Name] = true
+		}
+	}
+
+	shared := make(map[string]bool)
+	for name, structs := range defs {
+		if len(structs) < 2 || runtimeNames[name] {
+			continue
+		}
+		same := true
+		for _, s := range structs[1:] {
+			if !reflect.DeepEqual(s, structs[0]) {
+				same = false
+				break
+			}
+		}
+		shared[name] = same
+	}
+
+	// A struct is only shared when every contract-scoped type it references is
+	// shared too, otherwise its fields would point at a prefixed type
+	for changed := true; changed; {
+		changed = false
+		for name, ok := range shared {
+			if !ok {
+				continue
+			}
+			for _, field := range defs[name][0].Fields {
+				elem := elemTypeName(field.Type.TypeName)
+				if scoped[elem] && !shared[elem] {
+					shared[name] = false
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	decls := make(map[string]string)
+	for name, ok := range shared {
+		if ok {
+			decls[name] = name
+			decls["decode"+name] = name
+			decls["encode"+name] = name
+		}
+	}
+	return decls
+}
+
+// elemTypeName strips the slice and array brackets of a Go type name
+func elemTypeName(typeName string) string {
+	for strings.HasPrefix(typeName, "[") {
+		typeName = typeName[strings.Index(typeName, "]")+1:]
+	}
+	return typeName
+}
+
+// sharedStruct returns the shared struct a declaration belongs to, if any
+func sharedStruct(decl ast.Decl, sharedDecls map[string]string) (string, bool) {
+	name := ""
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv != nil && len(d.Recv.List) > 0 {
+			name = receiverTypeName(d.Recv.List[0].Type)
+		} else {
+			name = d.Name.Name
+		}
+	case *ast.GenDecl:
+		_, name = declDoc(d)
+	}
+	structName, ok := sharedDecls[name]
+	return structName, ok
+}
+
+// contractScopeRenames computes the edits that prefix every contract-scoped
+// package-level identifier (and its doc comment) with the given prefix,
+// leaving the unscoped names, runtime declarations and shared structs, as is
+func contractScopeRenames(rc *renderedContract, prefix string, unscoped map[string]bool) []sourceEdit {
+	return renameEdits(rc, func(name string) (string, bool) {
+		if unscoped[name] {
+			return "", false
+		}
+		return prefixIdentifier(prefix, name), true
+	})
+}
+
+// renameEdits computes the edits that rename package-level identifiers, and
+// the doc comments starting with them, as decided by rename
+func renameEdits(rc *renderedContract, rename func(name string) (string, bool)) []sourceEdit {
+	renames := make(map[*ast.Object]string)
+	for name, obj := range rc.file.Scope.Objects {
+		if newName, ok := rename(name); ok {
+			renames[obj] = newName
+		}
+	}
+
+	// Composite literal keys are field names, never package-level references
+	keys := make(map[*ast.Ident]bool)
+	ast.Inspect(rc.file, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.CompositeLit); ok {
+			for _, elt := range lit.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if ident, ok := kv.Key.(*ast.Ident); ok {
+						keys[ident] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	var edits []sourceEdit
+	ast.Inspect(rc.file, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || ident.Obj == nil || keys[ident] {
+			return true
+		}
+		if newName, ok := renames[ident.Obj]; ok {
+			start := rc.offset(ident.Pos())
+			edits = append(edits, sourceEdit{start: start, end: start + len(ident.Name), text: newName})
+		}
+		return true
+	})
+
+	// Keep doc comments starting with the declared name in sync with the rename
+	for _, decl := range rc.file.Decls {
+		doc, name := declDoc(decl)
+		if doc == nil || name == "" {
+			continue
+		}
+		newName, ok := rename(name)
+		if !ok {
+			continue
+		}
+		first := doc.List[0]
+		if !strings.HasPrefix(first.Text, "// "+name+" ") {
+			continue
+		}
+		start := rc.offset(first.Pos()) + len("// ")
+		edits = append(edits, sourceEdit{start: start, end: start + len(name), text: newName})
+	}
+
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	return edits
+}
+
+// declSource returns the source of a declaration, including its doc comment, with renames applied
+func (rc *renderedContract) declSource(decl ast.Decl) string {
+	start := rc.offset(decl.Pos())
+	if doc, _ := declDoc(decl); doc != nil {
+		start = rc.offset(doc.Pos())
+	}
+	end := rc.offset(decl.End())
+
+	var buf strings.Builder
+	pos := start
+	for _, edit := range rc.edits {
+		if edit.start < start || edit.end > end {
+			continue
+		}
+		buf.WriteString(rc.src[pos:edit.start])
+		buf.WriteString(edit.text)
+		pos = edit.end
+	}
+	buf.WriteString(rc.src[pos:end])
+	return buf.String()
+}
+
+// offset converts a token position to a byte offset in the rendered source
+func (rc *renderedContract) offset(pos token.Pos) int {
+	return rc.fset.Position(pos).Offset
+}
+
+// declDoc returns the doc comment and declared name of a package-level declaration.
+// Methods report no name since they are never renamed.
+func declDoc(decl ast.Decl) (*ast.CommentGroup, string) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv != nil {
+			return d.Doc, ""
+		}
+		return d.Doc, d.Name.Name
+	case *ast.GenDecl:
+		if len(d.Specs) != 1 {
+			return d.Doc, ""
+		}
+		switch spec := d.Specs[0].(type) {
+		case *ast.TypeSpec:
+			return d.Doc, spec.Name.Name
+		case *ast.ValueSpec:
+			if len(spec.Names) == 1 {
+				return d.Doc, spec.Names[0].Name
+			}
+		}
+		return d.Doc, ""
+	}
+	return nil, ""
+}
+
+// isImportDecl reports whether decl is an import declaration
+func isImportDecl(decl ast.Decl) bool {
+	gen, ok := decl.(*ast.GenDecl)
+	return ok && gen.Tok == token.IMPORT
+}
+
+// isRuntimeDecl reports whether decl belongs to the shared runtime
+func isRuntimeDecl(decl ast.Decl, runtimeNames map[string]bool) bool {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv != nil && len(d.Recv.List) > 0 {
+			return runtimeNames[receiverTypeName(d.Recv.List[0].Type)]
+		}
+		return runtimeNames[d.Name.Name]
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if !runtimeNames[s.Name.Name] {
+					return false
+				}
+			case *ast.ValueSpec:
+				for _, name := range s.Names {
+					if !runtimeNames[name.Name] {
+						return false
+					}
+				}
+			}
+		}
+		return len(d.Specs) > 0
+	}
+	return false
+}
+
+// receiverTypeName returns the base type name of a method receiver
+func receiverTypeName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// prefixIdentifier prefixes a package-level identifier while preserving its visibility
+func prefixIdentifier(prefix, name string) string {
+	if ast.IsExported(name) {
+		return prefix + name
+	}
+	return strings.ToLower(prefix[:1]) + prefix[1:] + titleCase(strings.TrimLeft(name, "_"))
+}
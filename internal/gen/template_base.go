@@ -3,8 +3,8 @@
 package gen
 
 // contractTemplate is the main template for generating contract Go packages
-const contractTemplate = `// Code generated by github.com/otherview/solgen. DO NOT EDIT.
-// SPDX-License-Identifier: MIT
+const contractTemplate = generatedHeader + `
+{{.Options.Header}}
 // Contract: {{.Contract.Name}} (solc {{.Contract.SolcVersion | default "unknown"}})
 
 package {{.Contract.PackageName}}
@@ -13,15 +13,41 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"reflect"
 	"strings"
 {{- range .Imports}}
 	"{{.}}"
 {{- end}}
 )
 
+{{- if .Options.EmbedABI}}
+
+// _abiFS holds the contract ABI, kept next to this file
+//
+//go:embed {{.ABIFile}}
+var _abiFS embed.FS
+
 // Contract metadata
-var _abiJSON = {{.Contract.ABIJson | quote}}
+var _abiJSON = func() string {
+	data, err := _abiFS.ReadFile({{.ABIFile | quote}})
+	if err != nil {
+		panic("reading embedded ABI: " + err.Error())
+	}
+	return string(data)
+}()
+{{- else}}
+
+// Contract metadata
+var _abiJSON = {{.Contract.ABIJson | stringLit}}
+{{- end}}
+
+// ContractName is the name of the contract in its Solidity source
+const ContractName = {{.Contract.Name | quote}}
+
+// SourceFile is the Solidity source file declaring the contract
+const SourceFile = {{.Contract.SourceFile | quote}}
 
 
 // ABI returns the contract ABI as a JSON string
@@ -37,9 +63,252 @@ var Bytecode = HexData({{.Contract.Bytecode.Hex | quote}})
 {{- if and .Contract.DeployedBytecode (ne .Contract.DeployedBytecode.Hex "0x") (ne .Contract.DeployedBytecode.Hex "")}}
 // DeployedBytecode contains the contract runtime bytecode  
 var DeployedBytecode = HexData({{.Contract.DeployedBytecode.Hex | quote}})
+
+// DeployedBytecodeStripped returns DeployedBytecode without its trailing
+// metadata, to compare it with on-chain code built from different metadata
+func DeployedBytecodeStripped() HexData {
+	return DeployedBytecode.StripMetadata()
+}
+{{- end}}
+
+{{- if and .Contract.Bytecode .Contract.Constructor .Contract.Constructor.LinkReferences}}
+
+` + libraryLinkTemplate + `
+{{- end}}
+
+{{- if and .Contract.Constructor .Contract.Constructor.Inputs}}
+
+` + constructorValidationTemplate + `
+{{- end}}
+
+{{- if and .Options.WithBind .Contract.Bytecode (ne .Contract.Bytecode.Hex "0x") (ne .Contract.Bytecode.Hex "")}}
+
+` + bindDeployTemplate + `
+{{- end}}
+
+{{- if and .Options.WithBind .Contract.DeployedBytecode (ne .Contract.DeployedBytecode.Hex "0x") (ne .Contract.DeployedBytecode.Hex "")}}
+
+` + bindVerifyTemplate + `
+{{- end}}
+
+{{- if .Options.NativeTypes}}
+
+` + nativeValueTypesTemplate + `
+{{- else}}
+
+` + valueTypesTemplate + `
+{{- end}}
+
+` + runtimeTypesTemplate + `
+
+{{- if and .Options.WithBind (not .Options.NativeTypes)}}
+
+` + bindConversionsTemplate + `
+{{- end}}
+
+{{- if .Options.WithBind}}
+
+` + bindRuntimeTemplate + `
+{{- end}}
+
+{{- if .Options.WithKeccak}}
+
+` + keccakRuntimeTemplate + `
+{{- end}}
+
+{{- if .Options.WithProxy}}
+
+` + proxyRuntimeTemplate + `
+{{- end}}
+
+` + encodingHelpersTemplate + `
+
+` + decodingHelpersTemplate + `
+
+{{- if .Options.StrictDecode}}
+
+` + strictValueDecodingTemplate + `
+{{- else}}
+
+` + valueDecodingTemplate + `
+{{- end}}
+
+{{- if .Options.TinyGo}}
+
+` + typedArrayDecodingTemplate + `
+{{- else}}
+
+` + arrayDecodingTemplate + `
+{{- end}}
+
+{{- if .Contract.Methods}}
+
+// Method information
+{{- range .Contract.Methods}}
+func Get{{.Name | title}}Method() MethodInfo {
+	return MethodInfo{
+		Name:      {{.Name | quote}},
+		Signature: {{.Signature | quote}},
+		Selector:  HexData({{.Selector.Hex | quote}}),
+	}
+}
+{{- end}}
+{{- end}}
+
+{{- if .Contract.Events}}
+
+// Event information
+{{- range .Contract.Events}}
+func Get{{.Name | title}}Event() EventInfo {
+	return EventInfo{
+		Name:  {{.Name | quote}},
+		Topic: HashFromHex({{printf "0x%x" .Topic.Bytes | quote}}),
+	}
+}
+{{- end}}
+{{- end}}
+
+{{- if .Contract.Errors}}
+
+// Error information  
+{{- range .Contract.Errors}}
+func Get{{.Name}}Error() ErrorInfo {
+	return ErrorInfo{
+		Name:      {{.Name | quote}},
+		Signature: {{.Signature | quote}},
+		Selector:  HexData({{.Selector.Hex | quote}}),
+	}
+}
+{{- end}}
+{{- end}}
+
+// Method registry provides access to packable contract methods
+type MethodRegistry struct{}
+
+// Event registry provides access to packable contract events
+type EventRegistry struct{}
+
+// Error registry provides access to packable contract errors
+type ErrorRegistry struct{}
+
+` + runtimeRegistryTypesTemplate + `
+
+` + methodRegistryTemplate + `
+
+` + eventRegistryTemplate + `
+
+` + errorRegistryTemplate + `
+
+` + structDefinitionsTemplate + `
+
+{{- if or .Options.WithEqual .Options.WithFound}}
+
+` + equalTemplate + `
+{{- end}}
+
+` + structDecodersTemplate + `
+
+` + structEncodersTemplate + `
+
+` + structRegistryTemplate + `
+
+` + methodDecodersTemplate + `
+
+` + methodTupleArgsTemplate + `
+
+{{- with eip712Domain .Contract}}
+
+` + eip712DomainTemplate + `
+{{- end}}
+
+{{- if .Options.WithBind}}
+
+` + bindCallTemplate + `
+{{- end}}
+
+` + eventDecodersTemplate + `
+
+{{- if and .Options.WithBind .Contract.Events}}
+
+` + bindReceiptTemplate + `
+{{- end}}
+
+` + errorDecodersTemplate + `
+
+`
+
+// libraryLinkTemplate generates LinkBytecode for creation bytecode holding
+// library placeholders
+const libraryLinkTemplate = `// libraryLinks holds the byte offsets of the library placeholders in Bytecode
+var libraryLinks = []struct {
+	Name   string
+	Starts []int
+}{
+{{- range $name, $refs := .Contract.Constructor.LinkReferences}}
+	{ {{- $name | quote}}, []int{ {{- range $i, $ref := $refs}}{{if $i}}, {{end}}{{$ref.Start}}{{end -}} } },
+{{- end}}
+}
+
+// LinkBytecode returns Bytecode with the placeholders of every library it uses
+// replaced by the library address, keyed by library name
+func LinkBytecode(libraries map[string]Address) (HexData, error) {
+	code := []byte(strings.TrimPrefix(Bytecode.Hex(), "0x"))
+	for _, link := range libraryLinks {
+		address, ok := libraries[link.Name]
+		if !ok {
+			return "", fmt.Errorf("missing address for library %s", link.Name)
+		}
+		for _, start := range link.Starts {
+			copy(code[2*start:], hex.EncodeToString(address[:]))
+		}
+	}
+	return HexData("0x" + string(code)), nil
+}`
+
+// constructorValidationTemplate generates ValidateConstructorArgs from the constructor inputs
+const constructorValidationTemplate = `{{- $ctor := .Contract.Constructor}}
+// ValidateConstructorArgs checks constructor arguments before deployment: the
+// argument count, that no argument is nil, that unsigned integers are not
+// negative and that addresses are given as Address
+func ValidateConstructorArgs(args ...any) error {
+	if len(args) != {{len $ctor.Inputs}} {
+		return fmt.Errorf("constructor expects {{len $ctor.Inputs}} arguments, got %d", len(args))
+	}
+{{- range $i, $input := $ctor.Inputs}}
+{{- $label := printf "constructor argument %d (%s)" $i $input.ABIType}}
+{{- if $input.Name}}{{$label = printf "constructor argument %d (%s %s)" $i $input.ABIType $input.Name}}{{end}}
+	if args[{{$i}}] == nil {
+		return errors.New({{printf "%s is nil" $label | quote}})
+	}
+{{- if isUintType $input.ABIType}}
+	switch v := args[{{$i}}].(type) {
+	case *big.Int:
+		if v == nil {
+			return errors.New({{printf "%s is nil" $label | quote}})
+		}
+		if v.Sign() < 0 {
+			return fmt.Errorf({{printf "%s is negative: %%s" $label | quote}}, v)
+		}
+	case int64:
+		if v < 0 {
+			return fmt.Errorf({{printf "%s is negative: %%d" $label | quote}}, v)
+		}
+	}
+{{- else if hasPrefix $input.ABIType "int"}}
+	if v, ok := args[{{$i}}].(*big.Int); ok && v == nil {
+		return errors.New({{printf "%s is nil" $label | quote}})
+	}
+{{- else if and (eq $input.ABIType "address") (not $input.Type.CustomType)}}
+	if _, ok := args[{{$i}}].(Address); !ok {
+		return fmt.Errorf({{printf "%s must be an Address, got %%T" $label | quote}}, args[{{$i}}])
+	}
 {{- end}}
+{{- end}}
+	return nil
+}`
 
-// Address represents a 20-byte Ethereum address
+// valueTypesTemplate declares the Address and Hash types shared by every contract
+const valueTypesTemplate = `// Address represents a 20-byte Ethereum address
 type Address [20]byte
 
 // String returns the hex string representation of the address
@@ -47,6 +316,17 @@ func (a Address) String() string {
 	return "0x" + hex.EncodeToString(a[:])
 }
 
+// MarshalText encodes the address as 0x-prefixed hex, so it is a string in JSON,
+// also as a map key
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an address from hex, with or without the 0x prefix
+func (a *Address) UnmarshalText(text []byte) error {
+	return unmarshalHexText(a[:], text, "address")
+}
+
 // Hash represents a 32-byte hash
 type Hash [32]byte
 
@@ -60,7 +340,42 @@ func (h Hash) Bytes() []byte {
 	return h[:]
 }
 
-// AddressFromHex creates an Address from a hex string
+// MarshalText encodes the hash as 0x-prefixed hex, so it is a string in JSON,
+// also as a map key
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText decodes a hash from hex, with or without the 0x prefix
+func (h *Hash) UnmarshalText(text []byte) error {
+	return unmarshalHexText(h[:], text, "hash")
+}
+
+// unmarshalHexText decodes hex text of exactly len(dst) bytes into dst
+func unmarshalHexText(dst, text []byte, kind string) error {
+	s := strings.TrimPrefix(string(text), "0x")
+	if len(s) != 2*len(dst) {
+		return fmt.Errorf("invalid %s hex length %d, expected %d", kind, len(s), 2*len(dst))
+	}
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("invalid %s hex: %w", kind, err)
+	}
+	copy(dst, decoded)
+	return nil
+}`
+
+// nativeValueTypesTemplate replaces valueTypesTemplate with --native-types,
+// declaring Address and Hash as aliases of the go-ethereum types
+const nativeValueTypesTemplate = `// Address is go-ethereum's common.Address, so decoded values and arguments
+// need no conversion
+type Address = common.Address
+
+// Hash is go-ethereum's common.Hash
+type Hash = common.Hash`
+
+// runtimeTypesTemplate contains the self-contained value helpers shared by every contract
+const runtimeTypesTemplate = `// AddressFromHex creates an Address from a hex string
 func AddressFromHex(s string) Address {
 	var addr Address
 	if strings.HasPrefix(s, "0x") {
@@ -118,52 +433,70 @@ func (h HexData) Bytes() []byte {
 	return decoded
 }
 
-` + encodingHelpersTemplate + `
-
-` + decodingHelpersTemplate + `
-
-// Method information
-{{- range .Contract.Methods}}
-func Get{{.Name | title}}Method() MethodInfo {
-	return MethodInfo{
-		Name:      {{.Name | quote}},
-		Signature: {{.Signature | quote}},
-		Selector:  HexData({{.Selector.Hex | quote}}),
+// StripMetadata returns the bytecode without the CBOR metadata solc appends to
+// it, whose length is given by the last 2 bytes. The metadata holds a hash of
+// the sources and settings, so it differs between otherwise identical builds.
+// Bytecode that does not end with a CBOR map is returned unchanged.
+func (h HexData) StripMetadata() HexData {
+	hexStr := strings.TrimPrefix(string(h), "0x")
+	if len(hexStr) < 4 {
+		return h
+	}
+	suffix, err := hex.DecodeString(hexStr[len(hexStr)-4:])
+	if err != nil {
+		return h
 	}
+	length := int(suffix[0])<<8 | int(suffix[1])
+	size := (length + 2) * 2
+	if size > len(hexStr) {
+		return h
+	}
+	start := len(hexStr) - size
+	// CBOR maps have major type 5 in the top 3 bits of their first byte
+	header, err := hex.DecodeString(hexStr[start : start+2])
+	if err != nil || header[0]>>5 != 5 {
+		return h
+	}
+	return HexData("0x" + hexStr[:start])
 }
-{{- end}}
 
-// Event information
-{{- range .Contract.Events}}
-func Get{{.Name | title}}Event() EventInfo {
-	return EventInfo{
-		Name:  {{.Name | quote}},
-		Topic: HashFromHex({{printf "0x%x" .Topic.Bytes | quote}}),
-	}
+// Largest values of the unsigned Solidity types that fit Go integers, e.g. type(uint64).max
+const (
+	MaxUint8  uint8  = 1<<8 - 1
+	MaxUint16 uint16 = 1<<16 - 1
+	MaxUint32 uint32 = 1<<32 - 1
+	MaxUint64 uint64 = 1<<64 - 1
+)
+
+// MaxUint128 returns type(uint128).max as a new *big.Int
+func MaxUint128() *big.Int {
+	return maxUint(128)
 }
-{{- end}}
 
-// Error information  
-{{- range .Contract.Errors}}
-func Get{{.Name}}Error() ErrorInfo {
-	return ErrorInfo{
-		Name:      {{.Name | quote}},
-		Signature: {{.Signature | quote}},
-		Selector:  HexData({{.Selector.Hex | quote}}),
-	}
+// MaxUint256 returns type(uint256).max, the usual unlimited ERC-20 allowance.
+// Every call returns a new *big.Int, which the caller may modify.
+func MaxUint256() *big.Int {
+	return maxUint(256)
 }
-{{- end}}
 
-// Method registry provides access to packable contract methods
-type MethodRegistry struct{}
+// MaxInt256 returns type(int256).max as a new *big.Int
+func MaxInt256() *big.Int {
+	return maxUint(255)
+}
 
-// Event registry provides access to packable contract events
-type EventRegistry struct{}
+// MinInt256 returns type(int256).min as a new *big.Int
+func MinInt256() *big.Int {
+	return new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))
+}
 
-// Error registry provides access to packable contract errors
-type ErrorRegistry struct{}
+// maxUint returns 2^bits - 1
+func maxUint(bits uint) *big.Int {
+	n := new(big.Int).Lsh(big.NewInt(1), bits)
+	return n.Sub(n, big.NewInt(1))
+}`
 
-// PackableMethod represents a method with packing capabilities
+// runtimeRegistryTypesTemplate contains the registry types shared by every contract
+const runtimeRegistryTypesTemplate = `// PackableMethod represents a method with packing capabilities
 type PackableMethod struct {
 	Name      string
 	Signature string
@@ -182,6 +515,12 @@ type EventDecoder struct {
 	Topic Hash
 }
 
+// RawLog is an undecoded log, as returned by eth_getLogs
+type RawLog struct {
+	Topics []Hash
+	Data   []byte
+}
+
 // PackableError represents an error with unpacking capabilities
 type PackableError struct {
 	Name      string
@@ -189,6 +528,21 @@ type PackableError struct {
 	Selector  HexData
 }
 
+// StructCodec decodes and encodes a struct as an ABI tuple, for tooling that
+// goes over the structs of a contract without reflection
+type StructCodec struct {
+	Name   string
+	Decode func(data []byte) (any, error)
+	Encode func(value any) ([]byte, error)
+}
+
+// ArgDescriptor describes a method argument as declared in the ABI
+type ArgDescriptor struct {
+	Name   string // Solidity name, empty for unnamed arguments
+	Type   string // canonical Solidity type, e.g. uint256
+	GoType string // Go type of the generated binding
+}
+
 // MethodInfo represents method metadata
 type MethodInfo struct {
 	Name      string
@@ -222,50 +576,154 @@ func (pm *PackableMethod) Pack(args ...any) (HexData, error) {
 		return pm.Selector, nil
 	}
 	
-	// Encode arguments using our ABI implementation
-	var encodedArgs []byte
-	for _, arg := range args {
-		switch v := arg.(type) {
-		case *big.Int:
-			data, err := encodeUint256(v)
-			if err != nil {
-				return "", fmt.Errorf("encoding big.Int: %w", err)
-			}
-			encodedArgs = append(encodedArgs, data...)
-		case Address:
-			data, err := encodeAddress(v)
-			if err != nil {
-				return "", fmt.Errorf("encoding address: %w", err)
-			}
-			encodedArgs = append(encodedArgs, data...)
-		case bool:
-			data, err := encodeBool(v)
-			if err != nil {
-				return "", fmt.Errorf("encoding bool: %w", err)
-			}
-			encodedArgs = append(encodedArgs, data...)
-		case string:
-			data, err := encodeString(v)
-			if err != nil {
-				return "", fmt.Errorf("encoding string: %w", err)
-			}
-			encodedArgs = append(encodedArgs, data...)
-		case []byte:
-			data, err := encodeBytes(v)
-			if err != nil {
-				return "", fmt.Errorf("encoding bytes: %w", err)
+	// Integers must fit the size of their Solidity type, e.g. 300 is not a uint8
+	if argTypes := signatureArgTypes(pm.Signature); len(argTypes) == len(args) {
+		for i, arg := range args {
+			if err := checkArgBits(arg, argTypes[i]); err != nil {
+				return "", fmt.Errorf("packing %s argument %d: %w", pm.Name, i, err)
 			}
-			encodedArgs = append(encodedArgs, data...)
-		default:
-			return "", fmt.Errorf("unsupported argument type: %T", arg)
 		}
 	}
-	
+
+	// Encode arguments using our ABI implementation. Static arguments are
+	// written in the head, dynamic ones as an offset to their data in the tail.
+	values := make([][]byte, len(args))
+	dynamic := make([]bool, len(args))
+	for i, arg := range args {
+		data, err := encodeArg(arg)
+		if err != nil {
+			return "", err
+		}
+		values[i], dynamic[i] = data, isDynamicArg(arg)
+	}
+	encodedArgs := encodeTuple(values, dynamic)
+
 	// Combine selector and encoded arguments
 	result := hex.EncodeToString(append(selectorBytes, encodedArgs...))
 	return HexData("0x" + result), nil
 }
 
+// widenUint widens the unsigned integer types accepted by Pack
+func widenUint(v any) uint64 {
+	switch n := v.(type) {
+	case uint8:
+		return uint64(n)
+	case uint16:
+		return uint64(n)
+	case uint32:
+		return uint64(n)
+	default:
+		return n.(uint64)
+	}
+}
+
+// widenInt widens the signed integer types accepted by Pack
+func widenInt(v any) int64 {
+	switch n := v.(type) {
+	case int8:
+		return int64(n)
+	case int16:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case int:
+		return int64(n)
+	default:
+		return n.(int64)
+	}
+}
+
+// signatureArgTypes returns the top-level argument types of a signature,
+// e.g. ["uint8", "(address,uint256)[]"] for "f(uint8,(address,uint256)[])"
+func signatureArgTypes(signature string) []string {
+	start, end := strings.Index(signature, "("), strings.LastIndex(signature, ")")
+	if start == -1 || end <= start+1 {
+		return nil
+	}
+
+	var argTypes []string
+	depth, from := 0, start+1
+	for i := start + 1; i < end; i++ {
+		switch signature[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				argTypes = append(argTypes, signature[from:i])
+				from = i + 1
+			}
+		}
+	}
+	return append(argTypes, signature[from:end])
+}
+
+// checkArgBits checks that an integer argument, or the elements of an integer
+// slice, fit the intN or uintN Solidity type they are packed as. Other
+// arguments and types are left to encodeArg.
+func checkArgBits(arg any, abiType string) error {
+	elemType, _, _ := strings.Cut(abiType, "[")
+	signed := strings.HasPrefix(elemType, "int")
+	if !signed && !strings.HasPrefix(elemType, "uint") {
+		return nil
+	}
+	bits := 256
+	if size := strings.TrimPrefix(strings.TrimPrefix(elemType, "u"), "int"); size != "" {
+		if _, err := fmt.Sscan(size, &bits); err != nil {
+			return nil
+		}
+	}
+
+	switch v := arg.(type) {
+	case *big.Int:
+		return checkIntBits(v, bits, signed, elemType)
+	case uint8, uint16, uint32, uint64:
+		return checkIntBits(new(big.Int).SetUint64(widenUint(v)), bits, signed, elemType)
+	case int8, int16, int32, int64, int:
+		return checkIntBits(big.NewInt(widenInt(v)), bits, signed, elemType)
+	case []*big.Int:
+		for i, elem := range v {
+			if err := checkIntBits(elem, bits, signed, elemType); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+	case []uint64:
+		for i, elem := range v {
+			if err := checkIntBits(new(big.Int).SetUint64(elem), bits, signed, elemType); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkIntBits checks that v fits a signed or unsigned integer of the given bits
+func checkIntBits(v *big.Int, bits int, signed bool, typeName string) error {
+	if v == nil {
+		return nil
+	}
+	if !signed {
+		if v.Sign() < 0 {
+			return fmt.Errorf("negative value %s for %s", v, typeName)
+		}
+		if v.BitLen() > bits {
+			return fmt.Errorf("value %s overflows %s", v, typeName)
+		}
+		return nil
+	}
+
+	// Two's complement range [-2^(bits-1), 2^(bits-1)-1]
+	magnitude := new(big.Int).Set(v)
+	if v.Sign() < 0 {
+		magnitude.Neg(v).Sub(magnitude, big.NewInt(1))
+	}
+	if magnitude.BitLen() > bits-1 {
+		return fmt.Errorf("value %s overflows %s", v, typeName)
+	}
+	return nil
+}
+
 // MustPack encodes method arguments and panics on error
 func (pm *PackableMethod) MustPack(args ...any) HexData {
 	result, err := pm.Pack(args...)
@@ -275,20 +733,60 @@ func (pm *PackableMethod) MustPack(args ...any) HexData {
 	return result
 }
 
-` + methodRegistryTemplate + `
-
-` + eventRegistryTemplate + `
-
-` + errorRegistryTemplate + `
+// PackWithSelector encodes method arguments like Pack, but after the given
+// selector instead of the method's own, e.g. for forwarders and meta-transactions
+func (pm *PackableMethod) PackWithSelector(sel [4]byte, args ...any) (HexData, error) {
+	packed, err := pm.Pack(args...)
+	if err != nil {
+		return "", err
+	}
+	return withSelector(packed, sel), nil
+}
 
-` + structDefinitionsTemplate + `
+// withSelector replaces the 4-byte selector of packed calldata
+func withSelector(packed HexData, sel [4]byte) HexData {
+	data := packed.Bytes()
+	copy(data, sel[:])
+	return HexData("0x" + hex.EncodeToString(data))
+}
 
-` + structDecodersTemplate + `
+// PackArgs encodes method arguments like Pack, without the method selector, as
+// abi.encode(args) does, e.g. for hashing them off-chain
+func (pm *PackableMethod) PackArgs(args ...any) (HexData, error) {
+	packed, err := pm.Pack(args...)
+	if err != nil {
+		return "", err
+	}
+	return withoutSelector(packed), nil
+}
 
-` + methodDecodersTemplate + `
+// withoutSelector strips the 4-byte selector of packed calldata
+func withoutSelector(packed HexData) HexData {
+	return HexData("0x" + hex.EncodeToString(packed.Bytes()[4:]))
+}
 
-` + eventDecodersTemplate + `
+// PackPadded encodes method arguments like Pack and right-pads the calldata with
+// zero bytes to a multiple of alignment bytes. The padding follows the encoded
+// arguments, which the contract ignores when decoding them.
+func (pm *PackableMethod) PackPadded(alignment int, args ...any) (HexData, error) {
+	packed, err := pm.Pack(args...)
+	if err != nil {
+		return "", err
+	}
+	return padCalldata(packed, alignment)
+}
 
-` + errorDecodersTemplate + `
+// padCalldata right-pads packed calldata with zero bytes to a multiple of alignment
+func padCalldata(packed HexData, alignment int) (HexData, error) {
+	if alignment < 1 {
+		return "", fmt.Errorf("invalid calldata alignment %d", alignment)
+	}
+	data := packed.Bytes()
+	if rem := len(data) % alignment; rem != 0 {
+		data = append(data, make([]byte, alignment-rem)...)
+	}
+	return HexData("0x" + hex.EncodeToString(data)), nil
+}`
 
-`
\ No newline at end of file
+// runtimeTemplate contains every declaration that does not depend on a specific contract
+const runtimeTemplate = runtimeTypesTemplate + "\n\n" + encodingHelpersTemplate + "\n\n" + decodingHelpersTemplate + "\n\n" + runtimeRegistryTypesTemplate
@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: MIT
+
+package gen
+
+// bindConversionsTemplate converts Address and Hash to and from the go-ethereum
+// types with --with-bind, unless --native-types makes them the same types
+const bindConversionsTemplate = `// Common converts the address to a go-ethereum common.Address
+func (a Address) Common() common.Address {
+	return common.Address(a)
+}
+
+// AddressFromCommon creates an Address from a go-ethereum common.Address
+func AddressFromCommon(addr common.Address) Address {
+	return Address(addr)
+}
+
+// Common converts the hash to a go-ethereum common.Hash
+func (h Hash) Common() common.Hash {
+	return common.Hash(h)
+}
+
+// HashFromCommon creates a Hash from a go-ethereum common.Hash
+func HashFromCommon(hash common.Hash) Hash {
+	return Hash(hash)
+}`
+
+// bindRuntimeTemplate contains the go-ethereum helpers shared by every contract
+const bindRuntimeTemplate = `// CallMsg packs the method arguments into a go-ethereum CallMsg addressed to the contract at to
+func (pm *PackableMethod) CallMsg(to Address, args ...any) (ethereum.CallMsg, error) {
+	data, err := pm.Pack(args...)
+	if err != nil {
+		return ethereum.CallMsg{}, err
+	}
+	contract := common.Address(to)
+	return ethereum.CallMsg{
+		To:   &contract,
+		Data: data.Bytes(),
+	}, nil
+}
+
+// EncodeArgs packs args behind the selector of the function with the given
+// signature, e.g. "transfer(address,uint256)", with go-ethereum's ABI packer.
+// It encodes calls to functions missing from the ABI, tuple parameters are not supported.
+func EncodeArgs(signature string, args ...any) (HexData, error) {
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return "", fmt.Errorf("invalid function signature %q", signature)
+	}
+	params := signature[open+1 : len(signature)-1]
+	if strings.Contains(params, "(") {
+		return "", fmt.Errorf("function signature %q has tuple parameters, which are not supported", signature)
+	}
+
+	var arguments abi.Arguments
+	var canonical []string
+	if strings.TrimSpace(params) != "" {
+		for _, param := range strings.Split(params, ",") {
+			typ, err := abi.NewType(strings.TrimSpace(param), "", nil)
+			if err != nil {
+				return "", fmt.Errorf("parsing parameter %q of %q: %w", param, signature, err)
+			}
+			arguments = append(arguments, abi.Argument{Type: typ})
+			canonical = append(canonical, typ.String())
+		}
+	}
+
+	packed, err := arguments.Pack(args...)
+	if err != nil {
+		return "", fmt.Errorf("packing arguments of %q: %w", signature, err)
+	}
+	name := strings.TrimSpace(signature[:open])
+	selector := crypto.Keccak256([]byte(name + "(" + strings.Join(canonical, ",") + ")"))[:4]
+	return HexData("0x" + hex.EncodeToString(append(selector, packed...))), nil
+}`
+
+// bindDeployTemplate generates the Deploy helper for contracts with creation bytecode
+const bindDeployTemplate = `{{- $ctor := .Contract.Constructor}}
+{{- $payable := and $ctor $ctor.Payable}}
+{{- $links := and $ctor $ctor.LinkReferences}}
+// Deploy deploys the contract with go-ethereum's bind package
+{{- if $payable}}, forwarding value to the payable constructor{{end}}
+{{- if $links}}
+// Libraries are linked into the bytecode from their addresses, keyed by library name
+{{- end}}
+func Deploy(opts *bind.TransactOpts, backend bind.ContractBackend
+{{- if $links}}, libraries map[string]Address{{end}}
+{{- if $payable}}, value *big.Int{{end}}
+{{- if and $ctor $ctor.InputStruct}}, input {{$ctor.InputStruct.Name}}
+{{- else if and $ctor (eq (len $ctor.Inputs) 1)}}, arg {{formatGoType (index $ctor.Inputs 0).Type}}
+{{- end}}) (Address, *types.Transaction, error) {
+{{- if $links}}
+	bytecode, err := LinkBytecode(libraries)
+	if err != nil {
+		return Address{}, nil, err
+	}
+{{- else}}
+	bytecode := Bytecode
+	if strings.Contains(bytecode.Hex(), "__") {
+		return Address{}, nil, errors.New("bytecode contains unlinked library placeholders")
+	}
+{{- end}}
+{{- if and $ctor $ctor.Inputs}}
+
+	if err := ValidateConstructorArgs(
+{{- if $ctor.InputStruct}}
+{{- range $i, $field := $ctor.InputStruct.Fields}}{{if $i}}, {{end}}input.{{$field.Name}}{{end}}
+{{- else if eq (len $ctor.Inputs) 1}}arg
+{{- end}}); err != nil {
+		return Address{}, nil, err
+	}
+{{- end}}
+
+	parsed, err := abi.JSON(strings.NewReader(_abiJSON))
+	if err != nil {
+		return Address{}, nil, fmt.Errorf("parsing ABI: %w", err)
+	}
+
+	txOpts := *opts
+{{- if $payable}}
+	txOpts.Value = value
+{{- else}}
+	if txOpts.Value != nil && txOpts.Value.Sign() != 0 {
+		return Address{}, nil, errors.New("constructor is not payable")
+	}
+{{- end}}
+
+	address, tx, _, err := bind.DeployContract(&txOpts, parsed, bytecode.Bytes(), backend
+{{- if and $ctor $ctor.InputStruct}}
+{{- range $ctor.InputStruct.Fields}}, input.{{.Name}}{{end}}
+{{- else if and $ctor (eq (len $ctor.Inputs) 1)}}, arg
+{{- end}})
+	if err != nil {
+		return Address{}, nil, err
+	}
+	return Address(address), tx, nil
+}`
+
+// bindVerifyTemplate generates VerifyDeployedCode for contracts with runtime bytecode
+const bindVerifyTemplate = `// VerifyDeployedCode reports whether the code deployed at addr is DeployedBytecode.
+// The trailing metadata of both is stripped, so a build from different sources
+// or settings producing the same code still verifies.
+func VerifyDeployedCode(ctx context.Context, backend bind.ContractCaller, addr Address) (bool, error) {
+	if strings.Contains(DeployedBytecode.Hex(), "__") {
+		return false, errors.New("deployed bytecode contains unlinked library placeholders")
+	}
+	code, err := backend.CodeAt(ctx, common.Address(addr), nil)
+	if err != nil {
+		return false, fmt.Errorf("fetching code: %w", err)
+	}
+	if len(code) == 0 {
+		return false, nil
+	}
+	onChain := HexData("0x" + hex.EncodeToString(code)).StripMetadata()
+	expected := DeployedBytecode.StripMetadata()
+	return strings.EqualFold(strings.TrimPrefix(onChain.Hex(), "0x"), strings.TrimPrefix(expected.Hex(), "0x")), nil
+}`
+
+// bindCallTemplate generates the Call helpers that run a method through a go-ethereum ContractCaller
+const bindCallTemplate = `{{- range .Contract.Methods}}
+{{- if gt (len .Outputs) 0}}
+{{- $result := printf "%sResult" (title .Name)}}
+{{- if eq (len .Outputs) 1}}{{$result = formatGoType (index .Outputs 0).Type}}{{end}}
+
+// Call calls {{.Name}} on the contract at to and decodes its return values
+func (m *{{.Name | title}}Method) Call(ctx context.Context, caller ethereum.ContractCaller, to Address, args ...any) ({{$result}}, error) {
+	var result {{$result}}
+	msg, err := m.CallMsg(to, args...)
+	if err != nil {
+		return result, err
+	}
+	data, err := caller.CallContract(ctx, msg, nil)
+	if err != nil {
+		return result, err
+	}
+	return m.Decode(data)
+}
+{{- else}}
+
+// Call calls {{.Name}} on the contract at to. The method has no return values,
+// so the returned data is not decoded and only the call error is reported
+func (m *{{.Name | title}}Method) Call(ctx context.Context, caller ethereum.ContractCaller, to Address, args ...any) error {
+	msg, err := m.CallMsg(to, args...)
+	if err != nil {
+		return err
+	}
+	_, err = caller.CallContract(ctx, msg, nil)
+	return err
+}
+{{- end}}
+{{- if .Payable}}
+
+// CallMsgWithValue packs the {{.Name}} arguments into a go-ethereum CallMsg
+// addressed to the contract at to, sending value with the call
+func (m *{{.Name | title}}Method) CallMsgWithValue(to Address, value *big.Int, args ...any) (ethereum.CallMsg, error) {
+	msg, err := m.CallMsg(to, args...)
+	if err != nil {
+		return ethereum.CallMsg{}, err
+	}
+	msg.Value = value
+	return msg, nil
+}
+
+// Transact sends a {{.Name}} transaction to the contract at to, forwarding value
+// to the payable method. The other fields of opts are used as they are.
+func (m *{{.Name | title}}Method) Transact(opts *bind.TransactOpts, backend bind.ContractTransactor, to Address, value *big.Int, args ...any) (*types.Transaction, error) {
+	// Methods taking structs shadow Pack with a typed one
+	data, err := m.PackableMethod.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	txOpts := *opts
+	txOpts.Value = value
+	contract := bind.NewBoundContract(common.Address(to), abi.ABI{}, nil, backend, nil)
+	return contract.RawTransact(&txOpts, data.Bytes())
+}
+{{- end}}
+{{- end}}`
+
+// bindReceiptTemplate generates DecodeReceiptLogs for contracts with events
+const bindReceiptTemplate = `// DecodeReceiptLogs decodes the logs of a transaction receipt whose first topic
+// is one of the contract events into their event structs, in receipt order.
+// Logs of other events are skipped. The log address is not checked, so other
+// contracts emitting an event with the same signature are decoded as well.
+func DecodeReceiptLogs(receipt *types.Receipt) ([]any, error) {
+	var results []any
+	for i, log := range receipt.Logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		topics := make([]Hash, len(log.Topics))
+		for j, topic := range log.Topics {
+			topics[j] = Hash(topic)
+		}
+
+		var result any
+		var err error
+		switch topics[0] {
+		{{- range .Contract.Events}}
+		case Events().{{.Name | title}}EventDecoder().Topic:
+			result, err = Events().{{.Name | title}}EventDecoder().DecodeLog(topics, log.Data)
+		{{- end}}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding receipt log %d: %w", i, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}`
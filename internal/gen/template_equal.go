@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: MIT
+
+package gen
+
+// equalTemplate generates Equal and IsZero methods for decoded structs under --with-equal
+const equalTemplate = `{{- range .Contract.Structs}}
+
+// Equal reports whether s and other hold the same values
+func (s {{.Name}}) Equal(other {{.Name}}) bool {
+	return {{if not .Fields}}true{{end}}{{range $i, $f := .Fields}}{{if $i}} &&
+		{{end}}{{equalExpr $f.Type (printf "s.%s" $f.Name) (printf "other.%s" $f.Name)}}{{end}}
+}
+
+// IsZero reports whether every field of s holds its zero value, big integers count as zero when nil or 0
+func (s {{.Name}}) IsZero() bool {
+	return {{if not .Fields}}true{{end}}{{range $i, $f := .Fields}}{{if $i}} &&
+		{{end}}{{zeroExpr $f.Type (printf "s.%s" $f.Name)}}{{end}}
+}
+{{- end}}
+
+{{- range .Contract.Methods}}
+{{- if gt (len .Outputs) 1}}
+{{- $result := printf "%sResult" (.Name | title)}}
+
+// Equal reports whether s and other hold the same values
+func (s {{$result}}) Equal(other {{$result}}) bool {
+	return {{range $i, $o := .Outputs}}{{if $i}} &&
+		{{end}}{{equalExpr $o.Type (printf "s.%s" ($o.Name | title)) (printf "other.%s" ($o.Name | title))}}{{end}}
+}
+
+// IsZero reports whether every field of s holds its zero value, big integers count as zero when nil or 0
+func (s {{$result}}) IsZero() bool {
+	return {{range $i, $o := .Outputs}}{{if $i}} &&
+		{{end}}{{zeroExpr $o.Type (printf "s.%s" ($o.Name | title))}}{{end}}
+}
+{{- end}}
+{{- end}}`
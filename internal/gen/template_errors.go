@@ -20,8 +20,25 @@ func (e *{{.Name}}ErrorDecoder) MustDecode(data []byte) {{.Struct.Name}} {
 	return result
 }
 
+{{- if $.Options.Qualified}}
+
+// decodeImpl decodes with decodeUnqualified, prefixing its errors with the
+// contract and error name
+func (e *{{.Name}}ErrorDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error) {
+	result, err := e.decodeUnqualified(data)
+	if err != nil {
+		return result, fmt.Errorf("{{$.Contract.Name | lower}}.{{.Name}}: %w", err)
+	}
+	return result, nil
+}
+
+// decodeUnqualified contains the actual decode logic
+func (e *{{.Name}}ErrorDecoder) decodeUnqualified(data []byte) ({{.Struct.Name}}, error) {
+{{- else}}
+
 // decodeImpl contains the actual decode logic
 func (e *{{.Name}}ErrorDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error) {
+{{- end}}
 	// Skip the 4-byte selector
 	if len(data) < 4 {
 		return {{.Struct.Name}}{}, errors.New("insufficient data for error selector")
@@ -71,6 +88,15 @@ func (e *{{.Name}}ErrorDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error
 	}
 	result.{{$input.Name | title}} = val{{$i}}
 	offset += 32
+	{{- else if or (eq $input.Type.TypeName "uint8") (eq $input.Type.TypeName "uint16") (eq $input.Type.TypeName "uint32") (eq $input.Type.TypeName "int8") (eq $input.Type.TypeName "int16") (eq $input.Type.TypeName "int32")}}
+	if len(errorData) < offset+32 {
+		return result, errors.New("insufficient data for error parameter {{$input.Name}}")
+	}
+	result.{{$input.Name | title}}, err = decode{{$input.Type.TypeName | title}}(errorData[offset:offset+32])
+	if err != nil {
+		return result, fmt.Errorf("decoding error parameter {{$input.Name}}: %w", err)
+	}
+	offset += 32
 	{{- else if eq $input.Type.TypeName "bool"}}
 	if len(errorData) < offset+32 {
 		return result, errors.New("insufficient data for error parameter {{$input.Name}}")
@@ -105,6 +131,59 @@ func (e *{{.Name}}ErrorDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error
 	}
 	result.{{$input.Name | title}} = val{{$i}}
 	offset = nextOffset
+	{{- else if and (bytesElemSize $input.Type) (fixedArrayLen $input.Type)}}
+	// {{$input.ABIType}} elements are stored in place, one per word
+	elems{{$i}}, err := decodeFixedBytesElems(errorData, offset, {{fixedArrayLen $input.Type}}, {{bytesElemSize $input.Type}})
+	if err != nil {
+		return result, fmt.Errorf("decoding error parameter {{$input.Name}}: %w", err)
+	}
+	for i, elem := range elems{{$i}} {
+		copy(result.{{$input.Name | title}}[i][:], elem)
+	}
+	offset += {{fixedArrayLen $input.Type}} * 32
+	{{- else if bytesElemSize $input.Type}}
+	arrayOffset{{$i}}, err := decodeOffset(errorData, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding error parameter {{$input.Name}}: %w", err)
+	}
+	length{{$i}}, err := decodeArrayLength(errorData, arrayOffset{{$i}})
+	if err != nil {
+		return result, fmt.Errorf("decoding error parameter {{$input.Name}}: %w", err)
+	}
+	elems{{$i}}, err := decodeFixedBytesElems(errorData, arrayOffset{{$i}}+32, length{{$i}}, {{bytesElemSize $input.Type}})
+	if err != nil {
+		return result, fmt.Errorf("decoding error parameter {{$input.Name}}: %w", err)
+	}
+	result.{{$input.Name | title}} = make({{$input.Type.TypeName}}, length{{$i}})
+	for i, elem := range elems{{$i}} {
+		copy(result.{{$input.Name | title}}[i][:], elem)
+	}
+	offset += 32
+	{{- else if $input.Type.IsStruct}}
+	{{- range $.Contract.Structs}}
+	{{- if eq .Name $input.Type.TypeName}}
+	{{- if .Dynamic}}
+	// Dynamic structs are encoded behind an offset
+	structOffset{{$i}}, err := decodeOffset(errorData, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding error parameter {{$input.Name}}: %w", err)
+	}
+	val{{$i}}, _, err := decode{{.Name}}(errorData, structOffset{{$i}})
+	if err != nil {
+		return result, fmt.Errorf("decoding error parameter {{$input.Name}}: %w", err)
+	}
+	result.{{$input.Name | title}} = val{{$i}}
+	offset += 32
+	{{- else}}
+	val{{$i}}, nextOffset{{$i}}, err := decode{{.Name}}(errorData, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding error parameter {{$input.Name}}: %w", err)
+	}
+	result.{{$input.Name | title}} = val{{$i}}
+	offset = nextOffset{{$i}}
+	{{- end}}
+	{{- end}}
+	{{- end}}
 	{{- else}}
 	return result, errors.New("unsupported error parameter type: {{$input.Type.TypeName}}")
 	{{- end}}
@@ -133,6 +212,33 @@ func Errors() ErrorRegistry {
 	return ErrorRegistry{}
 }
 
+{{- if .Contract.Errors}}
+
+// Error selectors, the first 4 bytes of the revert data of each custom error
+const (
+{{- range .Contract.Errors}}
+	ErrorSelector{{.Name}} HexData = {{.Selector.Hex | quote}}
+{{- end}}
+)
+{{- end}}
+
+// DecodeError decodes revert data into the struct of the custom error whose
+// selector it starts with. Contracts without custom errors reject every selector.
+func DecodeError(data []byte) (any, error) {
+	if len(data) < 4 {
+		return nil, errors.New("insufficient data for error selector")
+	}
+	{{- if .Contract.Errors}}
+	switch HexData("0x" + hex.EncodeToString(data[:4])) {
+	{{- range .Contract.Errors}}
+	case ErrorSelector{{.Name}}:
+		return Errors().{{.Name}}Error().Decode(data)
+	{{- end}}
+	}
+	{{- end}}
+	return nil, fmt.Errorf("unknown error selector 0x%x", data[:4])
+}
+
 {{/* Generate specific error decoder types */}}
 {{- range .Contract.Errors}}
 
@@ -6,7 +6,24 @@ package gen
 const eventDecodersTemplate = `{{/* Generate type-specific decoders for events */}}
 {{- range .Contract.Events}}
 
+// Signature returns the canonical signature of the {{.Name}} event
+func (e *{{.Name}}EventDecoder) Signature() string {
+	return {{.Signature | quote}}
+}
+
+// SignatureHash returns keccak256 of the event signature, the topics[0] of
+// non-anonymous {{.Name}} logs
+func (e *{{.Name}}EventDecoder) SignatureHash() Hash {
+	return e.Topic
+}
+
+{{- if topicFields .}}
+// Decode decodes the non-indexed parameters of a {{.Name}} log from its data.
+// Indexed parameters are held in the topics: Decode leaves {{join (topicFields .) ", "}}
+// zero, and DecodeLog decodes them as well
+{{- else}}
 // Decode decodes log data for {{.Name}} event
+{{- end}}
 func (e *{{.Name}}EventDecoder) Decode(data []byte) ({{.Struct.Name}}, error) {
 	return e.decodeImpl(data)
 }
@@ -20,8 +37,96 @@ func (e *{{.Name}}EventDecoder) MustDecode(data []byte) {{.Struct.Name}} {
 	return result
 }
 
+{{- $topics := 1}}
+{{- range .Inputs}}{{if .Indexed}}{{$topics = add $topics 1}}{{end}}{{end}}
+
+// DecodeLog decodes a {{.Name}} log from its topics and data. Indexed value types
+// are decoded from their topic, indexed parameters of reference types are only
+// available as the keccak256 hash held in their topic.
+func (e *{{.Name}}EventDecoder) DecodeLog(topics []Hash, data []byte) ({{.Struct.Name}}, error) {
+	var result {{.Struct.Name}}
+	if len(topics) != {{$topics}} {
+		return result, fmt.Errorf("expected {{$topics}} topics for event {{.Name}}, got %d", len(topics))
+	}
+	if topics[0] != e.Topic {
+		return result, errors.New("topic does not match event {{.Name}}")
+	}
+
+	result, err := e.decodeImpl(data)
+	if err != nil {
+		return result, err
+	}
+	{{- $topic := 0}}
+	{{- range .Inputs}}
+	{{- if .Indexed}}
+	{{- $topic = add $topic 1}}
+	{{- if .Hashed}}
+	result.{{.Name | title}}Hash = topics[{{$topic}}]
+	{{- else}}
+{{decodeTopic . $topic}}
+	{{- end}}
+	{{- end}}
+	{{- end}}
+	return result, nil
+}
+
+// DecodeLogs decodes the {{.Name}} logs among logs, in order, skipping the logs
+// of other events, whose first topic is not the {{.Name}} topic
+func (e *{{.Name}}EventDecoder) DecodeLogs(logs []RawLog) ([]{{.Struct.Name}}, error) {
+	var results []{{.Struct.Name}}
+	for i, log := range logs {
+		if len(log.Topics) == 0 || log.Topics[0] != e.Topic {
+			continue
+		}
+		result, err := e.DecodeLog(log.Topics, log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding log %d: %w", i, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+{{- $event := .}}
+{{- with erc20Filter .}}
+{{- $from := index . 0}}
+{{- $to := index . 1}}
+
+// {{$event.Name}}Filter returns the topics of an eth_getLogs filter matching ERC20
+// {{$event.Name}} logs by their {{$from}} and {{$to}} addresses. A nil address matches
+// any address.
+func (e *{{$event.Name}}EventDecoder) {{$event.Name}}Filter({{$from}}, {{$to}} *Address) [][]Hash {
+	topics := [][]Hash{ {e.Topic}, nil, nil}
+	for i, addr := range []*Address{ {{- $from}}, {{$to -}} } {
+		if addr != nil {
+			var topic Hash
+			copy(topic[12:], addr[:])
+			topics[i+1] = []Hash{topic}
+		}
+	}
+	return topics
+}
+{{- end}}
+
+{{- if $.Options.Qualified}}
+
+// decodeImpl decodes with decodeUnqualified, prefixing its errors with the
+// contract and event name
+func (e *{{.Name}}EventDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error) {
+	result, err := e.decodeUnqualified(data)
+	if err != nil {
+		return result, fmt.Errorf("{{$.Contract.Name | lower}}.{{.Name}}: %w", err)
+	}
+	return result, nil
+}
+
+// decodeUnqualified contains the actual decode logic
+func (e *{{.Name}}EventDecoder) decodeUnqualified(data []byte) ({{.Struct.Name}}, error) {
+{{- else}}
+
 // decodeImpl contains the actual decode logic
 func (e *{{.Name}}EventDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error) {
+{{- end}}
 	// Decode event parameters (only non-indexed parameters are in data)
 	var result {{.Struct.Name}}
 	{{- $hasNonIndexedParams := false}}
@@ -38,8 +143,18 @@ func (e *{{.Name}}EventDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error
 	{{- $needsValBool := false}}
 	{{- $needsValString := false}}
 	{{- $needsValBytes := false}}
+	{{- $needsDataOffset := false}}
+	{{- $needsFixedElems := false}}
+	{{- $needsFixedLength := false}}
 	{{- range .Inputs}}
 		{{- if not .Indexed}}
+			{{- if bytesElemSize .Type}}
+				{{- $needsFixedElems = true}}
+				{{- if not (fixedArrayLen .Type)}}
+					{{- $needsFixedLength = true}}
+					{{- $needsDataOffset = true}}
+				{{- end}}
+			{{- end}}
 			{{- if eq .Type.TypeName "*big.Int"}}
 				{{- $needsVal = true}}
 			{{- end}}
@@ -57,9 +172,11 @@ func (e *{{.Name}}EventDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error
 			{{- end}}
 			{{- if eq .Type.TypeName "string"}}
 				{{- $needsValString = true}}
+				{{- $needsDataOffset = true}}
 			{{- end}}
 			{{- if eq .Type.TypeName "[]byte"}}
 				{{- $needsValBytes = true}}
+				{{- $needsDataOffset = true}}
 			{{- end}}
 		{{- end}}
 	{{- end}}
@@ -84,6 +201,15 @@ func (e *{{.Name}}EventDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error
 	{{- if $needsValBytes}}
 	var valBytes []byte
 	{{- end}}
+	{{- if $needsDataOffset}}
+	var dataOffset int
+	{{- end}}
+	{{- if $needsFixedElems}}
+	var fixedElems [][]byte
+	{{- end}}
+	{{- if $needsFixedLength}}
+	var fixedLength int
+	{{- end}}
 	var err error
 	offset := 0
 	{{- range $i, $input := .Inputs}}
@@ -126,6 +252,15 @@ func (e *{{.Name}}EventDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error
 	}
 	result.{{$input.Name | title}} = valInt64
 	offset += 32
+	{{- else if or (eq $input.Type.TypeName "uint8") (eq $input.Type.TypeName "uint16") (eq $input.Type.TypeName "uint32") (eq $input.Type.TypeName "int8") (eq $input.Type.TypeName "int16") (eq $input.Type.TypeName "int32")}}
+	if len(data) < offset+32 {
+		return result, errors.New("insufficient data for event parameter {{$input.Name}}")
+	}
+	result.{{$input.Name | title}}, err = decode{{$input.Type.TypeName | title}}(data[offset:offset+32])
+	if err != nil {
+		return result, fmt.Errorf("decoding event parameter {{$input.Name}}: %w", err)
+	}
+	offset += 32
 	{{- else if eq $input.Type.TypeName "bool"}}
 	if len(data) < offset+32 {
 		return result, errors.New("insufficient data for event parameter {{$input.Name}}")
@@ -147,21 +282,55 @@ func (e *{{.Name}}EventDecoder) decodeImpl(data []byte) ({{.Struct.Name}}, error
 	result.{{$input.Name | title}} = valAddr
 	offset += 32
 	{{- else if eq $input.Type.TypeName "string"}}
-	var nextOffset int
-	valString, nextOffset, err = decodeString(data, offset)
+	dataOffset, err = decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding event parameter {{$input.Name}}: %w", err)
+	}
+	valString, _, err = decodeString(data, dataOffset)
 	if err != nil {
 		return result, fmt.Errorf("decoding event parameter {{$input.Name}}: %w", err)
 	}
 	result.{{$input.Name | title}} = valString
-	offset = nextOffset
+	offset += 32
 	{{- else if eq $input.Type.TypeName "[]byte"}}
-	var nextOffset int
-	valBytes, nextOffset, err = decodeBytes(data, offset)
+	dataOffset, err = decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding event parameter {{$input.Name}}: %w", err)
+	}
+	valBytes, _, err = decodeBytes(data, dataOffset)
 	if err != nil {
 		return result, fmt.Errorf("decoding event parameter {{$input.Name}}: %w", err)
 	}
 	result.{{$input.Name | title}} = valBytes
-	offset = nextOffset
+	offset += 32
+	{{- else if and (bytesElemSize $input.Type) (fixedArrayLen $input.Type)}}
+	// {{$input.ABIType}} elements are stored in place, one per word
+	fixedElems, err = decodeFixedBytesElems(data, offset, {{fixedArrayLen $input.Type}}, {{bytesElemSize $input.Type}})
+	if err != nil {
+		return result, fmt.Errorf("decoding event parameter {{$input.Name}}: %w", err)
+	}
+	for i, elem := range fixedElems {
+		copy(result.{{$input.Name | title}}[i][:], elem)
+	}
+	offset += {{fixedArrayLen $input.Type}} * 32
+	{{- else if bytesElemSize $input.Type}}
+	dataOffset, err = decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding event parameter {{$input.Name}}: %w", err)
+	}
+	fixedLength, err = decodeArrayLength(data, dataOffset)
+	if err != nil {
+		return result, fmt.Errorf("decoding event parameter {{$input.Name}}: %w", err)
+	}
+	fixedElems, err = decodeFixedBytesElems(data, dataOffset+32, fixedLength, {{bytesElemSize $input.Type}})
+	if err != nil {
+		return result, fmt.Errorf("decoding event parameter {{$input.Name}}: %w", err)
+	}
+	result.{{$input.Name | title}} = make({{$input.Type.TypeName}}, fixedLength)
+	for i, elem := range fixedElems {
+		copy(result.{{$input.Name | title}}[i][:], elem)
+	}
+	offset += 32
 	{{- else}}
 	return result, errors.New("unsupported event parameter type: {{$input.Type.TypeName}}")
 	{{- end}}
@@ -3,9 +3,13 @@
 package gen
 
 import (
+	"fmt"
+	"go/token"
 	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/otherview/solgen/internal/types"
 )
@@ -14,31 +18,464 @@ import (
 type TemplateData struct {
 	Contract *types.Contract
 	Imports  []string
+	Options  Options
+	ABIFile  string // Name of the embedded ABI file with Options.EmbedABI
 }
 
 // templateFuncs returns template helper functions
 func templateFuncs() template.FuncMap {
 	return template.FuncMap{
-		"formatGoType": formatGoType,
-		"quote":        strconv.Quote,
-		"lower":        strings.ToLower,
-		"title":        titleCase,
-		"join":         strings.Join,
-		"add":          func(a, b int) int { return a + b },
-		"default":      func(def, val string) string { if val == "" { return def }; return val },
-		"hasPrefix":    strings.HasPrefix,
+		"formatGoType":  formatGoType,
+		"quote":         strconv.Quote,
+		"stringLit":     stringLiteral,
+		"lower":         strings.ToLower,
+		"title":         titleCase,
+		"join":          strings.Join,
+		"add":           func(a, b int) int { return a + b },
+		"default":       func(def, val string) string { if val == "" { return def }; return val },
+		"hasPrefix":     strings.HasPrefix,
+		"byteArray":     byteArray,
+		"convertType":   convertType,
+		"equalExpr":     equalExpr,
+		"zeroExpr":      zeroExpr,
+		"isUintType":    isUintType,
+		"encodeExpr":    encodeExpr,
+		"structScope":   newStructScope,
+		"hasTupleInput": hasTupleInput,
+		"inputArgs":     inputArgs,
+		"argName":       argName,
+		"decodeTopic":   decodeTopic,
+		"callSize":      callSize,
+		"arrayDecoder":  arrayDecoder,
+		"hasFound":      hasFound,
+		"topicFields":   topicFields,
+		"bytesElemSize": bytesElemSize,
+		"fixedArrayLen": fixedArrayLen,
+		"erc20Filter":   erc20Filter,
+		"eip712Domain":  eip712Domain,
 	}
 }
 
 // formatGoType formats a GoType for use in generated code
 func formatGoType(goType types.GoType) string {
+	if goType.CustomType != "" {
+		return goType.CustomType
+	}
 	return goType.TypeName
 }
 
+// convertType converts a decoded value expression to the custom type of goType, if any
+func convertType(goType types.GoType, expr string) string {
+	if goType.CustomType != "" {
+		return goType.CustomType + "(" + expr + ")"
+	}
+	return expr
+}
+
+// stringLiteral returns s as a Go string literal, preferring a raw string so
+// that large JSON values such as ABIs are not bloated by escaped quotes
+func stringLiteral(s string) string {
+	if !utf8.ValidString(s) {
+		return strconv.Quote(s)
+	}
+	for _, r := range s {
+		// Backquotes end raw strings, carriage returns are dropped from them,
+		// the compiler rejects byte order marks and control characters are
+		// hard to read
+		if r == '`' || r == '\r' || r == '\uFEFF' || (unicode.IsControl(r) && r != '\n' && r != '\t') {
+			return strconv.Quote(s)
+		}
+	}
+	return "`" + s + "`"
+}
+
 // titleCase provides a simple title case conversion
 func titleCase(s string) string {
 	if s == "" {
 		return s
 	}
 	return strings.ToUpper(s[:1]) + s[1:]
-}
\ No newline at end of file
+}
+
+// byteArray formats hex data as the elements of a Go byte array literal, e.g. "0xa9, 0x05"
+func byteArray(data types.HexData) string {
+	var elems []string
+	for _, b := range data.Bytes() {
+		elems = append(elems, fmt.Sprintf("0x%02x", b))
+	}
+	return strings.Join(elems, ", ")
+}
+
+// equalExpr returns a boolean Go expression comparing the values a and b of goType
+func equalExpr(goType types.GoType, a, b string) string {
+	switch {
+	case goType.CustomType != "" || goType.IsEnum:
+		return a + " == " + b
+	case goType.IsStruct:
+		return a + ".Equal(" + b + ")"
+	}
+	return equalTypeExpr(goType.TypeName, a, b)
+}
+
+// equalTypeExpr compares values of a Go type name, looping over non-comparable arrays and slices
+func equalTypeExpr(typeName, a, b string) string {
+	switch {
+	case typeName == "*big.Int":
+		return fmt.Sprintf("((%[1]s == nil) == (%[2]s == nil) && (%[1]s == nil || %[1]s.Cmp(%[2]s) == 0))", a, b)
+	case typeName == "[]byte":
+		return fmt.Sprintf("string(%s) == string(%s)", a, b)
+	case isComparableType(typeName):
+		return a + " == " + b
+	case !strings.HasPrefix(typeName, "["):
+		// Remaining names are generated structs
+		return a + ".Equal(" + b + ")"
+	}
+
+	elem := typeName[strings.Index(typeName, "]")+1:]
+	i := loopVar(a)
+	return fmt.Sprintf("func() bool {\n\t\tif len(%[1]s) != len(%[2]s) {\n\t\t\treturn false\n\t\t}\n\t\tfor %[4]s := range %[1]s {\n\t\t\tif !(%[3]s) {\n\t\t\t\treturn false\n\t\t\t}\n\t\t}\n\t\treturn true\n\t}()",
+		a, b, equalTypeExpr(elem, a+"["+i+"]", b+"["+i+"]"), i)
+}
+
+// zeroExpr returns a boolean Go expression reporting whether the value v of goType is zero.
+// Big integers count as zero when nil or 0, slices when empty.
+func zeroExpr(goType types.GoType, v string) string {
+	switch {
+	case goType.CustomType != "":
+		return fmt.Sprintf("%s == *new(%s)", v, goType.CustomType)
+	case goType.IsEnum:
+		return v + " == 0"
+	case goType.IsStruct:
+		return v + ".IsZero()"
+	}
+	return zeroTypeExpr(goType.TypeName, v)
+}
+
+// zeroTypeExpr reports whether a value of a Go type name is zero
+func zeroTypeExpr(typeName, v string) string {
+	switch {
+	case typeName == "*big.Int":
+		return fmt.Sprintf("(%[1]s == nil || %[1]s.Sign() == 0)", v)
+	case typeName == "string":
+		return v + ` == ""`
+	case typeName == "bool":
+		return "!" + v
+	case strings.HasPrefix(typeName, "[]"):
+		return "len(" + v + ") == 0"
+	case strings.HasPrefix(typeName, "["):
+		if isComparableType(typeName) {
+			return v + " == " + typeName + "{}"
+		}
+		elem := typeName[strings.Index(typeName, "]")+1:]
+		i := loopVar(v)
+		return fmt.Sprintf("func() bool {\n\t\tfor %[3]s := range %[1]s {\n\t\t\tif !(%[2]s) {\n\t\t\t\treturn false\n\t\t\t}\n\t\t}\n\t\treturn true\n\t}()",
+			v, zeroTypeExpr(elem, v+"["+i+"]"), i)
+	case typeName == "Address" || typeName == "Hash":
+		return v + " == " + typeName + "{}"
+	case isNumericType(typeName):
+		return v + " == 0"
+	}
+	// Remaining names are generated structs
+	return v + ".IsZero()"
+}
+
+// isUintType reports whether a canonical ABI type is a single unsigned integer
+func isUintType(abiType string) bool {
+	return strings.HasPrefix(abiType, "uint") && !strings.Contains(abiType, "[")
+}
+
+// structScope is the data of the struct encoder and decoder templates: the
+// struct, the contract structs its fields may refer to and whether arrays are
+// decoded with the typed --tinygo decoders
+type structScope struct {
+	Struct  types.Struct
+	Structs []types.Struct
+	TinyGo  bool
+}
+
+// newStructScope returns the template data for encoding or decoding s
+func newStructScope(s types.Struct, structs []types.Struct, tinyGo bool) structScope {
+	return structScope{Struct: s, Structs: structs, TinyGo: tinyGo}
+}
+
+// arrayDecoder returns the --tinygo decoder of an array type, or "" when
+// the type has none
+func arrayDecoder(t types.GoType) string {
+	switch t.TypeName {
+	case "[]*big.Int":
+		if t.IsSigned {
+			return "decodeInt256Array"
+		}
+		return "decodeUint256Array"
+	case "[]uint64":
+		return "decodeUint64Array"
+	case "[]Address":
+		return "decodeAddressArray"
+	case "[]bool":
+		return "decodeBoolArray"
+	case "[][32]byte":
+		return "decodeBytes32Array"
+	}
+	return ""
+}
+
+// bytesElemSize returns N for a one-dimensional bytesN array type, such as
+// [][4]byte or [2][32]byte, or 0 for any other type
+func bytesElemSize(t types.GoType) int {
+	dims := strings.Count(t.TypeName, "[")
+	if dims != 2 || !strings.HasSuffix(t.TypeName, "]byte") {
+		return 0
+	}
+	elem := t.TypeName[strings.LastIndex(t.TypeName, "["):]
+	size, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(elem, "["), "]byte"))
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// fixedArrayLen returns the length of a fixed-size array type, such as
+// [2][32]byte, or 0 for slices and other types
+func fixedArrayLen(t types.GoType) int {
+	if !strings.HasPrefix(t.TypeName, "[") {
+		return 0
+	}
+	length, err := strconv.Atoi(t.TypeName[1:strings.Index(t.TypeName, "]")])
+	if err != nil {
+		return 0
+	}
+	return length
+}
+
+// hasTupleInput reports whether a method takes a tuple, possibly in an array,
+// and therefore gets typed Pack and UnpackInput methods
+func hasTupleInput(method types.Method) bool {
+	for _, input := range method.Inputs {
+		if strings.Contains(input.ABIType, "(") {
+			return true
+		}
+	}
+	return false
+}
+
+// callSize returns the calldata size of a method, selector included, or -1
+// when a dynamic argument makes it depend on the argument values
+func callSize(method types.Method) int {
+	size := 4
+	for _, input := range method.Inputs {
+		if input.Dynamic {
+			return -1
+		}
+		size += input.Size
+	}
+	return size
+}
+
+// hasFound reports whether a method looks like the getter of a mapping of
+// structs, a view taking keys and returning a struct or several values, and
+// therefore gets DecodeFound with --getters-with-found
+func hasFound(method types.Method) bool {
+	if !method.View || len(method.Inputs) == 0 {
+		return false
+	}
+	return len(method.Outputs) > 1 || (len(method.Outputs) == 1 && method.Outputs[0].Type.IsStruct)
+}
+
+// topicFields returns the event struct fields DecodeLog fills from the topics:
+// indexed value types, and the <Field>Hash of indexed reference types
+func topicFields(event types.Event) []string {
+	var fields []string
+	for _, input := range event.Inputs {
+		switch {
+		case input.Hashed:
+			fields = append(fields, titleCase(input.Name)+"Hash")
+		case input.Indexed:
+			fields = append(fields, titleCase(input.Name))
+		}
+	}
+	return fields
+}
+
+// erc20Filter returns the parameter names of the topic filter helper of the
+// standard ERC20 Transfer and Approval events: two indexed addresses followed by
+// a non-indexed uint256. It returns nil for any other event.
+func erc20Filter(event types.Event) []string {
+	var names []string
+	switch event.Signature {
+	case "Transfer(address,address,uint256)":
+		names = []string{"from", "to"}
+	case "Approval(address,address,uint256)":
+		names = []string{"owner", "spender"}
+	default:
+		return nil
+	}
+	// ERC721 indexes the token id as well, its logs have four topics
+	if !event.Inputs[0].Indexed || !event.Inputs[1].Indexed || event.Inputs[2].Indexed {
+		return nil
+	}
+	return names
+}
+
+// eip5267DomainTypes are the return types of the EIP-5267 eip712Domain() method
+var eip5267DomainTypes = []string{"bytes1", "string", "string", "uint256", "address", "bytes32", "uint256[]"}
+
+// eip712Domain returns the eip712Domain() method of a contract when it returns
+// the EIP-5267 domain fields, nil otherwise
+func eip712Domain(contract *types.Contract) *types.Method {
+	for i := range contract.Methods {
+		method := &contract.Methods[i]
+		if method.Signature != "eip712Domain()" || len(method.Outputs) != len(eip5267DomainTypes) {
+			continue
+		}
+		for j, output := range method.Outputs {
+			if output.ABIType != eip5267DomainTypes[j] {
+				return nil
+			}
+		}
+		return method
+	}
+	return nil
+}
+
+// inputArgs returns the struct holding the arguments of a method, encoded as a
+// tuple. Methods with several inputs use their input struct, a single input is
+// wrapped in an input struct of one field.
+func inputArgs(method types.Method) types.Struct {
+	if method.InputStruct != nil {
+		return *method.InputStruct
+	}
+	args := types.Struct{Name: titleCase(method.Name) + "Input"}
+	for _, input := range method.Inputs {
+		args.Fields = append(args.Fields, types.StructField{
+			Name:    titleCase(input.Name),
+			Type:    input.Type,
+			Dynamic: input.Dynamic,
+		})
+		args.Dynamic = args.Dynamic || input.Dynamic
+	}
+	return args
+}
+
+// argName returns the Go parameter name of input i, falling back to argN for
+// names that would shadow a keyword, an import or the locals of Pack
+func argName(name string, i int) string {
+	switch name {
+	case "", "m", "data", "err", "result", "sel", "packed", "hex", "errors", "fmt", "io", "big", "reflect", "strings":
+		return fmt.Sprintf("arg%d", i)
+	}
+	if token.IsKeyword(name) {
+		return fmt.Sprintf("arg%d", i)
+	}
+	return name
+}
+
+// decodeTopic returns the statements of DecodeLog decoding the indexed event
+// parameter held in topics[topic] into its result field. Value types are stored
+// in their topic as their 32-byte ABI encoding: right-aligned for numbers,
+// addresses and bools, left-aligned for fixed bytes.
+func decodeTopic(param types.Parameter, topic int) string {
+	field := "result." + titleCase(param.Name)
+	value := fmt.Sprintf("topic%d", topic)
+	word := fmt.Sprintf("topics[%d][:]", topic)
+
+	var decode string
+	switch typeName := param.Type.TypeName; {
+	case param.Type.IsEnum:
+		decode, value = "decodeUint8", typeName+"("+value+")"
+	case typeName == "Hash":
+		return fmt.Sprintf("\t%s = %s", field, convertType(param.Type, fmt.Sprintf("topics[%d]", topic)))
+	case strings.HasPrefix(typeName, "[") && strings.HasSuffix(typeName, "]byte"):
+		return fmt.Sprintf("\tvar %[1]s %[2]s\n\tcopy(%[1]s[:], %[3]s)\n\t%[4]s = %[5]s",
+			value, typeName, word, field, convertType(param.Type, value))
+	case typeName == "*big.Int" && param.Type.IsSigned:
+		decode = "decodeInt256"
+	case typeName == "*big.Int":
+		decode = "decodeUint256"
+	case typeName == "Address" || typeName == "bool" || isNumericType(typeName):
+		decode = "decode" + titleCase(typeName)
+	default:
+		return fmt.Sprintf("\treturn result, errors.New(\"unsupported indexed event parameter type: %s\")", typeName)
+	}
+
+	return fmt.Sprintf("\ttopic%[1]d, err := %[2]s(%[3]s)\n\tif err != nil {\n\t\treturn result, fmt.Errorf(\"decoding indexed event parameter %[4]s: %%w\", err)\n\t}\n\t%[5]s = %[6]s",
+		topic, decode, word, param.Name, field, convertType(param.Type, value))
+}
+
+// encodeExpr returns a Go expression ABI-encoding the value v of goType, of type ([]byte, error)
+func encodeExpr(goType types.GoType, v string, structs []types.Struct) string {
+	switch {
+	case goType.CustomType != "":
+		return encodeTypeExpr(goType.TypeName, "("+goType.TypeName+")("+v+")", structs)
+	case goType.IsEnum:
+		return "encodeArg(uint8(" + v + "))"
+	}
+	return encodeTypeExpr(goType.TypeName, v, structs)
+}
+
+// encodeTypeExpr ABI-encodes a value of a Go type name, looping over arrays and slices
+func encodeTypeExpr(typeName, v string, structs []types.Struct) string {
+	switch {
+	case typeName == "[]byte":
+		return "encodeArg(" + v + ")"
+	case strings.HasPrefix(typeName, "["):
+		elem := typeName[strings.Index(typeName, "]")+1:]
+		if elem == "byte" {
+			return "encodeFixedBytes(" + v + "[:])"
+		}
+		encode := "encodeArray"
+		if strings.HasPrefix(typeName, "[]") {
+			encode = "encodeSlice"
+		}
+		i := loopVar(v)
+		return fmt.Sprintf("%s(len(%s), func(%s int) ([]byte, error) { return %s }, %t)",
+			encode, v, i, encodeTypeExpr(elem, v+"["+i+"]", structs), isDynamicTypeName(elem, structs))
+	case typeName == "int8" || typeName == "int16" || typeName == "int32":
+		return "encodeArg(int64(" + v + "))"
+	case isComparableType(typeName) || typeName == "*big.Int":
+		return "encodeArg(" + v + ")"
+	}
+	// Remaining names are generated structs
+	return "encode" + typeName + "(" + v + ")"
+}
+
+// isDynamicTypeName reports whether values of a Go type name are ABI-encoded behind an offset
+func isDynamicTypeName(typeName string, structs []types.Struct) bool {
+	switch {
+	case typeName == "string" || strings.HasPrefix(typeName, "[]"):
+		return true
+	case strings.HasPrefix(typeName, "["):
+		return isDynamicTypeName(typeName[strings.Index(typeName, "]")+1:], structs)
+	}
+	for _, s := range structs {
+		if s.Name == typeName {
+			return s.Dynamic
+		}
+	}
+	return false
+}
+
+// loopVar names the index variable for looping over v, unique per nesting depth
+func loopVar(v string) string {
+	return fmt.Sprintf("i%d", strings.Count(v, "["))
+}
+
+// isComparableType reports whether values of a Go type name can be compared with ==
+func isComparableType(typeName string) bool {
+	switch {
+	case typeName == "*big.Int", strings.HasPrefix(typeName, "[]"):
+		return false
+	case strings.HasPrefix(typeName, "["):
+		return isComparableType(typeName[strings.Index(typeName, "]")+1:])
+	}
+	return typeName == "byte" || typeName == "string" || typeName == "bool" ||
+		typeName == "Address" || typeName == "Hash" || isNumericType(typeName)
+}
+
+// isNumericType reports whether a Go type name is a fixed-size integer
+func isNumericType(typeName string) bool {
+	switch typeName {
+	case "uint8", "uint16", "uint32", "uint64", "int8", "int16", "int32", "int64":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: MIT
+
+package gen
+
+// keccakRuntimeTemplate contains a dependency-free Keccak-256, generated with --with-keccak
+const keccakRuntimeTemplate = `// keccakRoundConstants are the iota constants of the 24 Keccak-f[1600] rounds
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotations are the rho rotation offsets of each lane, indexed by x+5y
+var keccakRotations = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// keccakF1600 applies the Keccak-f[1600] permutation to the state
+func keccakF1600(a *[25]uint64) {
+	var c [5]uint64
+	var b [25]uint64
+	for round := 0; round < 24; round++ {
+		// Theta
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[x+y] ^= d
+			}
+		}
+		// Rho and pi
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = bits.RotateLeft64(a[x+5*y], keccakRotations[x+5*y])
+			}
+		}
+		// Chi
+		for y := 0; y < 25; y += 5 {
+			for x := 0; x < 5; x++ {
+				a[x+y] = b[x+y] ^ (^b[(x+1)%5+y] & b[(x+2)%5+y])
+			}
+		}
+		// Iota
+		a[0] ^= keccakRoundConstants[round]
+	}
+}
+
+// Keccak256 returns the Ethereum Keccak-256 hash of the concatenated data
+func Keccak256(data ...[]byte) Hash {
+	const rate = 136
+
+	var input []byte
+	for _, d := range data {
+		input = append(input, d...)
+	}
+
+	// Pad with the original Keccak domain byte, not the SHA-3 one
+	padded := make([]byte, (len(input)/rate+1)*rate)
+	copy(padded, input)
+	padded[len(input)] ^= 0x01
+	padded[len(padded)-1] ^= 0x80
+
+	var state [25]uint64
+	for block := padded; len(block) > 0; block = block[rate:] {
+		for i := 0; i < rate/8; i++ {
+			state[i] ^= binary.LittleEndian.Uint64(block[8*i:])
+		}
+		keccakF1600(&state)
+	}
+
+	var hash Hash
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(hash[8*i:], state[i])
+	}
+	return hash
+}
+
+// SelectorOf computes the 4-byte selector of a canonical function signature,
+// e.g. SelectorOf("transfer(address,uint256)") is "0xa9059cbb", to call
+// functions that are not in the ABI
+func SelectorOf(signature string) HexData {
+	hash := Keccak256([]byte(signature))
+	return HexData("0x" + hex.EncodeToString(hash[:4]))
+}`
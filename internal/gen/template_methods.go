@@ -5,6 +5,14 @@ package gen
 // methodRegistryTemplate generates the method registry and method types
 const methodRegistryTemplate = `{{- range .Contract.Methods}}
 // {{.Name | title}}Method returns a packable method for {{.Name}}
+// {{.Signature}} [{{.Selector.Hex}}]
+{{- if ne .Name .BaseName}}
+// Renamed from {{.BaseName}}, which is overloaded in Solidity
+{{- end}}
+{{- if .Deprecated}}
+//
+// Deprecated: {{.Deprecated}}
+{{- end}}
 func (mr MethodRegistry) {{.Name | title}}Method() *{{.Name | title}}Method {
 	return &{{.Name | title}}Method{
 		PackableMethod: PackableMethod{
@@ -14,6 +22,18 @@ func (mr MethodRegistry) {{.Name | title}}Method() *{{.Name | title}}Method {
 		},
 	}
 }
+{{- if .DefaultOverload}}
+
+// {{.BaseName | title}}Method returns the {{.Signature}} overload of {{.BaseName}},
+// the other overloads are available through their suffixed accessors
+{{- if .Deprecated}}
+//
+// Deprecated: {{.Deprecated}}
+{{- end}}
+func (mr MethodRegistry) {{.BaseName | title}}Method() *{{.Name | title}}Method {
+	return mr.{{.Name | title}}Method()
+}
+{{- end}}
 {{- end}}
 
 // Methods returns the method registry
@@ -21,6 +41,75 @@ func Methods() MethodRegistry {
 	return MethodRegistry{}
 }
 
+// methodsByName indexes every method by its generated name, and the default
+// overloads also by their Solidity name
+var methodsByName = map[string]PackableMethod{
+{{- range .Contract.Methods}}
+	{{.Name | quote}}: {Name: {{.Name | quote}}, Signature: {{.Signature | quote}}, Selector: HexData({{.Selector.Hex | quote}})},
+{{- if .DefaultOverload}}
+	{{.BaseName | quote}}: {Name: {{.Name | quote}}, Signature: {{.Signature | quote}}, Selector: HexData({{.Selector.Hex | quote}})},
+{{- end}}
+{{- end}}
+}
+
+// methodsBySelector indexes every method by its 4-byte selector
+var methodsBySelector = map[[4]byte]PackableMethod{
+{{- range .Contract.Methods}}
+	{ {{- byteArray .Selector -}} }: methodsByName[{{.Name | quote}}],
+{{- end}}
+}
+
+// ByName returns the method with the given name. Overloaded methods are found by
+// their generated names, and their Solidity name returns the default overload,
+// like the accessor named after it
+func (mr MethodRegistry) ByName(name string) (PackableMethod, bool) {
+	method, ok := methodsByName[name]
+	return method, ok
+}
+
+// BySelector returns the method with the given 4-byte selector
+func (mr MethodRegistry) BySelector(selector [4]byte) (PackableMethod, bool) {
+	method, ok := methodsBySelector[selector]
+	return method, ok
+}
+
+// callSizes holds the calldata size, selector included, of the methods taking only static arguments
+var callSizes = map[[4]byte]int{
+{{- range .Contract.Methods}}
+{{- $size := callSize .}}
+{{- if ge $size 0}}
+	{ {{- byteArray .Selector -}} }: {{$size}},
+{{- end}}
+{{- end}}
+}
+
+// SplitCalls splits concatenated calldata, as sent by some batchers, into its
+// calls. Each call must be to a method taking only static arguments, whose
+// calldata size follows from its selector.
+func SplitCalls(data []byte) ([]HexData, error) {
+	var calls []HexData
+	for offset := 0; offset < len(data); {
+		if len(data)-offset < 4 {
+			return nil, fmt.Errorf("truncated selector at offset %d", offset)
+		}
+		var selector [4]byte
+		copy(selector[:], data[offset:])
+		size, ok := callSizes[selector]
+		if !ok {
+			if method, known := methodsBySelector[selector]; known {
+				return nil, fmt.Errorf("cannot split %s at offset %d: dynamic arguments", method.Name, offset)
+			}
+			return nil, fmt.Errorf("unknown selector 0x%x at offset %d", selector, offset)
+		}
+		if len(data)-offset < size {
+			return nil, fmt.Errorf("truncated call to %s at offset %d: expected %d bytes, got %d", methodsBySelector[selector].Name, offset, size, len(data)-offset)
+		}
+		calls = append(calls, HexData("0x"+hex.EncodeToString(data[offset:offset+size])))
+		offset += size
+	}
+	return calls, nil
+}
+
 {{/* Generate specific method types */}}
 {{- range .Contract.Methods}}
 
@@ -28,6 +117,19 @@ func Methods() MethodRegistry {
 type {{.Name | title}}Method struct {
 	PackableMethod
 }
+
+// Args describes the {{.Name}} arguments as declared in the ABI
+func (m *{{.Name | title}}Method) Args() []ArgDescriptor {
+{{- if .Inputs}}
+	return []ArgDescriptor{
+{{- range .Inputs}}
+		{Name: {{.Name | quote}}, Type: {{.ABIType | quote}}, GoType: {{formatGoType .Type | quote}}},
+{{- end}}
+	}
+{{- else}}
+	return nil
+{{- end}}
+}
 {{- end}}`
 
 // methodDecodersTemplate generates method decode functions
@@ -49,8 +151,51 @@ func (m *{{.Name | title}}Method) MustDecode(data []byte) {{if eq (len .Outputs)
 	return result
 }
 
+{{- if and $.Options.WithFound (hasFound .)}}
+
+// DecodeFound decodes return values for {{.Name}} method and reports whether they
+// are set. Mappings return zero values for missing keys, so found is false when
+// every returned value is zero.
+func (m *{{.Name | title}}Method) DecodeFound(data []byte) ({{if eq (len .Outputs) 1}}{{$output := index .Outputs 0}}{{formatGoType $output.Type}}{{else}}{{.Name | title}}Result{{end}}, bool, error) {
+	result, err := m.decodeImpl(data)
+	if err != nil {
+		return result, false, err
+	}
+	return result, !result.IsZero(), nil
+}
+{{- end}}
+
+{{- if eq (len .Outputs) 1}}
+{{- $output := index .Outputs 0}}
+{{- if or (eq $output.Type.TypeName "string") (eq $output.Type.TypeName "[]byte")}}
+
+// DecodeReader decodes the {{.Name}} return value from r without loading it into
+// memory, returning a reader over its content and the content length in bytes
+func (m *{{.Name | title}}Method) DecodeReader(r io.Reader) (io.Reader, uint64, error) {
+	return decodeBytesReader(r)
+}
+{{- end}}
+{{- end}}
+
+{{- if $.Options.Qualified}}
+
+// decodeImpl decodes with decodeUnqualified, prefixing its errors with the
+// contract and method name
+func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs) 1}}{{$output := index .Outputs 0}}{{formatGoType $output.Type}}{{else}}{{.Name | title}}Result{{end}}, error) {
+	result, err := m.decodeUnqualified(data)
+	if err != nil {
+		return result, fmt.Errorf("{{$.Contract.Name | lower}}.{{.Name}}: %w", err)
+	}
+	return result, nil
+}
+
+// decodeUnqualified contains the actual decode logic
+func (m *{{.Name | title}}Method) decodeUnqualified(data []byte) ({{if eq (len .Outputs) 1}}{{$output := index .Outputs 0}}{{formatGoType $output.Type}}{{else}}{{.Name | title}}Result{{end}}, error) {
+{{- else}}
+
 // decodeImpl contains the actual decode logic
 func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs) 1}}{{$output := index .Outputs 0}}{{formatGoType $output.Type}}{{else}}{{.Name | title}}Result{{end}}, error) {
+{{- end}}
 {{- if eq (len .Outputs) 1}}
 	// Single return value - use unified decoding approach
 	offset := 0
@@ -74,6 +219,15 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 		return 0, errors.New("insufficient data for return value")
 	}
 	return decodeUint8(data[offset:offset+32])
+	{{- else if $output.Type.IsEnum}}
+	if len(data) < offset+32 {
+		return 0, errors.New("insufficient data for return value")
+	}
+	val, err := decodeUint8(data[offset:offset+32])
+	if err != nil {
+		return 0, err
+	}
+	return {{$output.Type.TypeName}}(val), nil
 	{{- else if eq $output.Type.TypeName "uint16"}}
 	if len(data) < offset+32 {
 		return 0, errors.New("insufficient data for return value")
@@ -93,29 +247,17 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 	if len(data) < offset+32 {
 		return 0, errors.New("insufficient data for return value")
 	}
-	val, err := decodeInt64(data[offset:offset+32])
-	if err != nil {
-		return 0, err
-	}
-	return int8(val), nil
+	return decodeInt8(data[offset:offset+32])
 	{{- else if eq $output.Type.TypeName "int16"}}
 	if len(data) < offset+32 {
 		return 0, errors.New("insufficient data for return value")
 	}
-	val, err := decodeInt64(data[offset:offset+32])
-	if err != nil {
-		return 0, err
-	}
-	return int16(val), nil
+	return decodeInt16(data[offset:offset+32])
 	{{- else if eq $output.Type.TypeName "int32"}}
 	if len(data) < offset+32 {
 		return 0, errors.New("insufficient data for return value")
 	}
-	val, err := decodeInt64(data[offset:offset+32])
-	if err != nil {
-		return 0, err
-	}
-	return int32(val), nil
+	return decodeInt32(data[offset:offset+32])
 	{{- else if eq $output.Type.TypeName "bool"}}
 	if len(data) < offset+32 {
 		return false, errors.New("insufficient data for return value")
@@ -132,9 +274,17 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 	}
 	return decodeHash(data[offset:offset+32])
 	{{- else if eq $output.Type.TypeName "string"}}
+	offset, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return "", fmt.Errorf("decoding return value: %w", err)
+	}
 	result, _, err := decodeString(data, offset)
 	return result, err
 	{{- else if eq $output.Type.TypeName "[]byte"}}
+	offset, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return nil, fmt.Errorf("decoding return value: %w", err)
+	}
 	result, _, err := decodeBytes(data, offset)
 	return result, err
 	{{- else if eq $output.Type.TypeName "[1]byte"}}
@@ -147,11 +297,21 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 		return [32]byte{}, errors.New("insufficient data for return value")
 	}
 	return decodeBytes32(data[offset:offset+32])
+	{{- else if and $.Options.TinyGo (arrayDecoder $output.Type)}}
+	// Handle {{$output.Type.TypeName}} array, encoded behind an offset
+	arrayOffset, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return nil, fmt.Errorf("decoding return value: %w", err)
+	}
+	result, _, err := {{arrayDecoder $output.Type}}(data, arrayOffset)
+	return result, err
 	{{- else if eq $output.Type.TypeName "[]*big.Int"}}
-	// Handle []*big.Int array
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, offset, decodeUint256ArrayElement)
+	// Handle []*big.Int array, encoded behind an offset
+	arrayOffset, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return nil, fmt.Errorf("decoding return value: %w", err)
+	}
+	elems, _, err := decodeArray(data, arrayOffset, {{if $output.Type.IsSigned}}decodeInt256ArrayElement{{else}}decodeUint256ArrayElement{{end}})
 	if err != nil {
 		return nil, err
 	}
@@ -161,10 +321,12 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 	}
 	return result, nil
 	{{- else if eq $output.Type.TypeName "[]uint64"}}
-	// Handle []uint64 array
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, offset, func(d []byte) (interface{}, error) { return decodeUint64(d) })
+	// Handle []uint64 array, encoded behind an offset
+	arrayOffset, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return nil, fmt.Errorf("decoding return value: %w", err)
+	}
+	elems, _, err := decodeArray(data, arrayOffset, func(d []byte) (interface{}, error) { return decodeUint64(d) })
 	if err != nil {
 		return nil, err
 	}
@@ -174,10 +336,12 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 	}
 	return result, nil
 	{{- else if eq $output.Type.TypeName "[]Address"}}
-	// Handle []Address array
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, offset, decodeAddressArrayElement)
+	// Handle []Address array, encoded behind an offset
+	arrayOffset, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return nil, fmt.Errorf("decoding return value: %w", err)
+	}
+	elems, _, err := decodeArray(data, arrayOffset, decodeAddressArrayElement)
 	if err != nil {
 		return nil, err
 	}
@@ -187,10 +351,12 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 	}
 	return result, nil
 	{{- else if eq $output.Type.TypeName "[]bool"}}
-	// Handle []bool array
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, offset, decodeBoolArrayElement)
+	// Handle []bool array, encoded behind an offset
+	arrayOffset, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return nil, fmt.Errorf("decoding return value: %w", err)
+	}
+	elems, _, err := decodeArray(data, arrayOffset, decodeBoolArrayElement)
 	if err != nil {
 		return nil, err
 	}
@@ -199,63 +365,81 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 		result[i] = elem.(bool)
 	}
 	return result, nil
+	{{- else if eq $output.Type.TypeName "[][32]byte"}}
+	// Handle [][32]byte array, encoded behind an offset
+	arrayOffset, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return nil, fmt.Errorf("decoding return value: %w", err)
+	}
+	elems, _, err := decodeArray(data, arrayOffset, decodeBytes32ArrayElement)
+	if err != nil {
+		return nil, err
+	}
+	result := make([][32]byte, len(elems))
+	for i, elem := range elems {
+		result[i] = elem.([32]byte)
+	}
+	return result, nil
 	{{- else}}
-	// Handle struct types
+	{{- $decoded := false}}
 	{{- range $.Contract.Structs}}
 	{{- if eq .Name $output.Type.TypeName}}
+	{{- $decoded = true}}
+	// Handle struct types
+	{{- if .Dynamic}}
+	// Dynamic structs are encoded behind an offset
+	structOffset, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return {{.Name}}{}, fmt.Errorf("decoding return value: %w", err)
+	}
+	result, _, err := decode{{.Name}}(data, structOffset)
+	{{- else}}
 	result, _, err := decode{{.Name}}(data, offset)
+	{{- end}}
 	return result, err
 	{{- end}}
 	{{- end}}
-	// Handle struct array types
 	{{- if and $output.Type.IsSlice (gt (len $output.Type.TypeName) 2)}}
 	{{- $elemType := slice $output.Type.TypeName 2}}
 	{{- range $.Contract.Structs}}
 	{{- if eq .Name $elemType}}
-	// Read offset pointer to array data
-	if len(data) < offset+32 {
-		return nil, errors.New("insufficient data for array offset pointer")
-	}
-	arrayOffset, err := decodeUint256(data[offset:offset+32])
+	{{- $decoded = true}}
+	// Handle struct array types, encoded behind an offset
+	arrayOffset, err := decodeOffset(data, 0, offset)
 	if err != nil {
-		return nil, fmt.Errorf("decoding array offset pointer: %w", err)
-	}
-	if !arrayOffset.IsUint64() {
-		return nil, errors.New("array offset too large")
-	}
-	arrayOffsetInt := int(arrayOffset.Uint64())
-	
-	// Read array length at the offset location
-	if len(data) < arrayOffsetInt+32 {
-		return nil, errors.New("insufficient data for array length")
+		return nil, fmt.Errorf("decoding array offset: %w", err)
 	}
-	val, err := decodeUint256(data[arrayOffsetInt:arrayOffsetInt+32])
+	length, err := decodeArrayLength(data, arrayOffset)
 	if err != nil {
-		return nil, fmt.Errorf("decoding array length: %w", err)
-	}
-	if !val.IsUint64() {
-		return nil, errors.New("array length too large")
+		return nil, err
 	}
-	length := int(val.Uint64())
-	offset = arrayOffsetInt + 32
-	
+
 	result := make({{$output.Type.TypeName}}, length)
-	for i := 0; i < length; i++ {
-		var elem {{.Name}}
-		var nextOffset int
-		elem, nextOffset, err = decode{{.Name}}(data, offset)
+	elemsOffset := arrayOffset + 32
+	elemOffset := elemsOffset
+	for i := range result {
+		{{- if .Dynamic}}
+		// Dynamic elements hold an offset relative to the first element
+		elemOffset, err = decodeOffset(data, elemsOffset, elemsOffset+i*32)
+		if err != nil {
+			return nil, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+		result[i], _, err = decode{{.Name}}(data, elemOffset)
+		{{- else}}
+		result[i], elemOffset, err = decode{{.Name}}(data, elemOffset)
+		{{- end}}
 		if err != nil {
 			return nil, fmt.Errorf("decoding array element %d: %w", i, err)
 		}
-		result[i] = elem
-		offset = nextOffset
 	}
 	return result, nil
 	{{- end}}
 	{{- end}}
 	{{- end}}
+	{{- if not $decoded}}
 	return {{formatGoType $output.Type}}{}, errors.New("unsupported return type: {{$output.Type.TypeName}}")
 	{{- end}}
+	{{- end}}
 {{- else}}
 	// Multiple return values - return as struct
 	var result {{.Name | title}}Result
@@ -266,7 +450,11 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 	{{- $needsValInt64 := false}}
 	{{- $needsValString := false}}
 	{{- $needsValBytes := false}}
+	{{- $needsValUint8 := false}}
 	{{- range .Outputs}}
+		{{- if or .Type.IsEnum (eq .Type.TypeName "uint8")}}
+			{{- $needsValUint8 = true}}
+		{{- end}}
 		{{- if eq .Type.TypeName "*big.Int"}}
 			{{- $needsVal = true}}
 		{{- end}}
@@ -310,6 +498,9 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 	{{- if $needsValBytes}}
 	var valBytes []byte
 	{{- end}}
+	{{- if $needsValUint8}}
+	var valUint8 uint8
+	{{- end}}
 	var err error
 	offset := 0
 	{{- range $i, $output := .Outputs}}
@@ -332,6 +523,16 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 	result.{{$output.Name | title}} = val
 	offset += 32
 	{{- end}}
+	{{- else if or $output.Type.IsEnum (eq $output.Type.TypeName "uint8")}}
+	if len(data) < offset+32 {
+		return result, errors.New("insufficient data for return value {{$i}}")
+	}
+	valUint8, err = decodeUint8(data[offset:offset+32])
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	result.{{$output.Name | title}} = {{$output.Type.TypeName}}(valUint8)
+	offset += 32
 	{{- else if eq $output.Type.TypeName "uint64"}}
 	if len(data) < offset+32 {
 		return result, errors.New("insufficient data for return value {{$i}}")
@@ -352,6 +553,15 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 	}
 	result.{{$output.Name | title}} = valInt64
 	offset += 32
+	{{- else if or (eq $output.Type.TypeName "uint16") (eq $output.Type.TypeName "uint32") (eq $output.Type.TypeName "int8") (eq $output.Type.TypeName "int16") (eq $output.Type.TypeName "int32")}}
+	if len(data) < offset+32 {
+		return result, errors.New("insufficient data for return value {{$i}}")
+	}
+	result.{{$output.Name | title}}, err = decode{{$output.Type.TypeName | title}}(data[offset:offset+32])
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	offset += 32
 	{{- else if eq $output.Type.TypeName "bool"}}
 	if len(data) < offset+32 {
 		return result, errors.New("insufficient data for return value {{$i}}")
@@ -372,127 +582,208 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 	}
 	result.{{$output.Name | title}} = valAddr
 	offset += 32
+	{{- else if eq $output.Type.TypeName "[32]byte"}}
+	if len(data) < offset+32 {
+		return result, errors.New("insufficient data for return value {{$i}}")
+	}
+	result.{{$output.Name | title}}, err = decodeBytes32(data[offset:offset+32])
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	offset += 32
+	{{- else if eq $output.Type.TypeName (printf "[%d]byte" (fixedArrayLen $output.Type))}}
+	// Handle {{$output.ABIType}}, left-aligned in its word
+	if len(data) < offset+32 {
+		return result, errors.New("insufficient data for return value {{$i}}")
+	}
+	fixed{{$i}}, err := decodeFixedBytes(data[offset:offset+32], {{fixedArrayLen $output.Type}})
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	copy(result.{{$output.Name | title}}[:], fixed{{$i}})
+	offset += 32
+	{{- else if and $.Options.TinyGo (arrayDecoder $output.Type)}}
+	// Handle {{$output.Type.TypeName}} array, encoded behind an offset
+	arrayOffset{{$i}}, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	result.{{$output.Name | title}}, _, err = {{arrayDecoder $output.Type}}(data, arrayOffset{{$i}})
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	offset += 32
 	{{- else if eq $output.Type.TypeName "[]*big.Int"}}
-	// Handle []*big.Int array
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, offset, decodeUint256ArrayElement)
+	// Handle []*big.Int array, encoded behind an offset
+	arrayOffset{{$i}}, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	elems{{$i}}, _, err := decodeArray(data, arrayOffset{{$i}}, {{if $output.Type.IsSigned}}decodeInt256ArrayElement{{else}}decodeUint256ArrayElement{{end}})
 	if err != nil {
 		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
 	}
-	bigIntArray := make([]*big.Int, len(elems))
-	for j, elem := range elems {
-		bigIntArray[j] = elem.(*big.Int)
+	array{{$i}} := make([]*big.Int, len(elems{{$i}}))
+	for j, elem := range elems{{$i}} {
+		array{{$i}}[j] = elem.(*big.Int)
 	}
-	result.{{$output.Name | title}} = bigIntArray
-	offset = nextOffset
+	result.{{$output.Name | title}} = array{{$i}}
+	offset += 32
 	{{- else if eq $output.Type.TypeName "[]uint64"}}
-	// Handle []uint64 array
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, offset, func(d []byte) (interface{}, error) { return decodeUint64(d) })
+	// Handle []uint64 array, encoded behind an offset
+	arrayOffset{{$i}}, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	elems{{$i}}, _, err := decodeArray(data, arrayOffset{{$i}}, func(d []byte) (interface{}, error) { return decodeUint64(d) })
 	if err != nil {
 		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
 	}
-	uint64Array := make([]uint64, len(elems))
-	for j, elem := range elems {
-		uint64Array[j] = elem.(uint64)
+	array{{$i}} := make([]uint64, len(elems{{$i}}))
+	for j, elem := range elems{{$i}} {
+		array{{$i}}[j] = elem.(uint64)
 	}
-	result.{{$output.Name | title}} = uint64Array
-	offset = nextOffset
+	result.{{$output.Name | title}} = array{{$i}}
+	offset += 32
 	{{- else if eq $output.Type.TypeName "[]Address"}}
-	// Handle []Address array
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, offset, decodeAddressArrayElement)
+	// Handle []Address array, encoded behind an offset
+	arrayOffset{{$i}}, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	elems{{$i}}, _, err := decodeArray(data, arrayOffset{{$i}}, decodeAddressArrayElement)
 	if err != nil {
 		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
 	}
-	addressArray := make([]Address, len(elems))
-	for j, elem := range elems {
-		addressArray[j] = elem.(Address)
+	array{{$i}} := make([]Address, len(elems{{$i}}))
+	for j, elem := range elems{{$i}} {
+		array{{$i}}[j] = elem.(Address)
 	}
-	result.{{$output.Name | title}} = addressArray
-	offset = nextOffset
+	result.{{$output.Name | title}} = array{{$i}}
+	offset += 32
 	{{- else if eq $output.Type.TypeName "[]bool"}}
-	// Handle []bool array
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, offset, decodeBoolArrayElement)
+	// Handle []bool array, encoded behind an offset
+	arrayOffset{{$i}}, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	elems{{$i}}, _, err := decodeArray(data, arrayOffset{{$i}}, decodeBoolArrayElement)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	array{{$i}} := make([]bool, len(elems{{$i}}))
+	for j, elem := range elems{{$i}} {
+		array{{$i}}[j] = elem.(bool)
+	}
+	result.{{$output.Name | title}} = array{{$i}}
+	offset += 32
+	{{- else if eq $output.Type.TypeName "[][32]byte"}}
+	// Handle [][32]byte array, encoded behind an offset
+	arrayOffset{{$i}}, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	elems{{$i}}, _, err := decodeArray(data, arrayOffset{{$i}}, decodeBytes32ArrayElement)
 	if err != nil {
 		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
 	}
-	boolArray := make([]bool, len(elems))
-	for j, elem := range elems {
-		boolArray[j] = elem.(bool)
+	array{{$i}} := make([][32]byte, len(elems{{$i}}))
+	for j, elem := range elems{{$i}} {
+		array{{$i}}[j] = elem.([32]byte)
 	}
-	result.{{$output.Name | title}} = boolArray
-	offset = nextOffset
+	result.{{$output.Name | title}} = array{{$i}}
+	offset += 32
 	{{- else if eq $output.Type.TypeName "string"}}
-	// Handle string
-	var nextOffset int
-	valString, nextOffset, err = decodeString(data, offset)
+	// Handle string, encoded behind an offset
+	dataOffset{{$i}}, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	valString, _, err = decodeString(data, dataOffset{{$i}})
 	if err != nil {
 		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
 	}
 	result.{{$output.Name | title}} = valString
-	offset = nextOffset
+	offset += 32
 	{{- else if eq $output.Type.TypeName "[]byte"}}
-	// Handle []byte
-	var nextOffset int
-	valBytes, nextOffset, err = decodeBytes(data, offset)
+	// Handle []byte, encoded behind an offset
+	dataOffset{{$i}}, err := decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	valBytes, _, err = decodeBytes(data, dataOffset{{$i}})
 	if err != nil {
 		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
 	}
 	result.{{$output.Name | title}} = valBytes
-	offset = nextOffset
+	offset += 32
 	{{- else}}
-	// Handle struct types in multi-return
+	{{- $decoded := false}}
 	{{- range $.Contract.Structs}}
 	{{- if eq .Name $output.Type.TypeName}}
-	var structVal {{.Name}}
-	var nextOffset int
-	structVal, nextOffset, err = decode{{.Name}}(data, offset)
+	{{- $decoded = true}}
+	// Handle struct type in multi-return
+	var structVal{{$i}} {{.Name}}
+	{{- if .Dynamic}}
+	var structOffset{{$i}} int
+	structOffset{{$i}}, err = decodeOffset(data, 0, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	structVal{{$i}}, _, err = decode{{.Name}}(data, structOffset{{$i}})
 	if err != nil {
 		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
 	}
-	result.{{$output.Name | title}} = structVal
-	offset = nextOffset
+	result.{{$output.Name | title}} = structVal{{$i}}
+	offset += 32
+	{{- else}}
+	structVal{{$i}}, offset, err = decode{{.Name}}(data, offset)
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
+	}
+	result.{{$output.Name | title}} = structVal{{$i}}
+	{{- end}}
 	{{- end}}
 	{{- end}}
-	// Handle struct array types in multi-return
 	{{- if and $output.Type.IsSlice (gt (len $output.Type.TypeName) 2)}}
 	{{- $elemType := slice $output.Type.TypeName 2}}
 	{{- range $.Contract.Structs}}
 	{{- if eq .Name $elemType}}
-	if len(data) < offset+32 {
-		return result, errors.New("insufficient data for array length in return value {{$i}}")
-	}
-	val, err := decodeUint256(data[offset:offset+32])
+	{{- $decoded = true}}
+	// Handle struct array type in multi-return, encoded behind an offset
+	arrayOffset{{$i}}, err := decodeOffset(data, 0, offset)
 	if err != nil {
-		return result, fmt.Errorf("decoding array length in return value {{$i}}: %w", err)
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
 	}
-	if !val.IsUint64() {
-		return result, errors.New("array length too large in return value {{$i}}")
+	length{{$i}}, err := decodeArrayLength(data, arrayOffset{{$i}})
+	if err != nil {
+		return result, fmt.Errorf("decoding return value {{$i}}: %w", err)
 	}
-	length := int(val.Uint64())
-	offset += 32
-	
-	structArray := make({{$output.Type.TypeName}}, length)
-	for j := 0; j < length; j++ {
-		var elem {{.Name}}
-		var nextOffset int
-		elem, nextOffset, err = decode{{.Name}}(data, offset)
+
+	structArray{{$i}} := make({{$output.Type.TypeName}}, length{{$i}})
+	elemsOffset{{$i}} := arrayOffset{{$i}} + 32
+	elemOffset{{$i}} := elemsOffset{{$i}}
+	for j := range structArray{{$i}} {
+		{{- if .Dynamic}}
+		// Dynamic elements hold an offset relative to the first element
+		elemOffset{{$i}}, err = decodeOffset(data, elemsOffset{{$i}}, elemsOffset{{$i}}+j*32)
+		if err != nil {
+			return result, fmt.Errorf("decoding array element %d in return value {{$i}}: %w", j, err)
+		}
+		structArray{{$i}}[j], _, err = decode{{.Name}}(data, elemOffset{{$i}})
+		{{- else}}
+		structArray{{$i}}[j], elemOffset{{$i}}, err = decode{{.Name}}(data, elemOffset{{$i}})
+		{{- end}}
 		if err != nil {
 			return result, fmt.Errorf("decoding array element %d in return value {{$i}}: %w", j, err)
 		}
-		structArray[j] = elem
-		offset = nextOffset
 	}
-	result.{{$output.Name | title}} = structArray
+	result.{{$output.Name | title}} = structArray{{$i}}
+	offset += 32
 	{{- end}}
 	{{- end}}
-	{{- else}}
+	{{- end}}
+	{{- if not $decoded}}
 	return result, errors.New("unsupported multi-return type: {{$output.Type.TypeName}}")
 	{{- end}}
 	{{- end}}
@@ -501,4 +792,158 @@ func (m *{{.Name | title}}Method) decodeImpl(data []byte) ({{if eq (len .Outputs
 {{- end}}
 }
 {{- end}}
-{{- end}}`
\ No newline at end of file
+{{- end}}`
+
+// methodTupleArgsTemplate generates typed Pack, MustPack and UnpackInput methods
+// for methods taking tuples, which the variadic PackableMethod.Pack cannot encode
+const methodTupleArgsTemplate = `{{- range .Contract.Methods}}
+{{- if hasTupleInput .}}
+{{- $method := .}}
+{{- $args := inputArgs .}}
+{{- $result := $args.Name}}
+{{- if not .InputStruct}}{{$result = formatGoType (index .Inputs 0).Type}}{{end}}
+
+// Pack encodes the {{.Name}} arguments, nested tuples included, after the method selector
+func (m *{{.Name | title}}Method) Pack({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{argName $input.Name $i}} {{formatGoType $input.Type}}{{end}}) (HexData, error) {
+	data, err := encode{{$args.Name}}({{$args.Name}}{
+{{- range $i, $field := $args.Fields}}{{if $i}}, {{end}}{{$field.Name}}: {{argName (index $method.Inputs $i).Name $i}}{{end -}} })
+	if err != nil {
+		return "", fmt.Errorf("packing {{.Name}}: %w", err)
+	}
+	return HexData("0x" + hex.EncodeToString(append(m.Selector.Bytes(), data...))), nil
+}
+
+// MustPack encodes the {{.Name}} arguments and panics on error
+func (m *{{.Name | title}}Method) MustPack({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{argName $input.Name $i}} {{formatGoType $input.Type}}{{end}}) HexData {
+	result, err := m.Pack({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{argName $input.Name $i}}{{end}})
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// PackWithSelector encodes the {{.Name}} arguments like Pack, but after the given selector
+func (m *{{.Name | title}}Method) PackWithSelector(sel [4]byte{{range $i, $input := .Inputs}}, {{argName $input.Name $i}} {{formatGoType $input.Type}}{{end}}) (HexData, error) {
+	packed, err := m.Pack({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{argName $input.Name $i}}{{end}})
+	if err != nil {
+		return "", err
+	}
+	return withSelector(packed, sel), nil
+}
+
+// PackArgs encodes the {{.Name}} arguments like Pack, without the method selector
+func (m *{{.Name | title}}Method) PackArgs({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{argName $input.Name $i}} {{formatGoType $input.Type}}{{end}}) (HexData, error) {
+	packed, err := m.Pack({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{argName $input.Name $i}}{{end}})
+	if err != nil {
+		return "", err
+	}
+	return withoutSelector(packed), nil
+}
+
+// PackPadded encodes the {{.Name}} arguments like Pack and right-pads the calldata
+// with zero bytes to a multiple of alignment bytes
+func (m *{{.Name | title}}Method) PackPadded(alignment int{{range $i, $input := .Inputs}}, {{argName $input.Name $i}} {{formatGoType $input.Type}}{{end}}) (HexData, error) {
+	packed, err := m.Pack({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{argName $input.Name $i}}{{end}})
+	if err != nil {
+		return "", err
+	}
+	return padCalldata(packed, alignment)
+}
+
+// UnpackInput decodes {{.Name}} calldata, selector included, back into its arguments
+func (m *{{.Name | title}}Method) UnpackInput(data []byte) ({{$result}}, error) {
+	var args {{$args.Name}}
+	if len(data) < 4 {
+		return args{{if not .InputStruct}}.{{(index $args.Fields 0).Name}}{{end}}, errors.New("insufficient data for method selector")
+	}
+	if selector := HexData("0x" + hex.EncodeToString(data[:4])); selector != m.Selector {
+		return args{{if not .InputStruct}}.{{(index $args.Fields 0).Name}}{{end}}, fmt.Errorf("selector %s does not match {{.Name}}", selector)
+	}
+	args, _, err := decode{{$args.Name}}(data[4:], 0)
+	if err != nil {
+		return args{{if not .InputStruct}}.{{(index $args.Fields 0).Name}}{{end}}, fmt.Errorf("unpacking {{.Name}} input: %w", err)
+	}
+	return args{{if not .InputStruct}}.{{(index $args.Fields 0).Name}}{{end}}, nil
+}
+{{- end}}
+{{- end}}`
+
+// eip712DomainTemplate generates the EIP712Domain type decoded from the EIP-5267
+// eip712Domain() method, and its separator when a Keccak-256 is available
+const eip712DomainTemplate = `{{- $outputs := .Outputs}}
+// EIP712Domain is the EIP-712 signing domain the contract reports through
+// eip712Domain (EIP-5267). Fields flags the fields the domain uses, bit 0 for
+// Name up to bit 4 for Salt.
+type EIP712Domain struct {
+	Fields            byte
+	Name              string
+	Version           string
+	ChainId           *big.Int
+	VerifyingContract Address
+	Salt              [32]byte
+	Extensions        []*big.Int
+}
+
+// DecodeDomain decodes the return data of {{.Name}} into the EIP-712 domain it describes
+func (m *{{.Name | title}}Method) DecodeDomain(data []byte) (EIP712Domain, error) {
+	result, err := m.Decode(data)
+	if err != nil {
+		return EIP712Domain{}, err
+	}
+	return EIP712Domain{
+		Fields:            result.{{(index $outputs 0).Name | title}}[0],
+		Name:              result.{{(index $outputs 1).Name | title}},
+		Version:           result.{{(index $outputs 2).Name | title}},
+		ChainId:           result.{{(index $outputs 3).Name | title}},
+		VerifyingContract: result.{{(index $outputs 4).Name | title}},
+		Salt:              result.{{(index $outputs 5).Name | title}},
+		Extensions:        result.{{(index $outputs 6).Name | title}},
+	}, nil
+}
+
+{{- if or $.Options.WithKeccak $.Options.WithBind}}
+
+// Separator returns the EIP-712 domain separator, the hash struct of the domain
+// over the fields it uses
+func (d EIP712Domain) Separator() (Hash, error) {
+	var fields []string
+	var values [][]byte
+	if d.Fields&0x01 != 0 {
+		name := eip712Keccak([]byte(d.Name))
+		fields, values = append(fields, "string name"), append(values, name[:])
+	}
+	if d.Fields&0x02 != 0 {
+		version := eip712Keccak([]byte(d.Version))
+		fields, values = append(fields, "string version"), append(values, version[:])
+	}
+	if d.Fields&0x04 != 0 {
+		chainId, err := encodeUint256(d.ChainId)
+		if err != nil {
+			return Hash{}, fmt.Errorf("encoding chainId: %w", err)
+		}
+		fields, values = append(fields, "uint256 chainId"), append(values, chainId)
+	}
+	if d.Fields&0x08 != 0 {
+		verifyingContract, err := encodeAddress(d.VerifyingContract)
+		if err != nil {
+			return Hash{}, fmt.Errorf("encoding verifyingContract: %w", err)
+		}
+		fields, values = append(fields, "address verifyingContract"), append(values, verifyingContract)
+	}
+	if d.Fields&0x10 != 0 {
+		fields, values = append(fields, "bytes32 salt"), append(values, d.Salt[:])
+	}
+
+	typeHash := eip712Keccak([]byte("EIP712Domain(" + strings.Join(fields, ",") + ")"))
+	return eip712Keccak(append([][]byte{typeHash[:]}, values...)...), nil
+}
+
+// eip712Keccak returns the Keccak-256 hash of the concatenated data
+func eip712Keccak(data ...[]byte) Hash {
+	{{- if $.Options.WithKeccak}}
+	return Keccak256(data...)
+	{{- else}}
+	return Hash(crypto.Keccak256Hash(data...))
+	{{- end}}
+}
+{{- end}}`
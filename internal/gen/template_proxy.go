@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: MIT
+
+package gen
+
+// proxyRuntimeTemplate contains the ERC-1967 proxy helpers, generated with --with-proxy-helpers
+const proxyRuntimeTemplate = `// erc1967ImplementationSlot is the ERC-1967 slot holding the implementation of a proxy,
+// keccak256("eip1967.proxy.implementation") - 1
+var erc1967ImplementationSlot = HashFromHex("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// ImplementationSlot returns the ERC-1967 storage slot holding the implementation
+// address of a proxy, to read with eth_getStorageAt
+func ImplementationSlot() Hash {
+	return erc1967ImplementationSlot
+}
+
+// AddressFromStorage decodes an address from a 32-byte storage value, such as
+// the one read from ImplementationSlot. The address is right-aligned and the
+// 12 bytes before it must be zero.
+func AddressFromStorage(value []byte) (Address, error) {
+	if len(value) != 32 {
+		return Address{}, fmt.Errorf("storage value has %d bytes, expected 32", len(value))
+	}
+	for _, b := range value[:12] {
+		if b != 0 {
+			return Address{}, errors.New("storage value does not hold an address")
+		}
+	}
+	return decodeAddress(value)
+}`
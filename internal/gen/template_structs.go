@@ -4,10 +4,12 @@ package gen
 
 // structDecodersTemplate generates struct decoder functions
 const structDecodersTemplate = `{{/* Generate struct decoders for all structs */}}
-{{- range .Contract.Structs}}
-// decode{{.Name}} decodes a {{.Name}} struct from ABI-encoded data
-func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
-	var result {{.Name}}
+{{- define "structDecoder"}}
+{{- $s := .Struct}}
+{{- $structs := .Structs}}
+// decode{{$s.Name}} decodes a {{$s.Name}} struct from ABI-encoded data
+func decode{{$s.Name}}(data []byte, offset int) ({{$s.Name}}, int, error) {
+	var result {{$s.Name}}
 	{{- $needsVal := false}}
 	{{- $needsValAddr := false}}
 	{{- $needsValHash := false}}
@@ -19,10 +21,25 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	{{- $needsValUint16 := false}}
 	{{- $needsValUint8 := false}}
 	{{- $needsValInt64 := false}}
+	{{- $needsValInt32 := false}}
+	{{- $needsValInt16 := false}}
+	{{- $needsValInt8 := false}}
 	{{- $needsValBytes1 := false}}
 	{{- $needsValBytes32 := false}}
-	{{- range .Fields}}
-		{{- if or (eq .Type.TypeName "*big.Int") (and .Type.IsSlice (or (eq .Type.TypeName "[]*big.Int") (hasPrefix .Type.TypeName "[]")))}}
+	{{- $needsFieldOffset := false}}
+	{{- $needsElems := false}}
+	{{- range $s.Fields}}
+		{{- if .Dynamic}}
+			{{- $needsFieldOffset = true}}
+		{{- end}}
+		{{- if and $.TinyGo .Type.IsSlice (arrayDecoder .Type)}}
+			{{- /* typed array decoders need no scratch values */}}
+		{{- else if and .Type.IsSlice (or (eq .Type.TypeName "[]*big.Int") (eq .Type.TypeName "[]uint64") (eq .Type.TypeName "[]Address") (eq .Type.TypeName "[][32]byte"))}}
+			{{- $needsElems = true}}
+		{{- else if .Type.IsSlice}}
+			{{- $needsVal = true}}
+		{{- end}}
+		{{- if eq .Type.TypeName "*big.Int"}}
 			{{- $needsVal = true}}
 		{{- end}}
 		{{- if eq .Type.TypeName "Address"}}
@@ -49,12 +66,21 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 		{{- if eq .Type.TypeName "uint16"}}
 			{{- $needsValUint16 = true}}
 		{{- end}}
-		{{- if eq .Type.TypeName "uint8"}}
+		{{- if or (eq .Type.TypeName "uint8") .Type.IsEnum}}
 			{{- $needsValUint8 = true}}
 		{{- end}}
-		{{- if or (eq .Type.TypeName "int64") (eq .Type.TypeName "int8") (eq .Type.TypeName "int16") (eq .Type.TypeName "int32")}}
+		{{- if eq .Type.TypeName "int64"}}
 			{{- $needsValInt64 = true}}
 		{{- end}}
+		{{- if eq .Type.TypeName "int32"}}
+			{{- $needsValInt32 = true}}
+		{{- end}}
+		{{- if eq .Type.TypeName "int16"}}
+			{{- $needsValInt16 = true}}
+		{{- end}}
+		{{- if eq .Type.TypeName "int8"}}
+			{{- $needsValInt8 = true}}
+		{{- end}}
 		{{- if eq .Type.TypeName "[1]byte"}}
 			{{- $needsValBytes1 = true}}
 		{{- end}}
@@ -95,16 +121,32 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	{{- if $needsValInt64}}
 	var valInt64 int64
 	{{- end}}
+	{{- if $needsValInt32}}
+	var valInt32 int32
+	{{- end}}
+	{{- if $needsValInt16}}
+	var valInt16 int16
+	{{- end}}
+	{{- if $needsValInt8}}
+	var valInt8 int8
+	{{- end}}
 	{{- if $needsValBytes1}}
 	var valBytes1 [1]byte
 	{{- end}}
 	{{- if $needsValBytes32}}
 	var valBytes32 [32]byte
 	{{- end}}
+	{{- if $needsFieldOffset}}
+	var fieldOffset int
+	{{- end}}
+	{{- if $needsElems}}
+	var elems []interface{}
+	{{- end}}
 	var err error
+	// Static fields are read in place, dynamic fields hold an offset relative to the struct start
 	currentOffset := offset
-	{{- $structName := .Name}}
-	{{- range .Fields}}
+	{{- $structName := $s.Name}}
+	{{- range $s.Fields}}
 	{{- if eq .Type.TypeName "*big.Int"}}
 	if len(data) < currentOffset+32 {
 		return result, 0, errors.New("insufficient data for {{$structName}}.{{.Name}}")
@@ -114,13 +156,13 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = val
+	result.{{.Name}} = {{convertType .Type "val"}}
 	{{- else}}
 	val, err = decodeUint256(data[currentOffset:currentOffset+32])
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = val
+	result.{{.Name}} = {{convertType .Type "val"}}
 	{{- end}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "uint64"}}
@@ -131,7 +173,7 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valUint64
+	result.{{.Name}} = {{convertType .Type "valUint64"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "uint8"}}
 	if len(data) < currentOffset+32 {
@@ -141,7 +183,17 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valUint8
+	result.{{.Name}} = {{convertType .Type "valUint8"}}
+	currentOffset += 32
+	{{- else if .Type.IsEnum}}
+	if len(data) < currentOffset+32 {
+		return result, 0, errors.New("insufficient data for {{$structName}}.{{.Name}}")
+	}
+	valUint8, err = decodeUint8(data[currentOffset:currentOffset+32])
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	result.{{.Name}} = {{.Type.TypeName}}(valUint8)
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "uint16"}}
 	if len(data) < currentOffset+32 {
@@ -151,7 +203,7 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valUint16
+	result.{{.Name}} = {{convertType .Type "valUint16"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "uint32"}}
 	if len(data) < currentOffset+32 {
@@ -161,7 +213,7 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valUint32
+	result.{{.Name}} = {{convertType .Type "valUint32"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "int64"}}
 	if len(data) < currentOffset+32 {
@@ -171,37 +223,37 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valInt64
+	result.{{.Name}} = {{convertType .Type "valInt64"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "int8"}}
 	if len(data) < currentOffset+32 {
 		return result, 0, errors.New("insufficient data for {{$structName}}.{{.Name}}")
 	}
-	valInt64, err = decodeInt64(data[currentOffset:currentOffset+32])
+	valInt8, err = decodeInt8(data[currentOffset:currentOffset+32])
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = int8(valInt64)
+	result.{{.Name}} = {{convertType .Type "valInt8"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "int16"}}
 	if len(data) < currentOffset+32 {
 		return result, 0, errors.New("insufficient data for {{$structName}}.{{.Name}}")
 	}
-	valInt64, err = decodeInt64(data[currentOffset:currentOffset+32])
+	valInt16, err = decodeInt16(data[currentOffset:currentOffset+32])
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = int16(valInt64)
+	result.{{.Name}} = {{convertType .Type "valInt16"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "int32"}}
 	if len(data) < currentOffset+32 {
 		return result, 0, errors.New("insufficient data for {{$structName}}.{{.Name}}")
 	}
-	valInt64, err = decodeInt64(data[currentOffset:currentOffset+32])
+	valInt32, err = decodeInt32(data[currentOffset:currentOffset+32])
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = int32(valInt64)
+	result.{{.Name}} = {{convertType .Type "valInt32"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "bool"}}
 	if len(data) < currentOffset+32 {
@@ -211,7 +263,7 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valBool
+	result.{{.Name}} = {{convertType .Type "valBool"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "Address"}}
 	if len(data) < currentOffset+32 {
@@ -221,7 +273,7 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valAddr
+	result.{{.Name}} = {{convertType .Type "valAddr"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "Hash"}}
 	if len(data) < currentOffset+32 {
@@ -231,24 +283,30 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valHash
+	result.{{.Name}} = {{convertType .Type "valHash"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "string"}}
-	var nextOffset int
-	valStr, nextOffset, err = decodeString(data, currentOffset)
+	fieldOffset, err = decodeOffset(data, offset, currentOffset)
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valStr
-	currentOffset = nextOffset
+	valStr, _, err = decodeString(data, fieldOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	result.{{.Name}} = {{convertType .Type "valStr"}}
+	currentOffset += 32
 	{{- else if eq .Type.TypeName "[]byte"}}
-	var nextOffset int
-	valBytes, nextOffset, err = decodeBytes(data, currentOffset)
+	fieldOffset, err = decodeOffset(data, offset, currentOffset)
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valBytes
-	currentOffset = nextOffset
+	valBytes, _, err = decodeBytes(data, fieldOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	result.{{.Name}} = {{convertType .Type "valBytes"}}
+	currentOffset += 32
 	{{- else if eq .Type.TypeName "[1]byte"}}
 	if len(data) < currentOffset+32 {
 		return result, 0, errors.New("insufficient data for {{$structName}}.{{.Name}}")
@@ -257,7 +315,7 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valBytes1
+	result.{{.Name}} = {{convertType .Type "valBytes1"}}
 	currentOffset += 32
 	{{- else if eq .Type.TypeName "[32]byte"}}
 	if len(data) < currentOffset+32 {
@@ -267,12 +325,24 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
-	result.{{.Name}} = valBytes32
+	result.{{.Name}} = {{convertType .Type "valBytes32"}}
+	currentOffset += 32
+	{{- else if and $.TinyGo .Type.IsSlice (arrayDecoder .Type)}}
+	fieldOffset, err = decodeOffset(data, offset, currentOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	result.{{.Name}}, _, err = {{arrayDecoder .Type}}(data, fieldOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
 	currentOffset += 32
 	{{- else if and .Type.IsSlice (eq .Type.TypeName "[]*big.Int")}}
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, currentOffset, decodeUint256ArrayElement)
+	fieldOffset, err = decodeOffset(data, offset, currentOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	elems, _, err = decodeArray(data, fieldOffset, {{if .Type.IsSigned}}decodeInt256ArrayElement{{else}}decodeUint256ArrayElement{{end}})
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
@@ -280,11 +350,13 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	for i, elem := range elems {
 		result.{{.Name}}[i] = elem.(*big.Int)
 	}
-	currentOffset = nextOffset
+	currentOffset += 32
 	{{- else if and .Type.IsSlice (eq .Type.TypeName "[]uint64")}}
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, currentOffset, func(d []byte) (interface{}, error) { return decodeUint64(d) })
+	fieldOffset, err = decodeOffset(data, offset, currentOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	elems, _, err = decodeArray(data, fieldOffset, func(d []byte) (interface{}, error) { return decodeUint64(d) })
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
@@ -292,11 +364,13 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	for i, elem := range elems {
 		result.{{.Name}}[i] = elem.(uint64)
 	}
-	currentOffset = nextOffset
+	currentOffset += 32
 	{{- else if and .Type.IsSlice (eq .Type.TypeName "[]Address")}}
-	var elems []interface{}
-	var nextOffset int
-	elems, nextOffset, err = decodeArray(data, currentOffset, decodeAddressArrayElement)
+	fieldOffset, err = decodeOffset(data, offset, currentOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	elems, _, err = decodeArray(data, fieldOffset, decodeAddressArrayElement)
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
 	}
@@ -304,51 +378,148 @@ func decode{{.Name}}(data []byte, offset int) ({{.Name}}, int, error) {
 	for i, elem := range elems {
 		result.{{.Name}}[i] = elem.(Address)
 	}
-	currentOffset = nextOffset
+	currentOffset += 32
+	{{- else if and .Type.IsSlice (eq .Type.TypeName "[][32]byte")}}
+	fieldOffset, err = decodeOffset(data, offset, currentOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	elems, _, err = decodeArray(data, fieldOffset, decodeBytes32ArrayElement)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	result.{{.Name}} = make([][32]byte, len(elems))
+	for i, elem := range elems {
+		result.{{.Name}}[i] = elem.([32]byte)
+	}
+	currentOffset += 32
+	{{- else if .Type.IsStruct}}
+	{{- if .Dynamic}}
+	fieldOffset, err = decodeOffset(data, offset, currentOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	result.{{.Name}}, _, err = decode{{.Type.TypeName}}(data, fieldOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	currentOffset += 32
+	{{- else}}
+	result.{{.Name}}, currentOffset, err = decode{{.Type.TypeName}}(data, currentOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	{{- end}}
 	{{- else if .Type.IsSlice}}
 	// Handle struct array field: {{.Type.TypeName}}
-	if len(data) < currentOffset+32 {
+	fieldOffset, err = decodeOffset(data, offset, currentOffset)
+	if err != nil {
+		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}}: %w", err)
+	}
+	if len(data) < fieldOffset+32 {
 		return result, 0, errors.New("insufficient data for struct array length in {{$structName}}.{{.Name}}")
 	}
-	val, err = decodeUint256(data[currentOffset:currentOffset+32])
+	val, err = decodeUint256(data[fieldOffset:fieldOffset+32])
 	if err != nil {
 		return result, 0, fmt.Errorf("decoding {{$structName}}.{{.Name}} length: %w", err)
 	}
 	if !val.IsUint64() {
 		return result, 0, errors.New("struct array length too large in {{$structName}}.{{.Name}}")
 	}
-	length := int(val.Uint64())
-	currentOffset += 32
-	
-	elemTypeName := "{{.Type.TypeName}}"[2:] // Remove "[]" prefix
-	{{- $outerContract := $.Contract}}
 	{{- $fieldName := .Name}}
-	{{- $fieldType := .Type.TypeName}}
-	{{- range $struct := $outerContract.Structs}}
-	if elemTypeName == "{{$struct.Name}}" {
-		result.{{$fieldName}} = make({{$fieldType}}, length)
-		for i := 0; i < length; i++ {
-			var elem {{$struct.Name}}
-			var nextOffsetStruct int
-			elem, nextOffsetStruct, err = decode{{$struct.Name}}(data, currentOffset)
-			if err != nil {
-				return result, 0, fmt.Errorf("decoding {{$structName}}.{{$fieldName}}[%d]: %w", i, err)
-			}
-			result.{{$fieldName}}[i] = elem
-			currentOffset = nextOffsetStruct
+	{{- $elemType := slice .Type.TypeName 2}}
+	{{- range $struct := $structs}}
+	{{- if eq $struct.Name $elemType}}
+	result.{{$fieldName}} = make([]{{$struct.Name}}, int(val.Uint64()))
+	elemsOffset := fieldOffset + 32
+	elemOffset := elemsOffset
+	for i := range result.{{$fieldName}} {
+		{{- if $struct.Dynamic}}
+		elemOffset, err = decodeOffset(data, elemsOffset, elemsOffset+i*32)
+		if err != nil {
+			return result, 0, fmt.Errorf("decoding {{$structName}}.{{$fieldName}}[%d]: %w", i, err)
+		}
+		result.{{$fieldName}}[i], _, err = decode{{$struct.Name}}(data, elemOffset)
+		{{- else}}
+		result.{{$fieldName}}[i], elemOffset, err = decode{{$struct.Name}}(data, elemOffset)
+		{{- end}}
+		if err != nil {
+			return result, 0, fmt.Errorf("decoding {{$structName}}.{{$fieldName}}[%d]: %w", i, err)
 		}
 	}
 	{{- end}}
+	{{- end}}
+	currentOffset += 32
 	{{- else}}
 	return result, 0, errors.New("unsupported struct field type {{.Type.TypeName}} in {{$structName}}.{{.Name}}")
 	{{- end}}
 	{{- end}}
 	return result, currentOffset, nil
 }
+{{- end}}
+{{- range .Contract.Structs}}
+{{template "structDecoder" structScope . $.Contract.Structs $.Options.TinyGo}}
+{{- end}}
+{{- range .Contract.Methods}}
+{{- if hasTupleInput .}}
+{{template "structDecoder" structScope (inputArgs .) $.Contract.Structs $.Options.TinyGo}}
+{{- end}}
+{{- end}}`
+
+// structEncodersTemplate generates struct encoder functions, the counterparts
+// of the struct decoders used to pack tuple arguments
+const structEncodersTemplate = `{{/* Generate struct encoders for all structs */}}
+{{- define "structEncoder"}}
+{{- $s := .Struct}}
+{{- $structs := .Structs}}
+// encode{{$s.Name}} encodes a {{$s.Name}} struct as an ABI tuple
+func encode{{$s.Name}}(s {{$s.Name}}) ([]byte, error) {
+	values := make([][]byte, {{len $s.Fields}})
+	var err error
+	{{- range $i, $field := $s.Fields}}
+	if values[{{$i}}], err = {{encodeExpr $field.Type (printf "s.%s" $field.Name) $structs}}; err != nil {
+		return nil, fmt.Errorf("encoding {{$s.Name}}.{{$field.Name}}: %w", err)
+	}
+	{{- end}}
+	return encodeTuple(values, []bool{ {{- range $i, $field := $s.Fields}}{{if $i}}, {{end}}{{$field.Dynamic}}{{end -}} }), nil
+}
+
+// EncodeTuple encodes the {{$s.Name}} as an ABI tuple, so Pack accepts it
+func (s {{$s.Name}}) EncodeTuple() ([]byte, error) {
+	return encode{{$s.Name}}(s)
+}
+
+// IsDynamicTuple reports whether a {{$s.Name}} is encoded behind an offset
+func (s {{$s.Name}}) IsDynamicTuple() bool {
+	return {{$s.Dynamic}}
+}
+{{- end}}
+{{- range .Contract.Structs}}
+{{template "structEncoder" structScope . $.Contract.Structs $.Options.TinyGo}}
+{{- end}}
+{{- range .Contract.Methods}}
+{{- if hasTupleInput .}}
+{{template "structEncoder" structScope (inputArgs .) $.Contract.Structs $.Options.TinyGo}}
+{{- end}}
 {{- end}}`
 
 // structDefinitionsTemplate generates struct type definitions
-const structDefinitionsTemplate = `{{/* Generate event structs */}}
+const structDefinitionsTemplate = `{{/* Generate enum types */}}
+{{- range .Contract.Enums}}
+
+// {{.Name}} represents the {{.Name}} Solidity enum
+type {{.Name}} uint8
+{{- if .Members}}
+
+// IsValid reports whether the value is one of the {{.Members}} members of {{.Name}},
+// other decoded values come from corrupt data or a newer version of the enum
+func (e {{.Name}}) IsValid() bool {
+	return e < {{.Members}}
+}
+{{- end}}
+{{- end}}
+
+{{/* Generate event structs */}}
 {{- range .Contract.Events}}
 
 // {{.Struct.Name}} represents the {{.Name}} event
@@ -404,6 +575,20 @@ type {{.OutputStruct.Name}} struct {
 {{- end}}
 {{- end}}
 
+{{/* Generate input structs for single tuple inputs, to pack and unpack them */}}
+{{- range .Contract.Methods}}
+{{- if and (hasTupleInput .) (not .InputStruct)}}
+{{- $args := inputArgs .}}
+
+// {{$args.Name}} represents inputs for method {{.Name}}
+type {{$args.Name}} struct {
+{{- range $args.Fields}}
+	{{.Name}} {{formatGoType .Type}} ` + "`" + `json:"{{.Name | lower}}"` + "`" + `
+{{- end}}
+}
+{{- end}}
+{{- end}}
+
 {{/* Generate constructor struct if needed */}}
 {{- if and .Contract.Constructor .Contract.Constructor.InputStruct}}
 
@@ -426,4 +611,36 @@ type {{.Name | title}}Result struct {
 {{- end}}
 }
 {{- end}}
-{{- end}}`
\ No newline at end of file
+{{- end}}`
+
+// structRegistryTemplate generates StructNames and a StructCodec per struct
+const structRegistryTemplate = `// StructNames returns the names of the contract structs, sorted
+func StructNames() []string {
+	return []string{ {{- range $i, $s := .Contract.Structs}}{{if $i}}, {{end}}{{$s.Name | quote}}{{end -}} }
+}
+
+// structCodecs indexes the decoder and encoder of every struct by name
+var structCodecs = map[string]StructCodec{
+{{- range .Contract.Structs}}
+	{{.Name | quote}}: {
+		Name: {{.Name | quote}},
+		Decode: func(data []byte) (any, error) {
+			value, _, err := decode{{.Name}}(data, 0)
+			return value, err
+		},
+		Encode: func(value any) ([]byte, error) {
+			s, ok := value.({{.Name}})
+			if !ok {
+				return nil, fmt.Errorf("expected a {{.Name}}, got %T", value)
+			}
+			return encode{{.Name}}(s)
+		},
+	},
+{{- end}}
+}
+
+// StructCodecByName returns the decoder and encoder of the named struct
+func StructCodecByName(name string) (StructCodec, bool) {
+	codec, ok := structCodecs[name]
+	return codec, ok
+}`
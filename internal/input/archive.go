@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: MIT
+
+package input
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/otherview/solgen/internal/types"
+)
+
+// archiveArtifact holds the parts of a Foundry or Hardhat artifact solgen reads
+type archiveArtifact struct {
+	ContractName           string                                `json:"contractName"` // Hardhat
+	SourceName             string                                `json:"sourceName"`   // Hardhat
+	ABI                    json.RawMessage                       `json:"abi"`
+	Bytecode               json.RawMessage                       `json:"bytecode"` // hex string (Hardhat) or bytecode object (Foundry)
+	DeployedBytecode       json.RawMessage                       `json:"deployedBytecode"`
+	LinkReferences         map[string]map[string][]types.LinkRef `json:"linkReferences"`         // Hardhat
+	DeployedLinkReferences map[string]map[string][]types.LinkRef `json:"deployedLinkReferences"` // Hardhat
+	MethodIdentifiers      map[string]string                     `json:"methodIdentifiers"`      // Foundry
+	DevDoc                 json.RawMessage                       `json:"devdoc"`                 // Foundry, when selected as extra output
+	Metadata               json.RawMessage                       `json:"metadata"`               // Foundry, solc metadata object
+}
+
+// foundryMetadata holds the parts of the solc metadata Foundry inlines in its artifacts
+type foundryMetadata struct {
+	Compiler struct {
+		Version string `json:"version"`
+	} `json:"compiler"`
+	Settings struct {
+		CompilationTarget map[string]string `json:"compilationTarget"`
+	} `json:"settings"`
+}
+
+// Archive reads a zip of Foundry (out/<File>.sol/<Name>.json) or Hardhat
+// (artifacts/<path>/<Name>.json) artifacts without extracting it. Entries that
+// are not JSON objects with an "abi" array, such as build info or Hardhat debug
+// files, are skipped. Method identifiers are derived from the ABI when the
+// artifact does not carry them, and the compiler version is read from the
+// Foundry metadata, or "unknown" when no artifact records it.
+func Archive(data []byte) (*types.CompileResult, string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, "", fmt.Errorf("reading archive: %w", err)
+	}
+
+	files := make([]*zip.File, 0, len(reader.File))
+	for _, file := range reader.File {
+		if !file.FileInfo().IsDir() && strings.HasSuffix(file.Name, ".json") && !strings.HasSuffix(file.Name, ".dbg.json") {
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	result := &types.CompileResult{
+		Contracts: make(map[string]map[string]types.ContractResult),
+	}
+	solcVersion := "unknown"
+	for _, file := range files {
+		content, err := readArchiveFile(file)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var artifact archiveArtifact
+		if err := json.Unmarshal(content, &artifact); err != nil || !isJSONArray(artifact.ABI) {
+			continue
+		}
+
+		var metadata foundryMetadata
+		if bytes.HasPrefix(bytes.TrimSpace(artifact.Metadata), []byte("{")) {
+			if err := json.Unmarshal(artifact.Metadata, &metadata); err != nil {
+				return nil, "", fmt.Errorf("parsing metadata of %s: %w", file.Name, err)
+			}
+		}
+
+		sourceFile, contractName := artifactName(file.Name, &artifact, &metadata)
+		contract, err := artifactResult(&artifact)
+		if err != nil {
+			return nil, "", fmt.Errorf("reading artifact %s: %w", file.Name, err)
+		}
+
+		if result.Contracts[sourceFile] == nil {
+			result.Contracts[sourceFile] = make(map[string]types.ContractResult)
+		}
+		if _, ok := result.Contracts[sourceFile][contractName]; ok {
+			return nil, "", fmt.Errorf("duplicate artifact for %s:%s in %s", sourceFile, contractName, file.Name)
+		}
+		result.Contracts[sourceFile][contractName] = contract
+
+		if solcVersion == "unknown" && metadata.Compiler.Version != "" {
+			solcVersion = metadata.Compiler.Version
+		}
+	}
+
+	if len(result.Contracts) == 0 {
+		return nil, "", fmt.Errorf("no artifacts found in archive")
+	}
+
+	return result, solcVersion, nil
+}
+
+// readArchiveFile reads the content of an archive entry
+func readArchiveFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file.Name, err)
+	}
+	return content, nil
+}
+
+// artifactName returns the source file and contract name of an artifact.
+// Hardhat records both, Foundry records the compilation target in its metadata
+// and otherwise names the artifact after the contract inside a <File>.sol directory.
+func artifactName(entryName string, artifact *archiveArtifact, metadata *foundryMetadata) (string, string) {
+	if artifact.ContractName != "" && artifact.SourceName != "" {
+		return artifact.SourceName, artifact.ContractName
+	}
+
+	contractName := strings.TrimSuffix(path.Base(entryName), ".json")
+	for sourceFile, name := range metadata.Settings.CompilationTarget {
+		if name == contractName {
+			return sourceFile, contractName
+		}
+	}
+	return path.Base(path.Dir(entryName)), contractName
+}
+
+// artifactResult converts an artifact into the compile result of its contract
+func artifactResult(artifact *archiveArtifact) (types.ContractResult, error) {
+	bytecode, err := artifactBytecode(artifact.Bytecode, artifact.LinkReferences)
+	if err != nil {
+		return types.ContractResult{}, fmt.Errorf("parsing bytecode: %w", err)
+	}
+	deployedBytecode, err := artifactBytecode(artifact.DeployedBytecode, artifact.DeployedLinkReferences)
+	if err != nil {
+		return types.ContractResult{}, fmt.Errorf("parsing deployed bytecode: %w", err)
+	}
+
+	methodIds := artifact.MethodIdentifiers
+	if methodIds == nil {
+		methodIds, err = methodIdentifiers(artifact.ABI)
+		if err != nil {
+			return types.ContractResult{}, err
+		}
+	}
+
+	return types.ContractResult{
+		ABI: artifact.ABI,
+		EVM: types.EVMResult{
+			Bytecode:          bytecode,
+			DeployedBytecode:  deployedBytecode,
+			MethodIdentifiers: methodIds,
+		},
+		DevDoc: artifact.DevDoc,
+	}, nil
+}
+
+// artifactBytecode reads a Hardhat hex string, with its separate link references,
+// or a Foundry bytecode object. Link references are derived from the library
+// placeholders when the artifact does not list them.
+func artifactBytecode(raw json.RawMessage, linkRefs map[string]map[string][]types.LinkRef) (types.BytecodeResult, error) {
+	var bytecode types.BytecodeResult
+	if len(raw) == 0 || string(raw) == "null" {
+		return bytecode, nil
+	}
+
+	if raw[0] == '"' {
+		if err := json.Unmarshal(raw, &bytecode.Object); err != nil {
+			return bytecode, err
+		}
+		bytecode.LinkReferences = linkRefs
+	} else if err := json.Unmarshal(raw, &bytecode); err != nil {
+		return bytecode, err
+	}
+
+	if len(bytecode.LinkReferences) == 0 {
+		bytecode.LinkReferences = LinkReferences(bytecode.Object)
+	}
+	return bytecode, nil
+}
+
+// isJSONArray reports whether raw holds a JSON array
+func isJSONArray(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) > 0 && raw[0] == '['
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: MIT
+
+package input
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/otherview/solgen/internal/types"
+)
+
+// EtherscanSourceFile is the source file reported for contracts read from an Etherscan response
+const EtherscanSourceFile = "etherscan"
+
+// EtherscanResponse is the envelope returned by Etherscan's getabi endpoint
+type EtherscanResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Result  string `json:"result"`
+}
+
+// Etherscan converts an Etherscan getabi response into a compile result holding
+// a single ABI-only contract. Method identifiers are derived from the ABI since
+// no compiler output is available.
+func Etherscan(data []byte, contractName string) (*types.CompileResult, error) {
+	if contractName == "" {
+		return nil, fmt.Errorf("contract name is required for etherscan input")
+	}
+
+	var response EtherscanResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("parsing etherscan response: %w", err)
+	}
+
+	if response.Status != "1" {
+		return nil, fmt.Errorf("etherscan request failed: %s: %s", response.Message, response.Result)
+	}
+
+	abiJSON := json.RawMessage(response.Result)
+	if !json.Valid(abiJSON) {
+		return nil, fmt.Errorf("etherscan result is not a valid ABI JSON string")
+	}
+
+	methodIds, err := methodIdentifiers(abiJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.CompileResult{
+		Contracts: map[string]map[string]types.ContractResult{
+			EtherscanSourceFile: {
+				contractName: types.ContractResult{
+					ABI: abiJSON,
+					EVM: types.EVMResult{
+						MethodIdentifiers: methodIds,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// methodIdentifiers computes the solc-style method identifiers of an ABI
+func methodIdentifiers(abiJSON json.RawMessage) (map[string]string, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(string(abiJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("parsing ABI: %w", err)
+	}
+
+	methodIds := make(map[string]string, len(parsedABI.Methods))
+	for _, method := range parsedABI.Methods {
+		methodIds[method.Sig] = hex.EncodeToString(method.ID)
+	}
+	return methodIds, nil
+}
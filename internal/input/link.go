@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MIT
+
+package input
+
+import (
+	"strings"
+
+	"github.com/otherview/solgen/internal/types"
+)
+
+// legacyPlaceholderLength is the hex length of a pre-0.5 "__path:Name____" placeholder
+const legacyPlaceholderLength = 40
+
+// LinkReferences synthesizes the solc link references of a hex bytecode object
+// from its library placeholders, for inputs such as combined JSON that only
+// carry the placeholders. Both the hashed "__$<hash>$__" form, keyed by the
+// hash under an empty source file, and the legacy "__<path>:<Name>___" form
+// are recognised. Returns nil when the bytecode needs no linking.
+func LinkReferences(object string) map[string]map[string][]types.LinkRef {
+	object = strings.TrimPrefix(object, "0x")
+
+	var linkRefs map[string]map[string][]types.LinkRef
+	for i := 0; i+1 < len(object); {
+		if object[i:i+2] != "__" || i%2 != 0 {
+			i++
+			continue
+		}
+
+		sourceFile, libName, length := parsePlaceholder(object[i:])
+		if length == 0 {
+			i++
+			continue
+		}
+
+		if linkRefs == nil {
+			linkRefs = make(map[string]map[string][]types.LinkRef)
+		}
+		if linkRefs[sourceFile] == nil {
+			linkRefs[sourceFile] = make(map[string][]types.LinkRef)
+		}
+		linkRefs[sourceFile][libName] = append(linkRefs[sourceFile][libName], types.LinkRef{
+			Start:  i / 2,
+			Length: length / 2,
+		})
+		i += length
+	}
+
+	return linkRefs
+}
+
+// parsePlaceholder parses the placeholder at the start of s and returns the
+// library source file, name and placeholder hex length, or a zero length if s
+// does not start with a placeholder
+func parsePlaceholder(s string) (string, string, int) {
+	if strings.HasPrefix(s, "__$") {
+		end := strings.Index(s[3:], "$__")
+		if end <= 0 {
+			return "", "", 0
+		}
+		return "", s[3 : 3+end], 3 + end + 3
+	}
+
+	if len(s) < legacyPlaceholderLength {
+		return "", "", 0
+	}
+	body := strings.TrimRight(s[2:legacyPlaceholderLength], "_")
+	sep := strings.LastIndex(body, ":")
+	if sep < 0 || sep == len(body)-1 {
+		return "", "", 0
+	}
+	return body[:sep], body[sep+1:], legacyPlaceholderLength
+}
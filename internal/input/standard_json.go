@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: MIT
+
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/otherview/solgen/internal/types"
+)
+
+// standardJSONMetadata holds the parts of the solc metadata solgen reads
+type standardJSONMetadata struct {
+	Compiler struct {
+		Version string `json:"version"`
+	} `json:"compiler"`
+}
+
+// StandardJSON reads the output of solc --standard-json. Besides the ABI it
+// keeps the method identifiers, bytecode and link references when they were
+// selected, and returns the compiler version recorded in the contract metadata,
+// or "unknown" when no metadata was selected.
+func StandardJSON(data []byte) (*types.CompileResult, string, error) {
+	var result types.CompileResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, "", fmt.Errorf("parsing standard JSON: %w", err)
+	}
+
+	var compileErrors []string
+	for _, compileError := range result.Errors {
+		if compileError.Severity == "error" {
+			compileErrors = append(compileErrors, compileError.Message)
+		}
+	}
+	if len(compileErrors) > 0 {
+		return nil, "", fmt.Errorf("solc reported errors: %s", strings.Join(compileErrors, "; "))
+	}
+
+	if len(result.Contracts) == 0 {
+		return nil, "", fmt.Errorf("no contracts found in standard JSON output")
+	}
+
+	for sourceFile, contracts := range result.Contracts {
+		for contractName, contract := range contracts {
+			if len(contract.ABI) == 0 {
+				return nil, "", fmt.Errorf("contract %s:%s has no ABI (add \"abi\" to outputSelection)", sourceFile, contractName)
+			}
+		}
+	}
+
+	solcVersion, err := standardJSONVersion(&result)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &result, solcVersion, nil
+}
+
+// standardJSONVersion returns the compiler version from the first contract
+// metadata, in source and contract order, or "unknown" if none is present
+func standardJSONVersion(result *types.CompileResult) (string, error) {
+	var keys []string
+	for sourceFile, contracts := range result.Contracts {
+		for contractName := range contracts {
+			keys = append(keys, sourceFile+":"+contractName)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		sourceFile, contractName, _ := strings.Cut(key, ":")
+		contract := result.Contracts[sourceFile][contractName]
+		if contract.Metadata == "" {
+			continue
+		}
+
+		var metadata standardJSONMetadata
+		if err := json.Unmarshal([]byte(contract.Metadata), &metadata); err != nil {
+			return "", fmt.Errorf("parsing metadata of %s: %w", key, err)
+		}
+		if metadata.Compiler.Version != "" {
+			return metadata.Compiler.Version, nil
+		}
+	}
+
+	return "unknown", nil
+}
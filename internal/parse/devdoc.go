@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/otherview/solgen/internal/types"
+)
+
+// deprecatedTag is the devdoc key of a method's @custom:deprecated notice
+const deprecatedTag = "custom:deprecated"
+
+// devDoc is the subset of the solc developer documentation solgen reads
+type devDoc struct {
+	Methods map[string]map[string]json.RawMessage `json:"methods"` // signature -> tag -> value
+}
+
+// devDocMetadata holds the developer documentation inside the solc metadata
+type devDocMetadata struct {
+	Output struct {
+		DevDoc json.RawMessage `json:"devdoc"`
+	} `json:"output"`
+}
+
+// applyDeprecations marks the methods whose devdoc carries @custom:deprecated.
+// The devdoc is read from the contract output, or from its metadata when only
+// the metadata was selected. Methods tagged without a notice are still marked.
+func applyDeprecations(contract *types.Contract, result types.ContractResult) error {
+	doc, err := contractDevDoc(result)
+	if err != nil {
+		return err
+	}
+
+	for i := range contract.Methods {
+		method := &contract.Methods[i]
+		raw, ok := doc.Methods[method.Signature][deprecatedTag]
+		if !ok {
+			continue
+		}
+		var notice string
+		if err := json.Unmarshal(raw, &notice); err != nil {
+			return fmt.Errorf("parsing devdoc of %s: %w", method.Signature, err)
+		}
+		// Notices may span several lines, the comment holds them as one
+		notice = strings.Join(strings.Fields(notice), " ")
+		if notice == "" {
+			notice = "marked @custom:deprecated in the contract devdoc."
+		}
+		method.Deprecated = notice
+	}
+	return nil
+}
+
+// contractDevDoc returns the developer documentation of a contract, which older
+// combined JSON output holds as a JSON encoded string
+func contractDevDoc(result types.ContractResult) (devDoc, error) {
+	var doc devDoc
+	raw := result.DevDoc
+	if len(raw) == 0 && result.Metadata != "" {
+		var metadata devDocMetadata
+		if err := json.Unmarshal([]byte(result.Metadata), &metadata); err != nil {
+			return doc, fmt.Errorf("parsing metadata: %w", err)
+		}
+		raw = metadata.Output.DevDoc
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return doc, nil
+	}
+
+	if raw[0] == '"' {
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return doc, fmt.Errorf("parsing devdoc: %w", err)
+		}
+		raw = json.RawMessage(encoded)
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return doc, fmt.Errorf("parsing devdoc: %w", err)
+	}
+	return doc, nil
+}
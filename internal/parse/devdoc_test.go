@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"testing"
+
+	"github.com/otherview/solgen/internal/types"
+)
+
+func TestDeprecationsFromMetadata(t *testing.T) {
+	result := &types.CompileResult{
+		Contracts: map[string]map[string]types.ContractResult{
+			"Vault.sol": {
+				"Vault": {
+					ABI: []byte(`[
+						{"type": "function", "name": "withdraw", "inputs": [], "outputs": [], "stateMutability": "nonpayable"},
+						{"type": "function", "name": "deposit", "inputs": [], "outputs": [], "stateMutability": "payable"}
+					]`),
+					EVM: types.EVMResult{
+						MethodIdentifiers: map[string]string{"withdraw()": "3ccfd60b", "deposit()": "d0e30db0"},
+					},
+					Metadata: `{"output": {"devdoc": {"methods": {"withdraw()": {"custom:deprecated": "Use withdrawTo."}}}}}`,
+				},
+			},
+		},
+	}
+
+	contracts, err := ResultWithVersion(result, "0.8.24")
+	if err != nil {
+		t.Fatalf("ResultWithVersion failed: %v", err)
+	}
+	for _, method := range contracts[0].Methods {
+		want := ""
+		if method.Name == "withdraw" {
+			want = "Use withdrawTo."
+		}
+		if method.Deprecated != want {
+			t.Errorf("method %s: expected deprecation %q, got %q", method.Name, want, method.Deprecated)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/otherview/solgen/internal/types"
+)
+
+// enumPrefix is the internalType prefix solc uses for enum parameters
+const enumPrefix = "enum "
+
+// rawABIEntry is the subset of an ABI entry needed to recover internal types,
+// go-ethereum only keeps internalType for tuples
+type rawABIEntry struct {
+	Type    string                   `json:"type"`
+	Name    string                   `json:"name"`
+	Inputs  []abi.ArgumentMarshaling `json:"inputs"`
+	Outputs []abi.ArgumentMarshaling `json:"outputs"`
+}
+
+// applyEnumTypes retypes enum parameters of methods, events and errors, and enum
+// fields of the structs they use, to named uint8 types and records the enums on
+// the contract, with their member count when the source AST defines them
+func applyEnumTypes(contract *types.Contract, rawABI []byte, members map[string]int) error {
+	var entries []rawABIEntry
+	if err := json.Unmarshal(rawABI, &entries); err != nil {
+		return fmt.Errorf("parsing raw ABI: %w", err)
+	}
+
+	enums := make(map[string]string) // Go name -> canonical name
+	for _, entry := range entries {
+		if entry.Type != "function" && entry.Type != "event" && entry.Type != "error" {
+			continue
+		}
+
+		signature, err := rawSignature(entry.Name, entry.Inputs)
+		if err != nil {
+			return fmt.Errorf("resolving signature of %s: %w", entry.Name, err)
+		}
+
+		switch entry.Type {
+		case "function":
+			for i := range contract.Methods {
+				method := &contract.Methods[i]
+				if method.Signature == signature {
+					retypeEnums(contract, method.Inputs, method.InputStruct, entry.Inputs, enums)
+					retypeEnums(contract, method.Outputs, method.OutputStruct, entry.Outputs, enums)
+				}
+			}
+		case "event":
+			topic := types.Hash(crypto.Keccak256Hash([]byte(signature)))
+			for i := range contract.Events {
+				event := &contract.Events[i]
+				if event.Topic == topic {
+					retypeEnums(contract, event.Inputs, event.Struct, entry.Inputs, enums)
+				}
+			}
+		case "error":
+			for i := range contract.Errors {
+				contractError := &contract.Errors[i]
+				if contractError.Signature == signature {
+					retypeEnums(contract, contractError.Inputs, contractError.Struct, entry.Inputs, enums)
+				}
+			}
+		}
+	}
+
+	for name, canonicalName := range enums {
+		contract.Enums = append(contract.Enums, types.Enum{Name: name, Members: members[canonicalName]})
+	}
+	sort.Slice(contract.Enums, func(i, j int) bool {
+		return contract.Enums[i].Name < contract.Enums[j].Name
+	})
+	return nil
+}
+
+// retypeEnums updates enum parameters, and the matching struct fields, in place
+func retypeEnums(contract *types.Contract, params []types.Parameter, paramStruct *types.Struct, raw []abi.ArgumentMarshaling, enums map[string]string) {
+	for i := range params {
+		if i >= len(raw) {
+			return
+		}
+		retypeStructEnums(contract, params[i].Type, raw[i].Components, enums)
+
+		name := enumName(raw[i].InternalType)
+		if name == "" || params[i].Type.TypeName != "uint8" {
+			continue
+		}
+
+		enumType := types.GoType{TypeName: name, IsEnum: true}
+		params[i].Type = enumType
+		if paramStruct != nil && i < len(paramStruct.Fields) {
+			paramStruct.Fields[i].Type = enumType
+		}
+		enums[name] = strings.TrimPrefix(raw[i].InternalType, enumPrefix)
+	}
+}
+
+// retypeStructEnums updates the enum fields of the struct a tuple parameter of
+// goType refers to, and of the structs nested in it, from the raw components
+func retypeStructEnums(contract *types.Contract, goType types.GoType, components []abi.ArgumentMarshaling, enums map[string]string) {
+	if len(components) == 0 {
+		return
+	}
+	// Arrays of structs, e.g. []Order, refer to their element struct
+	structName := goType.TypeName[strings.LastIndex(goType.TypeName, "]")+1:]
+	for s := range contract.Structs {
+		if contract.Structs[s].Name != structName {
+			continue
+		}
+		fields := contract.Structs[s].Fields
+		for i := range fields {
+			if i >= len(components) {
+				return
+			}
+			if name := enumName(components[i].InternalType); name != "" && fields[i].Type.TypeName == "uint8" {
+				fields[i].Type = types.GoType{TypeName: name, IsEnum: true}
+				enums[name] = strings.TrimPrefix(components[i].InternalType, enumPrefix)
+			}
+			retypeStructEnums(contract, fields[i].Type, components[i].Components, enums)
+		}
+	}
+}
+
+// enumName extracts the Go type name from an enum internalType, e.g.
+// "enum Vault.Status" -> "Status". Enum arrays are not supported and yield "".
+func enumName(internalType string) string {
+	if !strings.HasPrefix(internalType, enumPrefix) || strings.Contains(internalType, "[") {
+		return ""
+	}
+	name := strings.TrimPrefix(internalType, enumPrefix)
+	if i := strings.LastIndex(name, "."); i != -1 {
+		name = name[i+1:]
+	}
+	return exportIdentifier(name)
+}
+
+// enumMembers counts the members of every enum defined in the source ASTs,
+// keyed by canonical name, e.g. "Vault.Status". Only the compact AST solc
+// emits since 0.8 is read, enums are absent when no AST was selected.
+func enumMembers(sources map[string]types.SourceResult) map[string]int {
+	members := make(map[string]int)
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			if n["nodeType"] == "EnumDefinition" {
+				name, _ := n["canonicalName"].(string)
+				values, _ := n["members"].([]interface{})
+				if name != "" && len(values) > 0 {
+					members[name] = len(values)
+				}
+				return
+			}
+			for _, child := range n {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range n {
+				walk(child)
+			}
+		}
+	}
+	for _, source := range sources {
+		walk(source.AST)
+	}
+	return members
+}
+
+// rawSignature computes the canonical signature of a raw ABI entry
+func rawSignature(name string, inputs []abi.ArgumentMarshaling) (string, error) {
+	var params []string
+	for _, input := range inputs {
+		abiType, err := abi.NewType(input.Type, input.InternalType, input.Components)
+		if err != nil {
+			return "", err
+		}
+		params = append(params, abiType.String())
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(params, ",")), nil
+}
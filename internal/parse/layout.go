@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"path"
+	"strings"
+
+	"github.com/otherview/solgen/internal/types"
+)
+
+// LoadLayout parses a JSON layout, keyed by contract name, e.g.
+//
+//	{"ERC20Token": {"dir": "tokens/erc20", "package": "token"}}
+//
+// Directories are relative to the output directory. The package name defaults
+// to the last element of the directory. Contracts sharing their name, declared
+// in different source files, are told apart by keys such as "nft/ERC20.sol:Token".
+func LoadLayout(data []byte) (types.Layout, error) {
+	var layout types.Layout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("parsing layout: %w", err)
+	}
+
+	for contractName, entry := range layout {
+		if entry.Dir == "" {
+			return nil, fmt.Errorf("layout entry %q: missing dir", contractName)
+		}
+		if strings.Contains(entry.Dir, `\`) || path.IsAbs(entry.Dir) || path.Clean(entry.Dir) != entry.Dir ||
+			entry.Dir == "." || entry.Dir == ".." || strings.HasPrefix(entry.Dir, "../") {
+			return nil, fmt.Errorf("layout entry %q: dir %q must be a clean slash-separated path inside the output directory", contractName, entry.Dir)
+		}
+		if entry.Package == "" {
+			entry.Package = sanitizePackageName(path.Base(entry.Dir))
+		}
+		if !token.IsIdentifier(entry.Package) {
+			return nil, fmt.Errorf("layout entry %q: %q is not a valid package name", contractName, entry.Package)
+		}
+		layout[contractName] = entry
+	}
+
+	return layout, nil
+}
+
+// contractDir returns the output subdirectory and package name of a contract
+func contractDir(sourceFile, contractName string, layout types.Layout) (string, string) {
+	if entry, ok := layoutEntry(sourceFile, contractName, layout); ok {
+		return entry.Dir, entry.Package
+	}
+	pkgName := sanitizePackageName(contractName)
+	return pkgName, pkgName
+}
+
+// layoutEntry returns the layout of a contract, preferring the entry keyed by its
+// source file and name over the one keyed by its name only
+func layoutEntry(sourceFile, contractName string, layout types.Layout) (types.PackageLayout, bool) {
+	if entry, ok := layout[sourceFile+":"+contractName]; ok {
+		return entry, true
+	}
+	entry, ok := layout[contractName]
+	return entry, ok
+}
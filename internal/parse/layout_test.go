@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/otherview/solgen/internal/types"
+)
+
+func TestLoadLayoutRejectsInvalidEntries(t *testing.T) {
+	for name, data := range map[string]string{
+		"invalid json":      `{"Token": `,
+		"missing dir":       `{"Token": {"package": "token"}}`,
+		"absolute dir":      `{"Token": {"dir": "/tmp/token"}}`,
+		"escaping dir":      `{"Token": {"dir": "../token"}}`,
+		"unclean dir":       `{"Token": {"dir": "tokens//erc20"}}`,
+		"backslash dir":     `{"Token": {"dir": "tokens\\erc20"}}`,
+		"invalid package":   `{"Token": {"dir": "token", "package": "my-token"}}`,
+		"keyword package":   `{"Token": {"dir": "token", "package": "type"}}`,
+		"output dir itself": `{"Token": {"dir": "."}}`,
+	} {
+		if _, err := LoadLayout([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	layout, err := LoadLayout([]byte(`{"ERC20Token": {"dir": "tokens/erc20", "package": "token"}, "Vault": {"dir": "defi/Vault-V2"}}`))
+	if err != nil {
+		t.Fatalf("LoadLayout failed: %v", err)
+	}
+	if entry := layout["ERC20Token"]; entry.Dir != "tokens/erc20" || entry.Package != "token" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	// The package name defaults to the sanitized last element of the directory
+	if entry := layout["Vault"]; entry.Dir != "defi/Vault-V2" || entry.Package != "vaultv2" {
+		t.Errorf("unexpected default package: %+v", entry)
+	}
+}
+
+func TestLayoutSameContractNameInDifferentSources(t *testing.T) {
+	token := types.ContractResult{ABI: json.RawMessage(`[]`)}
+	result := &types.CompileResult{
+		Contracts: map[string]map[string]types.ContractResult{
+			"token/ERC20.sol": {"Token": token},
+			"nft/ERC20.sol":   {"Token": token},
+		},
+	}
+
+	// The error names both source files so that the contracts can be told apart
+	_, err := ResultWithOptions(result, "", Options{})
+	if err == nil || !strings.Contains(err.Error(), `contracts [nft/ERC20.sol:Token token/ERC20.sol:Token]`) ||
+		!strings.Contains(err.Error(), `"nft/ERC20.sol:Token"`) {
+		t.Fatalf("expected a collision naming both source files, got: %v", err)
+	}
+
+	layout, err := LoadLayout([]byte(`{"nft/ERC20.sol:Token": {"dir": "nft/token"}}`))
+	if err != nil {
+		t.Fatalf("LoadLayout failed: %v", err)
+	}
+	contracts, err := ResultWithOptions(result, "", Options{Layout: layout})
+	if err != nil {
+		t.Fatalf("ResultWithOptions failed: %v", err)
+	}
+	dirs := map[string]string{}
+	for _, contract := range contracts {
+		dirs[contract.SourceFile] = contract.Dir
+	}
+	if dirs["nft/ERC20.sol"] != "nft/token" || dirs["token/ERC20.sol"] != "" {
+		t.Errorf("expected only the nft Token to be laid out, got %v", dirs)
+	}
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func TestOverloadNamesCollisionProof(t *testing.T) {
+	// foo(uint256[1]) and foo(uint256[2]) both normalize to foo_Uint256FixedArray,
+	// and the standalone foo_Uint256Array matches the name derived for foo(uint256[])
+	abiJSON := `[
+		{"type": "function", "name": "foo", "inputs": [{"name": "a", "type": "uint256[]"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "foo", "inputs": [{"name": "a", "type": "uint256[1]"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "foo", "inputs": [{"name": "a", "type": "uint256[2]"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "foo", "inputs": [{"name": "a", "type": "address"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "foo_Uint256Array", "inputs": [], "outputs": [], "stateMutability": "nonpayable"}
+	]`
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+
+	methodIds := make(map[string]string)
+	for _, method := range parsedABI.Methods {
+		methodIds[method.Sig] = hex.EncodeToString(method.ID)
+	}
+
+	methods, err := parseMethodsWithRegistry(parsedABI, methodIds, newStructRegistry())
+	if err != nil {
+		t.Fatalf("parseMethodsWithRegistry failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"foo(uint256[])":     "foo__" + methodIds["foo(uint256[])"],
+		"foo(uint256[1])":    "foo__" + methodIds["foo(uint256[1])"],
+		"foo(uint256[2])":    "foo__" + methodIds["foo(uint256[2])"],
+		"foo(address)":       "foo_Address",
+		"foo_Uint256Array()": "foo_Uint256Array",
+	}
+
+	seen := make(map[string]bool)
+	for _, method := range methods {
+		if want := expected[method.Signature]; method.Name != want {
+			t.Errorf("method %s: expected name %q, got %q", method.Signature, want, method.Name)
+		}
+		if seen[method.Name] {
+			t.Errorf("duplicate method name %q", method.Name)
+		}
+		seen[method.Name] = true
+	}
+	if len(methods) != len(expected) {
+		t.Errorf("expected %d methods, got %d", len(expected), len(methods))
+	}
+}
+
+func TestOverloadNameSelectorFallback(t *testing.T) {
+	// Long candidates fall back to the full selector, regardless of a 0x prefix
+	signature := "foo(uint256,uint256,uint256,uint256,uint256,uint256,uint256)"
+	for _, selector := range []string{"deadbeef", "0xdeadbeef"} {
+		if got := generateOverloadName("foo", signature, selector); got != "foo__deadbeef" {
+			t.Errorf("selector %s: expected foo__deadbeef, got %q", selector, got)
+		}
+	}
+}
+
+func TestDefaultOverloads(t *testing.T) {
+	abiJSON := `[
+		{"type": "function", "name": "mint", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "mint", "inputs": [{"name": "to", "type": "address"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "burn", "inputs": [{"name": "id", "type": "uint256"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "burn", "inputs": [{"name": "owner", "type": "address"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "pause", "inputs": [], "outputs": [], "stateMutability": "nonpayable"}
+	]`
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+
+	methodIds := make(map[string]string)
+	for _, method := range parsedABI.Methods {
+		methodIds[method.Sig] = hex.EncodeToString(method.ID)
+	}
+
+	methods, err := parseMethodsWithRegistry(parsedABI, methodIds, newStructRegistry())
+	if err != nil {
+		t.Fatalf("parseMethodsWithRegistry failed: %v", err)
+	}
+
+	// Fewest parameters wins, then the lowest signature
+	expected := map[string]bool{
+		"mint(address)":         true,
+		"mint(address,uint256)": false,
+		"burn(address)":         true,
+		"burn(uint256)":         false,
+		"pause()":               false,
+	}
+	for _, method := range methods {
+		if method.DefaultOverload != expected[method.Signature] {
+			t.Errorf("method %s: expected default overload %v", method.Signature, expected[method.Signature])
+		}
+		if method.Signature == "mint(address)" && method.BaseName != "mint" {
+			t.Errorf("expected base name mint, got %q", method.BaseName)
+		}
+	}
+}
+
+func TestSelectorCollision(t *testing.T) {
+	abiJSON := `[
+		{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "approve", "inputs": [{"name": "spender", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [], "stateMutability": "nonpayable"}
+	]`
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+
+	// Hand-assembled identifiers giving both methods the same selector
+	methodIds := map[string]string{
+		"transfer(address,uint256)": "a9059cbb",
+		"approve(address,uint256)":  "A9059CBB",
+	}
+
+	_, err = parseMethodsWithRegistry(parsedABI, methodIds, newStructRegistry())
+	if err == nil {
+		t.Fatal("expected a selector collision error")
+	}
+	want := "selector collision: approve(address,uint256) and transfer(address,uint256) share selector 0xa9059cbb"
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestMethodIdsNormalized(t *testing.T) {
+	abiJSON := `[
+		{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "value", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}], "outputs": [], "stateMutability": "nonpayable"}
+	]`
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+
+	// Selectors written by tools other than solc may be 0x-prefixed or uppercase
+	methodIds := map[string]string{
+		"transfer(address,uint256)": "0xA9059CBB",
+		"transfer(address)":         "1A695230",
+	}
+
+	methods, err := parseMethodsWithRegistry(parsedABI, methodIds, newStructRegistry())
+	if err != nil {
+		t.Fatalf("parseMethodsWithRegistry failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"transfer(address,uint256)": "0xa9059cbb",
+		"transfer(address)":         "0x1a695230",
+	}
+	for _, method := range methods {
+		if want := expected[method.Signature]; string(method.Selector) != want {
+			t.Errorf("method %s: expected selector %s, got %s", method.Signature, want, method.Selector)
+		}
+	}
+	if len(methods) != len(expected) {
+		t.Errorf("expected %d methods, got %d", len(expected), len(methods))
+	}
+}
@@ -3,6 +3,7 @@
 package parse
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -15,7 +16,10 @@ import (
 
 // structRegistry holds struct definitions collected during parsing
 type structRegistry struct {
-	structs map[string]types.Struct // key: struct name, value: struct definition
+	structs  map[string]types.Struct // key: struct name, value: struct definition
+	typeMap  types.TypeMap           // Go type overrides applied to struct fields
+	names    map[string]string       // key: go-ethereum tuple raw name, value: struct name from internalType
+	maxDepth int                     // maximum nesting of arrays and tuples, DefaultMaxDepth when zero
 }
 
 // newStructRegistry creates a new struct registry
@@ -25,10 +29,26 @@ func newStructRegistry() *structRegistry {
 	}
 }
 
+// DefaultMaxDepth is the maximum nesting of arrays and tuples in a type when
+// Options.MaxDepth is not set
+const DefaultMaxDepth = 32
+
+// Options holds optional settings for parsing
+type Options struct {
+	TypeMap  types.TypeMap // Go type overrides for struct field types, keyed by Solidity type
+	Layout   types.Layout  // Output subdirectory and package name overrides, keyed by contract name
+	MaxDepth int           // Maximum nesting of arrays and tuples in a type, DefaultMaxDepth when zero
+
+	// QualifyStructs keeps the contract qualifier of structs whose bare name is
+	// declared by several contracts in one ABI, e.g. A.User and B.User become
+	// AUser and BUser instead of both being generated as User
+	QualifyStructs bool
+}
+
 // registerStruct adds a struct definition to the registry
 func (r *structRegistry) registerStruct(structName string, abiType abi.Type) {
-	if structName == "" || structName == "AnonymousTuple" {
-		return // Don't register anonymous tuples
+	if structName == "" {
+		return
 	}
 	
 	// Don't re-register if already exists
@@ -38,28 +58,87 @@ func (r *structRegistry) registerStruct(structName string, abiType abi.Type) {
 	
 	// Convert tuple elements to struct fields
 	var fields []types.StructField
+	used := make(map[string]bool)
 	for i, elemType := range abiType.TupleElems {
 		goType, err := mapSolidityToGoTypeWithRegistry(*elemType, r)
 		if err != nil {
 			continue // Skip problematic fields for now
 		}
+		goType = applyTypeMap(goType, *elemType, r.typeMap)
 		
 		fieldName := "Field" + fmt.Sprintf("%d", i+1) // Default field name
 		if i < len(abiType.TupleRawNames) && abiType.TupleRawNames[i] != "" {
-			fieldName = exportIdentifier(abiType.TupleRawNames[i])
+			// Exported names never clash with Go keywords such as type or func
+			fieldName = exportIdentifier(sanitizeIdentifier(abiType.TupleRawNames[i]))
 		}
+		// Components such as value and _value export to the same name
+		for base, n := fieldName, 0; used[fieldName]; n++ {
+			fieldName = fmt.Sprintf("%s%d", base, n)
+		}
+		used[fieldName] = true
 		
 		fields = append(fields, types.StructField{
 			Name:    fieldName,
 			Type:    goType,
 			JSONTag: strings.ToLower(fieldName),
+			Dynamic: isDynamicType(*elemType),
+			Size:    headSize(*elemType),
 		})
 	}
 	
 	r.structs[structName] = types.Struct{
-		Name:   structName,
-		Fields: fields,
+		Name:    structName,
+		Fields:  fields,
+		Dynamic: isDynamicType(abiType),
+	}
+}
+
+// structName returns the struct name for a go-ethereum tuple raw name, preferring
+// the name recovered from the internalType of the raw ABI
+func (r *structRegistry) structName(rawName string) string {
+	if name, ok := r.names[rawName]; ok {
+		return name
+	}
+	return extractStructName(rawName)
+}
+
+// maxTupleNameLength bounds the struct names derived from the element types of
+// anonymous tuples, longer ones are named after a hash of the tuple type
+const maxTupleNameLength = 50
+
+// tupleName returns the struct name of a tuple type. Tuples without an
+// internalType are named after their element types, e.g. TupleUint256Address
+// for (uint256,address), so that identical anonymous tuples share a struct.
+func tupleName(abiType abi.Type, registry *structRegistry) string {
+	name := extractStructName(abiType.TupleRawName)
+	if registry != nil {
+		name = registry.structName(abiType.TupleRawName)
+	}
+	if name != "" {
+		return name
+	}
+
+	name = "Tuple"
+	for _, elem := range abiType.TupleElems {
+		name += typeNamePart(*elem, registry)
+	}
+	if len(name) > maxTupleNameLength {
+		return fmt.Sprintf("Tuple%x", crypto.Keccak256([]byte(abiType.String()))[:4])
+	}
+	return name
+}
+
+// typeNamePart names an element type within the name of an anonymous tuple
+func typeNamePart(abiType abi.Type, registry *structRegistry) string {
+	switch abiType.T {
+	case abi.TupleTy:
+		return tupleName(abiType, registry)
+	case abi.SliceTy:
+		return typeNamePart(*abiType.Elem, registry) + "Array"
+	case abi.ArrayTy:
+		return fmt.Sprintf("%sArray%d", typeNamePart(*abiType.Elem, registry), abiType.Size)
 	}
+	return normalizeTypeForNaming(abiType.String())
 }
 
 // getAllStructs returns all registered structs as a slice
@@ -77,32 +156,54 @@ func (r *structRegistry) getAllStructs() []types.Struct {
 
 // ResultWithVersion converts solc compilation result with version info
 func ResultWithVersion(result *types.CompileResult, solcVersion string) ([]*types.Contract, error) {
+	return ResultWithOptions(result, solcVersion, Options{})
+}
+
+// ResultWithOptions converts solc compilation result with version info and the given options
+func ResultWithOptions(result *types.CompileResult, solcVersion string, options Options) ([]*types.Contract, error) {
 	var contracts []*types.Contract
-	nameCollisions := make(map[string][]string) // package name -> contract names
+	nameCollisions := make(map[string][]string) // package directory -> contract names
+	laidOut := make(map[string]bool)
 
-	// First pass: collect all contracts and check for package name collisions
+	// First pass: collect all contracts and check for package directory collisions
 	for sourceFile, sourceContracts := range result.Contracts {
 		for contractName := range sourceContracts {
-			pkgName := sanitizePackageName(contractName)
-			nameCollisions[pkgName] = append(nameCollisions[pkgName], fmt.Sprintf("%s:%s", sourceFile, contractName))
+			dir, _ := contractDir(sourceFile, contractName, options.Layout)
+			nameCollisions[dir] = append(nameCollisions[dir], fmt.Sprintf("%s:%s", sourceFile, contractName))
+			laidOut[contractName] = true
+			laidOut[sourceFile+":"+contractName] = true
 		}
 	}
 
 	// Check for collisions
-	for pkgName, contractNames := range nameCollisions {
+	for dir, contractNames := range nameCollisions {
 		if len(contractNames) > 1 {
-			return nil, fmt.Errorf("package name collision for %q: contracts %v would generate the same package name", pkgName, contractNames)
+			// Contracts of the same name in different source files are placed apart by
+			// layout entries keyed by their source file
+			sort.Strings(contractNames)
+			return nil, fmt.Errorf("package name collision for %q: contracts %v would generate the same package name, "+
+				"give them separate directories with layout entries keyed by source file and contract name, e.g. %q",
+				dir, contractNames, contractNames[0])
+		}
+	}
+	for contractName := range options.Layout {
+		if !laidOut[contractName] {
+			return nil, fmt.Errorf("layout entry %q does not match any contract", contractName)
 		}
 	}
 
 	// Second pass: parse contracts
+	members := enumMembers(result.Sources)
 	for sourceFile, sourceContracts := range result.Contracts {
 		for contractName, contractResult := range sourceContracts {
-			contract, err := parseContract(sourceFile, contractName, contractResult)
+			contract, err := parseContract(sourceFile, contractName, contractResult, members, options)
 			if err != nil {
 				return nil, fmt.Errorf("parsing contract %s:%s: %w", sourceFile, contractName, err)
 			}
-			contract.SolcVersion = solcVersion
+			contract.SolcVersion = NormalizeSolcVersion(solcVersion)
+			if entry, ok := layoutEntry(sourceFile, contractName, options.Layout); ok {
+				contract.Dir, contract.PackageName = entry.Dir, entry.Package
+			}
 			contracts = append(contracts, contract)
 		}
 	}
@@ -119,15 +220,24 @@ func ResultWithVersion(result *types.CompileResult, solcVersion string) ([]*type
 }
 
 // parseContract parses a single contract from solc output
-func parseContract(sourceFile, contractName string, result types.ContractResult) (*types.Contract, error) {
+func parseContract(sourceFile, contractName string, result types.ContractResult, members map[string]int, options Options) (*types.Contract, error) {
 	// Parse ABI
+	if err := validateABI(result.ABI); err != nil {
+		return nil, err
+	}
 	parsedABI, err := abi.JSON(strings.NewReader(string(result.ABI)))
 	if err != nil {
-		return nil, fmt.Errorf("parsing ABI: %w", err)
+		return nil, fmt.Errorf("parsing ABI: %w: %s", err, snippet(result.ABI))
 	}
 
 	// Create struct registry to collect struct definitions
 	registry := newStructRegistry()
+	registry.typeMap = options.TypeMap
+	registry.maxDepth = options.MaxDepth
+	registry.names, err = structNames(result.ABI, options.QualifyStructs)
+	if err != nil {
+		return nil, fmt.Errorf("parsing struct names: %w", err)
+	}
 
 	contract := &types.Contract{
 		Name:             contractName,
@@ -153,44 +263,47 @@ func parseContract(sourceFile, contractName string, result types.ContractResult)
 	contract.Events = events
 
 	// Parse errors
-	errors, err := parseErrors(parsedABI)
+	errors, err := parseErrorsWithRegistry(parsedABI, registry)
 	if err != nil {
 		return nil, fmt.Errorf("parsing errors: %w", err)
 	}
 	contract.Errors = errors
 
 	// Parse constructor
-	constructor := parseConstructor(parsedABI, result.EVM.Bytecode.LinkReferences)
+	constructor := parseConstructorWithRegistry(parsedABI, result.EVM.Bytecode.LinkReferences, registry)
 	contract.Constructor = constructor
 
 	// Add all collected struct definitions
 	contract.Structs = registry.getAllStructs()
 
+	// Recover enum types, which go-ethereum drops while parsing
+	if err := applyEnumTypes(contract, result.ABI, members); err != nil {
+		return nil, fmt.Errorf("parsing enums: %w", err)
+	}
+
+	// Mark the methods the devdoc deprecates
+	if err := applyDeprecations(contract, result); err != nil {
+		return nil, err
+	}
+
 	return contract, nil
 }
 
 // parseMethodsWithRegistry extracts and processes contract methods using struct registry
 func parseMethodsWithRegistry(parsedABI abi.ABI, methodIds map[string]string, registry *structRegistry) ([]types.Method, error) {
 	var methods []types.Method
-	methodNames := make(map[string]int) // track name collisions
+	methodIds = normalizeMethodIds(methodIds)
 
-	// First pass: count method names for overload detection
-	for _, method := range parsedABI.Methods {
-		methodNames[method.Name]++
+	// First pass: resolve unique method names, including overloads
+	methodNames, err := resolveMethodNames(parsedABI, methodIds)
+	if err != nil {
+		return nil, err
 	}
 
 	// Second pass: create method descriptors
 	for _, method := range parsedABI.Methods {
 		selector := methodIds[method.Sig]
-		if selector == "" {
-			return nil, fmt.Errorf("missing method identifier for %s", method.Sig)
-		}
-
-		// Generate method name with overload suffix if needed
-		methodName := method.Name
-		if methodNames[method.Name] > 1 {
-			methodName = generateOverloadName(method.Name, method.Sig, selector)
-		}
+		methodName := methodNames[method.Sig]
 
 		// Parse inputs and outputs with registry
 		inputs, err := parseParametersWithRegistry(method.Inputs, false, registry)
@@ -222,12 +335,15 @@ func parseMethodsWithRegistry(parsedABI abi.ABI, methodIds map[string]string, re
 
 		methods = append(methods, types.Method{
 			Name:         methodName,
+			BaseName:     method.RawName,
 			Signature:    method.Sig,
 			Selector:     types.HexData("0x" + selector),
 			Inputs:       inputs,
 			Outputs:      outputs,
 			InputStruct:  inputStruct,
 			OutputStruct: outputStruct,
+			Payable:      method.IsPayable(),
+			View:         method.IsConstant(),
 		})
 	}
 
@@ -238,6 +354,7 @@ func parseMethodsWithRegistry(parsedABI abi.ABI, methodIds map[string]string, re
 		}
 		return methods[i].Signature < methods[j].Signature
 	})
+	markDefaultOverloads(methods)
 
 	return methods, nil
 }
@@ -245,25 +362,18 @@ func parseMethodsWithRegistry(parsedABI abi.ABI, methodIds map[string]string, re
 // parseMethods extracts and processes contract methods
 func parseMethods(parsedABI abi.ABI, methodIds map[string]string) ([]types.Method, error) {
 	var methods []types.Method
-	methodNames := make(map[string]int) // track name collisions
+	methodIds = normalizeMethodIds(methodIds)
 
-	// First pass: count method names for overload detection
-	for _, method := range parsedABI.Methods {
-		methodNames[method.Name]++
+	// First pass: resolve unique method names, including overloads
+	methodNames, err := resolveMethodNames(parsedABI, methodIds)
+	if err != nil {
+		return nil, err
 	}
 
 	// Second pass: create method descriptors
 	for _, method := range parsedABI.Methods {
 		selector := methodIds[method.Sig]
-		if selector == "" {
-			return nil, fmt.Errorf("missing method identifier for %s", method.Sig)
-		}
-
-		// Generate method name with overload suffix if needed
-		methodName := method.Name
-		if methodNames[method.Name] > 1 {
-			methodName = generateOverloadName(method.Name, method.Sig, selector)
-		}
+		methodName := methodNames[method.Sig]
 
 		// Parse inputs and outputs
 		inputs, err := parseParameters(method.Inputs, false)
@@ -295,12 +405,15 @@ func parseMethods(parsedABI abi.ABI, methodIds map[string]string) ([]types.Metho
 
 		methods = append(methods, types.Method{
 			Name:         methodName,
+			BaseName:     method.RawName,
 			Signature:    method.Sig,
 			Selector:     types.HexData(prefixHex(selector)),
 			Inputs:       inputs,
 			Outputs:      outputs,
 			InputStruct:  inputStruct,
 			OutputStruct: outputStruct,
+			Payable:      method.IsPayable(),
+			View:         method.IsConstant(),
 		})
 	}
 
@@ -311,6 +424,7 @@ func parseMethods(parsedABI abi.ABI, methodIds map[string]string) ([]types.Metho
 		}
 		return methods[i].Signature < methods[j].Signature
 	})
+	markDefaultOverloads(methods)
 
 	return methods, nil
 }
@@ -332,7 +446,7 @@ func parseEventsWithRegistry(parsedABI abi.ABI, registry *structRegistry) ([]typ
 		// Create event struct
 		eventStruct := &types.Struct{
 			Name:   event.Name + "Event",
-			Fields: parametersToFields(inputs),
+			Fields: eventFields(inputs),
 		}
 
 		// Convert common.Hash to types.Hash
@@ -340,13 +454,19 @@ func parseEventsWithRegistry(parsedABI abi.ABI, registry *structRegistry) ([]typ
 		copy(typesHash[:], topic[:])
 		
 		events = append(events, types.Event{
-			Name:   event.Name,
-			Topic:  typesHash,
-			Inputs: inputs,
-			Struct: eventStruct,
+			Name:      event.Name,
+			Signature: event.Sig,
+			Topic:     typesHash,
+			Inputs:    inputs,
+			Struct:    eventStruct,
 		})
 	}
 
+	// Sort events for deterministic output
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Name < events[j].Name
+	})
+
 	return events, nil
 }
 
@@ -367,7 +487,7 @@ func parseEvents(parsedABI abi.ABI) ([]types.Event, error) {
 		// Create event struct
 		eventStruct := &types.Struct{
 			Name:   event.Name + "Event",
-			Fields: parametersToFields(inputs),
+			Fields: eventFields(inputs),
 		}
 
 		// Convert common.Hash to types.Hash
@@ -375,10 +495,11 @@ func parseEvents(parsedABI abi.ABI) ([]types.Event, error) {
 		copy(typesHash[:], topic[:])
 		
 		events = append(events, types.Event{
-			Name:   event.Name,
-			Topic:  typesHash,
-			Inputs: inputs,
-			Struct: eventStruct,
+			Name:      event.Name,
+			Signature: event.Sig,
+			Topic:     typesHash,
+			Inputs:    inputs,
+			Struct:    eventStruct,
 		})
 	}
 
@@ -390,16 +511,16 @@ func parseEvents(parsedABI abi.ABI) ([]types.Event, error) {
 	return events, nil
 }
 
-// parseErrors extracts and processes contract errors
-func parseErrors(parsedABI abi.ABI) ([]types.ContractError, error) {
+// parseErrorsWithRegistry extracts and processes contract errors using struct registry
+func parseErrorsWithRegistry(parsedABI abi.ABI, registry *structRegistry) ([]types.ContractError, error) {
 	var errors []types.ContractError
 
 	for _, abiError := range parsedABI.Errors {
 		// Calculate error selector (first 4 bytes of signature hash)
 		selector := common.BytesToHash(crypto.Keccak256([]byte(abiError.Sig))).Hex()[:10]
 
-		// Parse error inputs
-		inputs, err := parseParameters(abiError.Inputs, false)
+		// Parse error inputs with registry
+		inputs, err := parseParametersWithRegistry(abiError.Inputs, false, registry)
 		if err != nil {
 			return nil, fmt.Errorf("parsing inputs for error %s: %w", abiError.Sig, err)
 		}
@@ -427,14 +548,14 @@ func parseErrors(parsedABI abi.ABI) ([]types.ContractError, error) {
 	return errors, nil
 }
 
-// parseConstructor extracts constructor information
-func parseConstructor(parsedABI abi.ABI, linkRefs map[string]map[string][]types.LinkRef) *types.Constructor {
+// parseConstructorWithRegistry extracts constructor information using struct registry
+func parseConstructorWithRegistry(parsedABI abi.ABI, linkRefs map[string]map[string][]types.LinkRef, registry *structRegistry) *types.Constructor {
 	constructor := parsedABI.Constructor
 	if constructor.Type != abi.Constructor {
 		return nil
 	}
 
-	inputs, err := parseParameters(constructor.Inputs, false)
+	inputs, err := parseParametersWithRegistry(constructor.Inputs, false, registry)
 	if err != nil {
 		// Log error but don't fail, constructor is optional
 		return nil
@@ -466,6 +587,7 @@ func parseConstructor(parsedABI abi.ABI, linkRefs map[string]map[string][]types.
 		Inputs:         inputs,
 		InputStruct:    inputStruct,
 		LinkReferences: linkReferences,
+		Payable:        constructor.IsPayable(),
 	}
 }
 
@@ -487,7 +609,11 @@ func parseParametersWithRegistry(args abi.Arguments, allowIndexed bool, registry
 		params = append(params, types.Parameter{
 			Name:    sanitizeIdentifier(name),
 			Type:    goType,
+			ABIType: arg.Type.String(),
 			Indexed: allowIndexed && arg.Indexed,
+			Hashed:  allowIndexed && arg.Indexed && isHashedTopic(arg.Type),
+			Dynamic: isDynamicType(arg.Type),
+			Size:    headSize(arg.Type),
 		})
 	}
 
@@ -512,7 +638,11 @@ func parseParameters(args abi.Arguments, allowIndexed bool) ([]types.Parameter,
 		params = append(params, types.Parameter{
 			Name:    sanitizeIdentifier(name),
 			Type:    goType,
+			ABIType: arg.Type.String(),
 			Indexed: allowIndexed && arg.Indexed,
+			Hashed:  allowIndexed && arg.Indexed && isHashedTopic(arg.Type),
+			Dynamic: isDynamicType(arg.Type),
+			Size:    headSize(arg.Type),
 		})
 	}
 
@@ -529,12 +659,42 @@ func parametersToFields(params []types.Parameter) []types.StructField {
 			Name:    exportIdentifier(param.Name),
 			Type:    param.Type,
 			JSONTag: jsonTag,
+			Dynamic: param.Dynamic,
 		})
 	}
 
 	return fields
 }
 
+// eventFields converts event parameters to struct fields, adding a companion
+// <Field>Hash field after each hashed indexed parameter
+func eventFields(params []types.Parameter) []types.StructField {
+	var fields []types.StructField
+
+	for _, param := range params {
+		fields = append(fields, parametersToFields([]types.Parameter{param})...)
+		if param.Hashed {
+			fields = append(fields, types.StructField{
+				Name:    exportIdentifier(param.Name) + "Hash",
+				Type:    types.GoTypeHash,
+				JSONTag: strings.ToLower(param.Name) + "Hash",
+			})
+		}
+	}
+
+	return fields
+}
+
+// isHashedTopic reports whether an indexed event parameter of this type is
+// stored in its topic as the keccak256 hash of its encoding
+func isHashedTopic(abiType abi.Type) bool {
+	switch abiType.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	}
+	return false
+}
+
 // mapSolidityToGoType maps Solidity types to Go types
 func mapSolidityToGoType(abiType abi.Type) (types.GoType, error) {
 	switch abiType.T {
@@ -560,12 +720,7 @@ func mapSolidityToGoType(abiType abi.Type) (types.GoType, error) {
 			return mapIntType(abiType.Size), nil
 		}
 		// int256 - signed big integer
-		return types.GoType{
-			Import:   "math/big",
-			TypeName: "*big.Int",
-			IsPtr:    true,
-			IsSigned: true,
-		}, nil
+		return types.GoTypeSignedBigInt, nil
 
 	case abi.FixedBytesTy:
 		return types.GoType{
@@ -581,6 +736,7 @@ func mapSolidityToGoType(abiType abi.Type) (types.GoType, error) {
 			Import:   elemType.Import,
 			TypeName: "[]" + elemType.TypeName,
 			IsSlice:  true,
+			IsSigned: elemType.IsSigned,
 		}, nil
 
 	case abi.ArrayTy:
@@ -591,17 +747,16 @@ func mapSolidityToGoType(abiType abi.Type) (types.GoType, error) {
 		return types.GoType{
 			Import:   elemType.Import,
 			TypeName: fmt.Sprintf("[%d]%s", abiType.Size, elemType.TypeName),
+			IsSigned: elemType.IsSigned,
 		}, nil
 
 	case abi.TupleTy:
 		// This function should not be called directly for TupleTy when we need struct registration
 		// Use mapSolidityToGoTypeWithRegistry instead
-		structName := extractStructName(abiType.TupleRawName)
-		if structName == "" {
-			structName = "AnonymousTuple" // fallback for truly anonymous tuples
-		}
+		structName := tupleName(abiType, nil)
 		return types.GoType{
 			TypeName: structName,
+			IsStruct: true,
 		}, nil
 
 	default:
@@ -637,12 +792,23 @@ func mapIntType(size int) types.GoType {
 	case 64:
 		return types.GoTypeInt64
 	default:
-		return types.GoTypeBigInt
+		// Sizes without a Go counterpart, e.g. int24, keep their sign
+		return types.GoTypeSignedBigInt
 	}
 }
 
 // mapSolidityToGoTypeWithRegistry maps Solidity types to Go types and registers structs
 func mapSolidityToGoTypeWithRegistry(abiType abi.Type, registry *structRegistry) (types.GoType, error) {
+	// Deeply nested types would recurse through the mapper and the generated
+	// decoders, and are rejected before registering any of their structs
+	maxDepth := DefaultMaxDepth
+	if registry != nil && registry.maxDepth > 0 {
+		maxDepth = registry.maxDepth
+	}
+	if depth := typeDepth(abiType); depth > maxDepth {
+		return types.GoType{}, fmt.Errorf("nesting depth %d exceeds the maximum of %d", depth, maxDepth)
+	}
+
 	switch abiType.T {
 	case abi.SliceTy:
 		elemType, err := mapSolidityToGoTypeWithRegistry(*abiType.Elem, registry)
@@ -653,6 +819,7 @@ func mapSolidityToGoTypeWithRegistry(abiType abi.Type, registry *structRegistry)
 			Import:   elemType.Import,
 			TypeName: "[]" + elemType.TypeName,
 			IsSlice:  true,
+			IsSigned: elemType.IsSigned,
 		}, nil
 	case abi.ArrayTy:
 		elemType, err := mapSolidityToGoTypeWithRegistry(*abiType.Elem, registry)
@@ -662,15 +829,12 @@ func mapSolidityToGoTypeWithRegistry(abiType abi.Type, registry *structRegistry)
 		return types.GoType{
 			Import:   elemType.Import,
 			TypeName: fmt.Sprintf("[%d]%s", abiType.Size, elemType.TypeName),
+			IsSigned: elemType.IsSigned,
 		}, nil
 	case abi.TupleTy:
 		// Extract struct name and register the struct definition
-		structName := extractStructName(abiType.TupleRawName)
-		if structName == "" {
-			structName = "AnonymousTuple" // fallback for truly anonymous tuples
-		}
-		
-		
+		structName := tupleName(abiType, registry)
+
 		// Register this struct type for generation
 		if registry != nil {
 			registry.registerStruct(structName, abiType)
@@ -678,6 +842,7 @@ func mapSolidityToGoTypeWithRegistry(abiType abi.Type, registry *structRegistry)
 		
 		return types.GoType{
 			TypeName: structName,
+			IsStruct: true,
 		}, nil
 	default:
 		// For non-composite types, use the original mapping function
@@ -685,6 +850,109 @@ func mapSolidityToGoTypeWithRegistry(abiType abi.Type, registry *structRegistry)
 	}
 }
 
+// typeDepth returns how deeply arrays and tuples nest in an ABI type, e.g. 0
+// for uint256, 2 for uint256[][] and 2 for a tuple holding a uint256[]
+func typeDepth(abiType abi.Type) int {
+	switch abiType.T {
+	case abi.SliceTy, abi.ArrayTy:
+		return 1 + typeDepth(*abiType.Elem)
+	case abi.TupleTy:
+		depth := 0
+		for _, elem := range abiType.TupleElems {
+			depth = max(depth, typeDepth(*elem))
+		}
+		return 1 + depth
+	}
+	return 0
+}
+
+// isDynamicType reports whether an ABI type is encoded behind an offset
+func isDynamicType(abiType abi.Type) bool {
+	switch abiType.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy:
+		return true
+	case abi.ArrayTy:
+		return isDynamicType(*abiType.Elem)
+	case abi.TupleTy:
+		for _, elem := range abiType.TupleElems {
+			if isDynamicType(*elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize returns the bytes an ABI type occupies in the head of its enclosing
+// tuple: a single offset word for dynamic types, the full encoding otherwise
+func headSize(abiType abi.Type) int {
+	if isDynamicType(abiType) {
+		return 32
+	}
+	switch abiType.T {
+	case abi.ArrayTy:
+		return abiType.Size * headSize(*abiType.Elem)
+	case abi.TupleTy:
+		size := 0
+		for _, elem := range abiType.TupleElems {
+			size += headSize(*elem)
+		}
+		return size
+	}
+	return 32
+}
+
+// structPrefix is the internalType prefix solc uses for struct parameters
+const structPrefix = "struct "
+
+// structNames maps the tuple raw names go-ethereum derives from internalType,
+// which drop the dots ("struct Exchange.OrderDetails" -> "ExchangeOrderDetails"),
+// back to the struct name declared in the source ("OrderDetails"). With qualify,
+// structs sharing their name with a struct of another contract keep the contract
+// name ("ExchangeOrderDetails").
+func structNames(rawABI []byte, qualify bool) (map[string]string, error) {
+	var entries []rawABIEntry
+	if err := json.Unmarshal(rawABI, &entries); err != nil {
+		return nil, fmt.Errorf("parsing raw ABI: %w", err)
+	}
+
+	names := make(map[string]string)
+	var collect func(args []abi.ArgumentMarshaling)
+	collect = func(args []abi.ArgumentMarshaling) {
+		for _, arg := range args {
+			if strings.HasPrefix(arg.InternalType, structPrefix) {
+				name := strings.TrimPrefix(arg.InternalType, structPrefix)
+				if i := strings.Index(name, "["); i != -1 {
+					name = name[:i]
+				}
+				rawName := strings.ReplaceAll(name, ".", "")
+				if i := strings.LastIndex(name, "."); i != -1 {
+					name = name[i+1:]
+				}
+				names[rawName] = exportIdentifier(name)
+			}
+			collect(arg.Components)
+		}
+	}
+	for _, entry := range entries {
+		collect(entry.Inputs)
+		collect(entry.Outputs)
+	}
+
+	if qualify {
+		declared := make(map[string]int)
+		for _, name := range names {
+			declared[name]++
+		}
+		for rawName, name := range names {
+			if declared[name] > 1 && rawName != name {
+				names[rawName] = exportIdentifier(rawName)
+			}
+		}
+	}
+	return names, nil
+}
+
 // extractStructName extracts a clean struct name from the raw tuple name
 // Examples: 
 //   "struct TestStructArray.User" -> "User"
@@ -725,6 +993,90 @@ func extractStructName(rawName string) string {
 	return exportIdentifier(rawName)
 }
 
+// resolveMethodNames assigns a unique name to every method, keyed by signature.
+// Overloaded methods get a parameter based suffix; candidates that still collide
+// with another method name fall back to the selector suffixed form.
+func resolveMethodNames(parsedABI abi.ABI, methodIds map[string]string) (map[string]string, error) {
+	// go-ethereum deduplicates overloaded names (foo, foo0, ...), RawName holds the Solidity name
+	overloads := make(map[string]int) // track name collisions
+	for _, method := range parsedABI.Methods {
+		overloads[method.RawName]++
+	}
+
+	names := make(map[string]string, len(parsedABI.Methods))
+	candidates := make(map[string]int)
+	selectors := make(map[string]string, len(parsedABI.Methods)) // selector -> signature
+	for _, method := range parsedABI.Methods {
+		selector := methodIds[method.Sig]
+		if selector == "" {
+			return nil, fmt.Errorf("missing method identifier for %s", method.Sig)
+		}
+
+		// Solidity rejects colliding selectors, hand-assembled ABIs may not
+		key := strings.ToLower(strings.TrimPrefix(selector, "0x"))
+		if other, ok := selectors[key]; ok {
+			first, second := other, method.Sig
+			if second < first {
+				first, second = second, first
+			}
+			return nil, fmt.Errorf("selector collision: %s and %s share selector 0x%s", first, second, key)
+		}
+		selectors[key] = method.Sig
+
+		name := method.RawName
+		if overloads[method.RawName] > 1 {
+			name = generateOverloadName(method.RawName, method.Sig, selector)
+		}
+		names[method.Sig] = name
+		candidates[name]++
+	}
+
+	// Residual collisions: normalized parameter names can coincide (e.g. two
+	// fixed size arrays of different lengths) or match another method's name
+	for _, method := range parsedABI.Methods {
+		if overloads[method.RawName] > 1 && candidates[names[method.Sig]] > 1 {
+			names[method.Sig] = selectorOverloadName(method.RawName, methodIds[method.Sig])
+		}
+	}
+
+	return names, nil
+}
+
+// markDefaultOverloads picks, for every overloaded method, the overload with the
+// fewest parameters as the one returned by the accessor named after the Solidity
+// name, e.g. transfer(address,uint256) for TransferMethod(). Ties go to the
+// lowest signature. Names already taken by another method are left alone.
+func markDefaultOverloads(methods []types.Method) {
+	// Accessor names are title cased by the templates
+	accessor := func(name string) string {
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+	taken := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		taken[accessor(method.Name)] = true
+	}
+
+	defaults := make(map[string]int)
+	for i, method := range methods {
+		if method.Name == method.BaseName || taken[accessor(method.BaseName)] {
+			continue
+		}
+		current, ok := defaults[method.BaseName]
+		if !ok || len(method.Inputs) < len(methods[current].Inputs) ||
+			(len(method.Inputs) == len(methods[current].Inputs) && method.Signature < methods[current].Signature) {
+			defaults[method.BaseName] = i
+		}
+	}
+	for _, i := range defaults {
+		methods[i].DefaultOverload = true
+	}
+}
+
+// selectorOverloadName creates an overload name suffixed with the method selector
+func selectorOverloadName(baseName, selector string) string {
+	return fmt.Sprintf("%s__%s", baseName, strings.TrimPrefix(selector, "0x"))
+}
+
 // generateOverloadName creates a unique method name for overloaded functions
 func generateOverloadName(baseName, signature, selector string) string {
 	// Extract parameter types from signature: "foo(uint256,address)" -> ["uint256", "address"]
@@ -732,7 +1084,7 @@ func generateOverloadName(baseName, signature, selector string) string {
 	end := strings.Index(signature, ")")
 	if start == -1 || end == -1 || end <= start {
 		// Fallback to selector-based naming
-		return fmt.Sprintf("%s__%s", baseName, selector[2:])
+		return selectorOverloadName(baseName, selector)
 	}
 
 	paramStr := signature[start+1 : end]
@@ -753,7 +1105,7 @@ func generateOverloadName(baseName, signature, selector string) string {
 
 	// If still too complex, fall back to selector
 	if len(candidate) > 50 {
-		return fmt.Sprintf("%s__%s", baseName, selector[2:])
+		return selectorOverloadName(baseName, selector)
 	}
 
 	return candidate
@@ -869,6 +1221,17 @@ func exportIdentifier(name string) string {
 	return strings.ToUpper(name[:1]) + name[1:]
 }
 
+// normalizeMethodIds returns the method identifiers with their selectors
+// lowercased and without 0x prefix, as tools other than solc may write them
+func normalizeMethodIds(methodIds map[string]string) map[string]string {
+	normalized := make(map[string]string, len(methodIds))
+	for signature, selector := range methodIds {
+		selector = strings.ToLower(selector)
+		normalized[signature] = strings.TrimPrefix(selector, "0x")
+	}
+	return normalized
+}
+
 // prefixHex adds 0x prefix if not present
 func prefixHex(hex string) string {
 	if hex == "" {
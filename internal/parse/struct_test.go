@@ -3,10 +3,12 @@
 package parse
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/otherview/solgen/internal/types"
 )
 
 func TestStructArraySupport(t *testing.T) {
@@ -189,4 +191,156 @@ func TestNoStructArrayRegression(t *testing.T) {
 	if len(structs) != 0 {
 		t.Errorf("expected no structs registered, got %d", len(structs))
 	}
-}
\ No newline at end of file
+}
+
+func TestConstructorStructRegistration(t *testing.T) {
+	abiJSON := `[
+		{
+			"type": "constructor",
+			"inputs": [
+				{
+					"components": [
+						{"internalType": "uint256", "name": "fee", "type": "uint256"},
+						{"internalType": "address", "name": "treasury", "type": "address"}
+					],
+					"internalType": "struct Vault.Config",
+					"name": "cfg",
+					"type": "tuple"
+				},
+				{"internalType": "address", "name": "owner", "type": "address"}
+			],
+			"stateMutability": "nonpayable"
+		}
+	]`
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+
+	registry := newStructRegistry()
+	constructor := parseConstructorWithRegistry(parsedABI, nil, registry)
+	if constructor == nil {
+		t.Fatal("expected a constructor")
+	}
+
+	structs := registry.getAllStructs()
+	if len(structs) != 1 || structs[0].Name != "Config" {
+		t.Fatalf("expected the Config struct to be registered, got %+v", structs)
+	}
+
+	if constructor.InputStruct == nil || len(constructor.InputStruct.Fields) != 2 {
+		t.Fatalf("expected a two field constructor input struct, got %+v", constructor.InputStruct)
+	}
+	if cfg := constructor.InputStruct.Fields[0]; cfg.Name != "Cfg" || cfg.Type.TypeName != "Config" {
+		t.Errorf("expected a Cfg field of type Config, got %s %s", cfg.Name, cfg.Type.TypeName)
+	}
+}
+
+func TestStructFieldNames(t *testing.T) {
+	abiJSON := `[
+		{
+			"type": "function",
+			"name": "order",
+			"inputs": [],
+			"outputs": [
+				{
+					"components": [
+						{"internalType": "uint8", "name": "type", "type": "uint8"},
+						{"internalType": "address", "name": "func", "type": "address"},
+						{"internalType": "uint256", "name": "value", "type": "uint256"},
+						{"internalType": "uint256", "name": "_value", "type": "uint256"}
+					],
+					"internalType": "struct Book.Order",
+					"name": "",
+					"type": "tuple"
+				}
+			],
+			"stateMutability": "view"
+		}
+	]`
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+
+	registry := newStructRegistry()
+	if _, err := parseMethodsWithRegistry(parsedABI, map[string]string{"order()": "12345678"}, registry); err != nil {
+		t.Fatalf("parseMethodsWithRegistry failed: %v", err)
+	}
+
+	structs := registry.getAllStructs()
+	if len(structs) != 1 {
+		t.Fatalf("expected one struct, got %+v", structs)
+	}
+
+	expected := []struct{ name, tag string }{
+		{"Type", "type"},
+		{"Func", "func"},
+		{"Value", "value"},
+		{"Value0", "value0"},
+	}
+	fields := structs[0].Fields
+	if len(fields) != len(expected) {
+		t.Fatalf("expected %d fields, got %+v", len(expected), fields)
+	}
+	for i, want := range expected {
+		if fields[i].Name != want.name || fields[i].JSONTag != want.tag {
+			t.Errorf("field %d: expected %s with tag %q, got %s with tag %q", i, want.name, want.tag, fields[i].Name, fields[i].JSONTag)
+		}
+	}
+}
+
+func TestQualifiedStructNames(t *testing.T) {
+	abiJSON := `[
+		{"type": "function", "name": "register", "stateMutability": "nonpayable", "outputs": [], "inputs": [
+			{"name": "a", "type": "tuple", "internalType": "struct A.User", "components": [
+				{"name": "id", "type": "uint256", "internalType": "uint256"}
+			]},
+			{"name": "b", "type": "tuple", "internalType": "struct B.User", "components": [
+				{"name": "wallet", "type": "address", "internalType": "address"},
+				{"name": "name", "type": "string", "internalType": "string"}
+			]}
+		]},
+		{"type": "function", "name": "group", "stateMutability": "view", "inputs": [], "outputs": [
+			{"name": "", "type": "tuple", "internalType": "struct A.Group", "components": [
+				{"name": "size", "type": "uint256", "internalType": "uint256"}
+			]}
+		]}
+	]`
+	result := &types.CompileResult{
+		Contracts: map[string]map[string]types.ContractResult{
+			"Registry.sol": {"Registry": {
+				ABI: json.RawMessage(abiJSON),
+				EVM: types.EVMResult{MethodIdentifiers: map[string]string{
+					"register((uint256),(address,string))": "00000001",
+					"group()":                              "00000002",
+				}},
+			}},
+		},
+	}
+
+	structFields := func(options Options) map[string]int {
+		contracts, err := ResultWithOptions(result, "", options)
+		if err != nil {
+			t.Fatalf("ResultWithOptions failed: %v", err)
+		}
+		fields := make(map[string]int)
+		for _, s := range contracts[0].Structs {
+			fields[s.Name] = len(s.Fields)
+		}
+		return fields
+	}
+
+	// By default both structs are generated as User
+	if got := structFields(Options{}); len(got) != 2 || got["User"] != 1 || got["Group"] != 1 {
+		t.Errorf("expected User and Group, got %v", got)
+	}
+
+	// Only the clashing names keep their qualifier
+	got := structFields(Options{QualifyStructs: true})
+	if len(got) != 3 || got["AUser"] != 1 || got["BUser"] != 2 || got["Group"] != 1 {
+		t.Errorf("expected AUser, BUser and Group, got %v", got)
+	}
+}
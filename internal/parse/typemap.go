@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/otherview/solgen/internal/types"
+)
+
+// LoadTypeMap parses a JSON type map, keyed by Solidity type name, e.g.
+//
+//	{"address": {"type": "acct.Account", "import": "example.com/acct"}}
+//
+// Only elementary Solidity types can be overridden
+func LoadTypeMap(data []byte) (types.TypeMap, error) {
+	var typeMap types.TypeMap
+	if err := json.Unmarshal(data, &typeMap); err != nil {
+		return nil, fmt.Errorf("parsing type map: %w", err)
+	}
+
+	for solType, mapping := range typeMap {
+		abiType, err := abi.NewType(solType, "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("type map entry %q: %w", solType, err)
+		}
+		switch abiType.T {
+		case abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+			return nil, fmt.Errorf("type map entry %q: only elementary types can be mapped", solType)
+		}
+		if mapping.Type == "" {
+			return nil, fmt.Errorf("type map entry %q: missing Go type", solType)
+		}
+	}
+
+	return typeMap, nil
+}
+
+// applyTypeMap replaces the default Go type of an elementary Solidity type with
+// its type map override, if any. The default type name is kept so decoders can
+// decode the value as before and convert it to the custom type
+func applyTypeMap(goType types.GoType, abiType abi.Type, typeMap types.TypeMap) types.GoType {
+	mapping, ok := typeMap[abiType.String()]
+	if !ok {
+		return goType
+	}
+	goType.CustomType = mapping.Type
+	goType.Import = mapping.Import
+	return goType
+}
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"testing"
+)
+
+func TestLoadTypeMapRejectsInvalidEntries(t *testing.T) {
+	for name, data := range map[string]string{
+		"invalid json":    `{"address": `,
+		"unknown type":    `{"adress": {"type": "acct.Account"}}`,
+		"composite type":  `{"address[]": {"type": "acct.Accounts"}}`,
+		"missing go type": `{"address": {"import": "example.com/acct"}}`,
+	} {
+		if _, err := LoadTypeMap([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	typeMap, err := LoadTypeMap([]byte(`{"address": {"type": "acct.Account", "import": "example.com/acct"}}`))
+	if err != nil {
+		t.Fatalf("LoadTypeMap failed: %v", err)
+	}
+	if mapping := typeMap["address"]; mapping.Type != "acct.Account" || mapping.Import != "example.com/acct" {
+		t.Errorf("unexpected mapping: %+v", mapping)
+	}
+}
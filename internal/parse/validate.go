@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// maxSnippetLength bounds the ABI excerpt quoted in validation errors
+const maxSnippetLength = 200
+
+// validateABI checks that the ABI is a JSON array of entries with valid types,
+// so hand-edited ABIs fail with the offending entry rather than a generic
+// go-ethereum error
+func validateABI(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return fmt.Errorf("ABI is empty, expected a JSON array of ABI entries")
+	}
+
+	switch trimmed[0] {
+	case '[':
+	case '{':
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &object); err != nil {
+			return fmt.Errorf("ABI is not valid JSON: %w", err)
+		}
+		if _, ok := object["contracts"]; ok {
+			return fmt.Errorf("ABI is a whole compiler output (it has a \"contracts\" field), expected a JSON array of ABI entries: %s", snippet(trimmed))
+		}
+		if _, ok := object["abi"]; ok {
+			return fmt.Errorf("ABI is an object with an \"abi\" field, expected that field's JSON array of ABI entries: %s", snippet(trimmed))
+		}
+		return fmt.Errorf("ABI is a JSON object, expected a JSON array of ABI entries: %s", snippet(trimmed))
+	case '"':
+		return fmt.Errorf("ABI is a JSON string, expected a JSON array of ABI entries: %s", snippet(trimmed))
+	default:
+		return fmt.Errorf("ABI is not a JSON array of ABI entries: %s", snippet(trimmed))
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal(trimmed, &entries); err != nil {
+		return fmt.Errorf("ABI is not valid JSON: %w", err)
+	}
+	for i, raw := range entries {
+		var entry rawABIEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return fmt.Errorf("ABI entry %d is malformed: %w: %s", i, err, snippet(raw))
+		}
+		for _, params := range [][]abi.ArgumentMarshaling{entry.Inputs, entry.Outputs} {
+			for _, param := range params {
+				abiType, err := abi.NewType(param.Type, param.InternalType, param.Components)
+				if err == nil {
+					err = checkTypeSize(abiType)
+				}
+				if err != nil {
+					return fmt.Errorf("ABI entry %d (%s %s): parameter %q has invalid type %q: %w: %s",
+						i, entry.Type, entry.Name, param.Name, param.Type, err, snippet(raw))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkTypeSize rejects integer and fixed bytes sizes that go-ethereum parses
+// but Solidity does not have, e.g. uint257 or bytes33
+func checkTypeSize(t abi.Type) error {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		if t.Size < 8 || t.Size > 256 || t.Size%8 != 0 {
+			return fmt.Errorf("integer size %d is not a multiple of 8 between 8 and 256", t.Size)
+		}
+	case abi.FixedBytesTy:
+		if t.Size < 1 || t.Size > 32 {
+			return fmt.Errorf("fixed bytes size %d is not between 1 and 32", t.Size)
+		}
+	case abi.SliceTy, abi.ArrayTy:
+		return checkTypeSize(*t.Elem)
+	case abi.TupleTy:
+		for _, elem := range t.TupleElems {
+			if err := checkTypeSize(*elem); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// snippet compacts a JSON excerpt for an error message, truncating long ones
+func snippet(data []byte) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		buf.Reset()
+		buf.Write(data)
+	}
+	if buf.Len() > maxSnippetLength {
+		return buf.String()[:maxSnippetLength] + "..."
+	}
+	return buf.String()
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/otherview/solgen/internal/types"
+)
+
+func TestInvalidABIErrors(t *testing.T) {
+	for name, tt := range map[string]struct {
+		abi  string
+		want []string
+	}{
+		"bad type string": {
+			abi: `[
+				{"type": "function", "name": "balanceOf", "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"},
+				{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint257"}], "outputs": [], "stateMutability": "nonpayable"}
+			]`,
+			want: []string{
+				"contract Token.sol:Token",
+				"ABI entry 1 (function transfer)",
+				`parameter "amount" has invalid type "uint257"`,
+				`{"type":"function","name":"transfer"`,
+			},
+		},
+		"whole combined json": {
+			abi:  `{"contracts": {"Token.sol:Token": {"abi": []}}, "version": "0.8.20"}`,
+			want: []string{"contract Token.sol:Token", `whole compiler output (it has a "contracts" field)`},
+		},
+		"contract object": {
+			abi:  `{"abi": [], "bin": "0x"}`,
+			want: []string{`object with an "abi" field`},
+		},
+	} {
+		result := &types.CompileResult{
+			Contracts: map[string]map[string]types.ContractResult{
+				"Token.sol": {"Token": {ABI: json.RawMessage(tt.abi)}},
+			},
+		}
+
+		_, err := ResultWithVersion(result, "")
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: expected the error to contain %q, got: %v", name, want, err)
+			}
+		}
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	// uint256 nested in 40 dynamic arrays, and a tuple holding a uint256[][]
+	nested := "uint256" + strings.Repeat("[]", 40)
+	abiJSON := `[
+		{"type": "function", "name": "deep", "inputs": [{"name": "v", "type": "` + nested + `"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "grid", "inputs": [], "stateMutability": "view", "outputs": [
+			{"name": "", "type": "tuple", "internalType": "struct Deep.Grid", "components": [{"name": "cells", "type": "uint256[][]", "internalType": "uint256[][]"}]}
+		]}
+	]`
+	result := &types.CompileResult{
+		Contracts: map[string]map[string]types.ContractResult{
+			"Deep.sol": {"Deep": {
+				ABI: json.RawMessage(abiJSON),
+				EVM: types.EVMResult{MethodIdentifiers: map[string]string{"deep(" + nested + ")": "00000001", "grid()": "00000002"}},
+			}},
+		},
+	}
+
+	_, err := ResultWithVersion(result, "")
+	if err == nil || !strings.Contains(err.Error(), "nesting depth 40 exceeds the maximum of 32") {
+		t.Errorf("expected the default depth guard to trigger, got: %v", err)
+	}
+
+	// The tuple counts as a level, Grid nests 3 levels deep
+	result.Contracts["Deep.sol"]["Deep"] = types.ContractResult{
+		ABI: json.RawMessage(strings.Replace(abiJSON, nested, "uint256", 1)),
+		EVM: types.EVMResult{MethodIdentifiers: map[string]string{"deep(uint256)": "00000001", "grid()": "00000002"}},
+	}
+	if _, err := ResultWithOptions(result, "", Options{MaxDepth: 3}); err != nil {
+		t.Errorf("expected a depth of 3 to be allowed, got: %v", err)
+	}
+	_, err = ResultWithOptions(result, "", Options{MaxDepth: 2})
+	if err == nil || !strings.Contains(err.Error(), "nesting depth 3 exceeds the maximum of 2") {
+		t.Errorf("expected the depth guard to trigger at 2, got: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: MIT
+
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// solcVersionPattern matches solc version strings such as
+// "0.8.20+commit.a1b79de6.Linux.g++" or "v0.8.21-nightly.2023.5.1+commit.1c1b9b7e"
+var solcVersionPattern = regexp.MustCompile(`^v?(\d+\.\d+\.\d+)[^+]*(\+commit\.[0-9a-f]+)?`)
+
+// NormalizeSolcVersion reduces a solc version string to its "x.y.z+commit.hash"
+// form, dropping the pre-release, platform and compiler suffixes. Strings that
+// are not solc versions, such as "unknown", are returned unchanged.
+func NormalizeSolcVersion(version string) string {
+	match := solcVersionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return version
+	}
+	return match[1] + match[2]
+}
+
+// ErrSolcVersionOutOfRange is returned by CheckSolcVersion for versions outside the supported range
+var ErrSolcVersionOutOfRange = errors.New("solc version out of range")
+
+// CheckSolcVersion reports an error when version lies outside the inclusive
+// [minVersion, maxVersion] range. Empty bounds are not checked, and neither are
+// versions that cannot be parsed, such as "unknown".
+func CheckSolcVersion(version, minVersion, maxVersion string) error {
+	lower, hasLower := parseSolcVersion(minVersion)
+	if minVersion != "" && !hasLower {
+		return fmt.Errorf("invalid minimum solc version %q", minVersion)
+	}
+	upper, hasUpper := parseSolcVersion(maxVersion)
+	if maxVersion != "" && !hasUpper {
+		return fmt.Errorf("invalid maximum solc version %q", maxVersion)
+	}
+
+	current, ok := parseSolcVersion(version)
+	if !ok {
+		return nil
+	}
+	if hasLower && compareSolcVersions(current, lower) < 0 {
+		return fmt.Errorf("%w: solc %s is older than the minimum supported version %s", ErrSolcVersionOutOfRange, version, minVersion)
+	}
+	if hasUpper && compareSolcVersions(current, upper) > 0 {
+		return fmt.Errorf("%w: solc %s is newer than the maximum supported version %s", ErrSolcVersionOutOfRange, version, maxVersion)
+	}
+	return nil
+}
+
+// parseSolcVersion extracts the major, minor and patch numbers of a solc version
+func parseSolcVersion(version string) ([3]int, bool) {
+	var parts [3]int
+	match := solcVersionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return parts, false
+	}
+	for i, part := range strings.Split(match[1], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// compareSolcVersions returns -1, 0 or 1 when a is older than, equal to or newer than b
+func compareSolcVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -5,6 +5,7 @@ package types
 import (
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"strings"
 )
 
@@ -16,6 +17,11 @@ func (a Address) String() string {
 	return "0x" + hex.EncodeToString(a[:])
 }
 
+// MarshalText encodes the address as hex, so it appears as a string in JSON
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
 // Hash represents a 32-byte hash
 type Hash [32]byte
 
@@ -29,6 +35,11 @@ func (h Hash) Bytes() []byte {
 	return h[:]
 }
 
+// MarshalText encodes the hash as hex, so it appears as a string in JSON
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
 // HexData represents hex-encoded byte data with convenient access methods
 type HexData string
 
@@ -91,8 +102,10 @@ type CompileError struct {
 
 // ContractResult holds solc output for a single contract
 type ContractResult struct {
-	ABI json.RawMessage `json:"abi"`
-	EVM EVMResult       `json:"evm"`
+	ABI      json.RawMessage `json:"abi"`
+	EVM      EVMResult       `json:"evm"`
+	Metadata string          `json:"metadata,omitempty"` // solc metadata JSON, when selected
+	DevDoc   json.RawMessage `json:"devdoc,omitempty"`   // solc developer documentation, when selected
 }
 
 // EVMResult holds EVM-related compilation output
@@ -125,6 +138,7 @@ type Contract struct {
 	Name             string
 	SourceFile       string
 	PackageName      string
+	Dir              string // Output subdirectory set by a layout, PackageName when empty
 	SolcVersion      string
 	ABIJson          string
 	Bytecode         HexData
@@ -134,25 +148,32 @@ type Contract struct {
 	Errors           []ContractError
 	Constructor      *Constructor
 	Structs          []Struct // Standalone struct definitions
+	Enums            []Enum   // Named enum types recovered from internalType
 }
 
 // Method represents a contract method
 type Method struct {
-	Name         string
-	Signature    string
-	Selector     HexData
-	Inputs       []Parameter
-	Outputs      []Parameter
-	InputStruct  *Struct
-	OutputStruct *Struct
+	Name            string
+	BaseName        string // Solidity name, shared by all overloads
+	Signature       string
+	Selector        HexData
+	Inputs          []Parameter
+	Outputs         []Parameter
+	InputStruct     *Struct
+	OutputStruct    *Struct
+	DefaultOverload bool   // overload also returned by the accessor named after BaseName
+	Payable         bool   // accepts value, stateMutability payable
+	View            bool   // does not modify state, stateMutability view or pure
+	Deprecated      string // devdoc @custom:deprecated notice, empty if the method is not deprecated
 }
 
 // Event represents a contract event
 type Event struct {
-	Name    string
-	Topic   Hash
-	Inputs  []Parameter
-	Struct  *Struct
+	Name      string
+	Signature string // canonical signature, e.g. Transfer(address,address,uint256)
+	Topic     Hash
+	Inputs    []Parameter
+	Struct    *Struct
 }
 
 // ContractError represents a custom contract error
@@ -170,19 +191,31 @@ type Constructor struct {
 	Inputs         []Parameter
 	InputStruct    *Struct
 	LinkReferences map[string][]LinkRef
+	Payable        bool // Constructor accepts a value (stateMutability "payable")
+}
+
+// Enum represents a Solidity enum, generated as a named uint8 type
+type Enum struct {
+	Name    string
+	Members int // member count from the source AST, 0 when unknown
 }
 
 // Parameter represents a method/event/error parameter
 type Parameter struct {
 	Name    string
 	Type    GoType
-	Indexed bool // for events
+	ABIType string // canonical Solidity type, e.g. uint256 or (address,bytes)[]
+	Indexed bool   // for events
+	Hashed  bool   // indexed reference type, its topic only holds the keccak256 hash of the value
+	Dynamic bool   // ABI-encoded behind an offset
+	Size    int    // bytes the parameter occupies in the head
 }
 
 // Struct represents a generated Go struct
 type Struct struct {
-	Name   string
-	Fields []StructField
+	Name    string
+	Fields  []StructField
+	Dynamic bool // ABI-encoded behind an offset because a field is dynamic
 }
 
 // StructField represents a field in a generated struct
@@ -190,6 +223,8 @@ type StructField struct {
 	Name    string
 	Type    GoType
 	JSONTag string
+	Dynamic bool // ABI-encoded as an offset, relative to the struct start, to the field data
+	Size    int  // bytes the field occupies in the struct head
 }
 
 // GoType represents a Go type mapping
@@ -199,24 +234,98 @@ type GoType struct {
 	IsSlice    bool   // for dynamic arrays
 	IsPtr      bool   // for big.Int
 	IsSigned   bool   // for distinguishing int256 vs uint256 when both map to *big.Int
+	IsEnum     bool   // for named uint8 enum types
+	IsStruct   bool   // for tuples generated as structs
+	CustomType string // user-provided Go type from a type map; decoded as TypeName and converted
 }
 
+// TypeMapping overrides the Go type generated for a Solidity type
+type TypeMapping struct {
+	Type   string `json:"type"`             // Go type expression, e.g. "acct.Account"
+	Import string `json:"import,omitempty"` // import path providing the type, if any
+}
+
+// PackageLayout places the package of a contract in an output subdirectory
+type PackageLayout struct {
+	Dir     string `json:"dir"`               // slash-separated path relative to the output directory, e.g. "tokens/erc20"
+	Package string `json:"package,omitempty"` // Go package name, defaults to the last element of Dir
+}
+
+// Layout maps contract names to the layout of their generated package
+type Layout map[string]PackageLayout
+
+// TypeMap maps Solidity type names (e.g. "address") to Go type overrides
+type TypeMap map[string]TypeMapping
+
 // CombinedJSON represents the structure of solc --combined-json output
 type CombinedJSON struct {
 	Contracts map[string]CombinedContract `json:"contracts"`
+	Sources   map[string]CombinedSource   `json:"sources,omitempty"`
 	Version   string                      `json:"version,omitempty"`
 }
 
+// UnmarshalJSON reads the contracts either as the map keyed by "file.sol:Contract"
+// that solc writes, or as an array of contracts with their key in a name field,
+// which some tooling emits instead. A name without a source file is read as the
+// contract of the source file named after it, e.g. Token.sol:Token for Token.
+func (c *CombinedJSON) UnmarshalJSON(data []byte) error {
+	type combinedJSON CombinedJSON
+	var raw struct {
+		combinedJSON
+		Contracts json.RawMessage `json:"contracts"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = CombinedJSON(raw.combinedJSON)
+
+	contracts := strings.TrimSpace(string(raw.Contracts))
+	if !strings.HasPrefix(contracts, "[") {
+		if len(contracts) == 0 {
+			return nil
+		}
+		return json.Unmarshal(raw.Contracts, &c.Contracts)
+	}
+
+	var list []struct {
+		Name string `json:"name"`
+		CombinedContract
+	}
+	if err := json.Unmarshal(raw.Contracts, &list); err != nil {
+		return err
+	}
+	c.Contracts = make(map[string]CombinedContract, len(list))
+	for i, contract := range list {
+		key := contract.Name
+		if key == "" {
+			return fmt.Errorf("contract %d has no name", i)
+		}
+		if !strings.Contains(key, ":") {
+			key = key + ".sol:" + key
+		}
+		if _, ok := c.Contracts[key]; ok {
+			return fmt.Errorf("duplicate contract %s", key)
+		}
+		c.Contracts[key] = contract.CombinedContract
+	}
+	return nil
+}
+
 // CombinedContract represents a single contract in combined JSON output
 type CombinedContract struct {
 	ABI        json.RawMessage   `json:"abi"`
 	Bin        string            `json:"bin"`
 	BinRuntime string            `json:"bin-runtime"`
 	Hashes     map[string]string `json:"hashes,omitempty"`
-	DevDoc     interface{}       `json:"devdoc,omitempty"`
+	DevDoc     json.RawMessage   `json:"devdoc,omitempty"`
 	UserDoc    interface{}       `json:"userdoc,omitempty"`
 }
 
+// CombinedSource holds the per-source output of combined JSON, when ast was selected
+type CombinedSource struct {
+	AST interface{} `json:"AST,omitempty"`
+}
+
 
 
 // Common Go types
@@ -225,6 +334,7 @@ var (
 	GoTypeString       = GoType{TypeName: "string"}
 	GoTypeBytes        = GoType{TypeName: "[]byte"}
 	GoTypeBigInt       = GoType{Import: "math/big", TypeName: "*big.Int", IsPtr: true}
+	GoTypeSignedBigInt = GoType{Import: "math/big", TypeName: "*big.Int", IsPtr: true, IsSigned: true}
 	GoTypeAddress      = GoType{TypeName: "Address"}
 	GoTypeHash         = GoType{TypeName: "Hash"}
 	GoTypeUint8        = GoType{TypeName: "uint8"}
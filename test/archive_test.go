@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/otherview/solgen/internal/gen"
+	"github.com/otherview/solgen/internal/input"
+	"github.com/otherview/solgen/internal/parse"
+)
+
+// zipArchive builds an in-memory zip holding the given entries
+func zipArchive(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range entries {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write zip entry: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchive_Artifacts(t *testing.T) {
+	data := zipArchive(t, map[string]string{
+		// Foundry artifact, with method identifiers and inlined metadata
+		"out/Token.sol/Token.json": `{
+			"abi": [
+				{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "value", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"}
+			],
+			"bytecode": {"object": "0x608060405234801561001057600080fd5b50", "linkReferences": {}},
+			"deployedBytecode": {"object": "0x6080604052348015600f57600080fd5b50", "linkReferences": {}},
+			"methodIdentifiers": {"transfer(address,uint256)": "a9059cbb"},
+			"metadata": {"compiler": {"version": "0.8.24+commit.e11b9ed9"}, "settings": {"compilationTarget": {"src/Token.sol": "Token"}}}
+		}`,
+		// Hardhat artifact, selectors are derived from the ABI
+		"artifacts/contracts/Vault.sol/Vault.json": `{
+			"_format": "hh-sol-artifact-1",
+			"contractName": "Vault",
+			"sourceName": "contracts/Vault.sol",
+			"abi": [
+				{"type": "function", "name": "balanceOf", "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"}
+			],
+			"bytecode": "0x608060405234801561001057600080fd5b50",
+			"deployedBytecode": "0x6080604052348015600f57600080fd5b50",
+			"linkReferences": {},
+			"deployedLinkReferences": {}
+		}`,
+		// Entries that are not artifacts are skipped
+		"artifacts/contracts/Vault.sol/Vault.dbg.json": `{"_format": "hh-sol-dbg-1", "buildInfo": "../../build-info/1.json"}`,
+		"artifacts/build-info/1.json":                  `{"id": "1", "solcVersion": "0.8.24", "input": {}, "output": {}}`,
+		"out/README.md":                                "not an artifact",
+	})
+
+	result, solcVersion, err := input.Archive(data)
+	if err != nil {
+		t.Fatalf("input.Archive failed: %v", err)
+	}
+	if solcVersion != "0.8.24+commit.e11b9ed9" {
+		t.Errorf("expected the Foundry compiler version, got %q", solcVersion)
+	}
+
+	contracts, err := parse.ResultWithVersion(result, solcVersion)
+	if err != nil {
+		t.Fatalf("parse.ResultWithVersion failed: %v", err)
+	}
+	if len(contracts) != 2 {
+		t.Fatalf("expected 2 contracts, got %d", len(contracts))
+	}
+
+	sources := map[string]string{"Token": "src/Token.sol", "Vault": "contracts/Vault.sol"}
+	selectors := map[string]string{"transfer": "0xa9059cbb", "balanceOf": "0x70a08231"}
+	for _, contract := range contracts {
+		if want := sources[contract.Name]; contract.SourceFile != want {
+			t.Errorf("contract %s: expected source %s, got %s", contract.Name, want, contract.SourceFile)
+		}
+		if contract.Bytecode == "" {
+			t.Errorf("contract %s: expected the creation bytecode", contract.Name)
+		}
+		for _, method := range contract.Methods {
+			if want := selectors[method.Name]; string(method.Selector) != want {
+				t.Errorf("method %s: expected selector %s, got %s", method.Name, want, method.Selector)
+			}
+		}
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+	if err := testGeneratedCode(t, outputDir); err != nil {
+		t.Errorf("generated code compilation failed: %v", err)
+	}
+}
+
+func TestArchive_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{
+			name:    "not a zip",
+			data:    []byte(`{"contracts": {}}`),
+			wantErr: "reading archive",
+		},
+		{
+			name:    "no artifacts",
+			data:    zipArchive(t, map[string]string{"out/build-info/1.json": `{"id": "1"}`}),
+			wantErr: "no artifacts found in archive",
+		},
+		{
+			name: "duplicate artifacts",
+			data: zipArchive(t, map[string]string{
+				"a/Token.sol/Token.json": `{"abi": []}`,
+				"b/Token.sol/Token.json": `{"abi": []}`,
+			}),
+			wantErr: "duplicate artifact for Token.sol:Token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := input.Archive(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,1063 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/otherview/solgen/internal/gen"
+)
+
+// bindTestInput is a minimal contract used by the --with-bind tests
+const bindTestInput = `{
+	"contracts": {
+		"SimpleToken.sol:SimpleToken": {
+			"abi": [
+				{
+					"type": "function",
+					"name": "transfer",
+					"inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}],
+					"outputs": [{"name": "", "type": "bool"}],
+					"stateMutability": "nonpayable"
+				},
+				{
+					"type": "event",
+					"name": "Transfer",
+					"inputs": [
+						{"name": "from", "type": "address", "indexed": true},
+						{"name": "to", "type": "address", "indexed": true},
+						{"name": "value", "type": "uint256", "indexed": false}
+					]
+				}
+			],
+			"bin": "0x608060405234801561001057600080fd5b50",
+			"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+			"hashes": {"transfer(address,uint256)": "a9059cbb"}
+		}
+	}
+}`
+
+// generateBindPackage generates input with --with-bind and adds the given
+// in-package test files, keyed by package name, next to the generated code
+func generateBindPackage(t *testing.T, input string, options gen.Options, testFiles map[string]string) string {
+	t.Helper()
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	options.WithBind = true
+	if err := gen.NewGeneratorWithOptions(outputDir, options).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	writeGeneratedTests(t, outputDir, testFiles)
+	return outputDir
+}
+
+func TestWithBind_CommonRoundTrip(t *testing.T) {
+	outputDir := generateBindPackage(t, bindTestInput, gen.Options{}, map[string]string{"simpletoken": `package simpletoken
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCommonRoundTrip(t *testing.T) {
+	addr := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	if got := AddressFromCommon(addr).Common(); got != addr {
+		t.Errorf("address round trip mismatch: got %s, want %s", got, addr)
+	}
+	if got, want := AddressFromCommon(addr).String(), strings.ToLower(addr.Hex()); got != want {
+		t.Errorf("address hex mismatch: got %s, want %s", got, want)
+	}
+
+	hash := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	if got := HashFromCommon(hash).Common(); got != hash {
+		t.Errorf("hash round trip mismatch: got %s, want %s", got, hash)
+	}
+	if got, want := HashFromCommon(hash), GetTransferEvent().Topic; got != want {
+		t.Errorf("hash conversion mismatch: got %s, want %s", got, want)
+	}
+}
+`})
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+func TestWithBind_Disabled(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(bindTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "simpletoken", "simpletoken.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if strings.Contains(string(content), "go-ethereum") {
+		t.Error("generated code should not depend on go-ethereum without --with-bind")
+	}
+}
+
+func TestWithBind_PayableConstructorDeploy(t *testing.T) {
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{
+						"type": "constructor",
+						"inputs": [{"name": "owner", "type": "address"}, {"name": "cap", "type": "uint256"}],
+						"stateMutability": "payable"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50"
+			},
+			"Plain.sol:Plain": {
+				"abi": [
+					{
+						"type": "constructor",
+						"inputs": [{"name": "owner", "type": "address"}],
+						"stateMutability": "nonpayable"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50"
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	for _, contract := range contracts {
+		if contract.Constructor == nil {
+			t.Fatalf("contract %s: constructor should not be nil", contract.Name)
+		}
+		if want := contract.Name == "Vault"; contract.Constructor.Payable != want {
+			t.Errorf("contract %s: expected payable %v, got %v", contract.Name, want, contract.Constructor.Payable)
+		}
+	}
+
+	// The creation transaction is signed but not sent, so the backend is never used
+	deployTest := `package %s
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type unusedBackend struct {
+	bind.ContractBackend
+}
+
+func deployOpts() *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:     common.HexToAddress("0x1000000000000000000000000000000000000001"),
+		Nonce:    big.NewInt(0),
+		GasPrice: big.NewInt(1),
+		GasLimit: 1000000,
+		NoSend:   true,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}
+}
+
+%s
+`
+
+	outputDir := generateBindPackage(t, input, gen.Options{}, map[string]string{
+		"vault": fmt.Sprintf(deployTest, "vault", `func TestDeployPayable(t *testing.T) {
+	owner := AddressFromHex("0x2000000000000000000000000000000000000002")
+	_, tx, err := Deploy(deployOpts(), unusedBackend{}, big.NewInt(42), ConstructorInput{Owner: owner, Cap: big.NewInt(7)})
+	if err != nil {
+		t.Fatalf("Deploy failed: %v", err)
+	}
+	if tx.Value().Int64() != 42 {
+		t.Errorf("expected value 42, got %s", tx.Value())
+	}
+
+	var args []byte
+	args = append(args, common.LeftPadBytes(owner[:], 32)...)
+	args = append(args, common.LeftPadBytes([]byte{7}, 32)...)
+	if want := append(Bytecode.Bytes(), args...); !bytes.Equal(tx.Data(), want) {
+		t.Errorf("unexpected creation data %x", tx.Data())
+	}
+}`),
+		"plain": fmt.Sprintf(deployTest, "plain", `func TestDeployNonPayable(t *testing.T) {
+	owner := AddressFromHex("0x2000000000000000000000000000000000000002")
+	_, tx, err := Deploy(deployOpts(), unusedBackend{}, owner)
+	if err != nil {
+		t.Fatalf("Deploy failed: %v", err)
+	}
+	if tx.Value().Sign() != 0 {
+		t.Errorf("expected no value, got %s", tx.Value())
+	}
+	if want := append(Bytecode.Bytes(), common.LeftPadBytes(owner[:], 32)...); !bytes.Equal(tx.Data(), want) {
+		t.Errorf("unexpected creation data %x", tx.Data())
+	}
+
+	opts := deployOpts()
+	opts.Value = big.NewInt(1)
+	if _, _, err := Deploy(opts, unusedBackend{}, owner); err == nil {
+		t.Error("expected an error when sending value to a non-payable constructor")
+	}
+}`),
+	})
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+func TestWithBind_LinkedStructConstructorDeploy(t *testing.T) {
+	// The creation code returns a runtime that pushes the MathLib address,
+	// so the library is linked at byte 12 of the creation code
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{
+						"type": "constructor",
+						"inputs": [
+							{
+								"name": "config",
+								"type": "tuple",
+								"internalType": "struct Vault.Config",
+								"components": [
+									{"name": "owner", "type": "address"},
+									{"name": "label", "type": "string"}
+								]
+							},
+							{"name": "salt", "type": "uint256"}
+						],
+						"stateMutability": "nonpayable"
+					}
+				],
+				"bin": "0x601780600b6000396000f373__MathLib.sol:MathLib___________________5000",
+				"bin-runtime": "0x73__MathLib.sol:MathLib___________________5000"
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	refs := contracts[0].Constructor.LinkReferences["MathLib"]
+	if len(refs) != 1 || refs[0].Start != 12 || refs[0].Length != 20 {
+		t.Fatalf("unexpected constructor link references: %+v", contracts[0].Constructor.LinkReferences)
+	}
+
+	outputDir := generateBindPackage(t, input, gen.Options{}, map[string]string{"vault": `package vault
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type unusedBackend struct {
+	bind.ContractBackend
+}
+
+func deployOpts() *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:     common.HexToAddress("0x1000000000000000000000000000000000000001"),
+		Nonce:    big.NewInt(0),
+		GasPrice: big.NewInt(1),
+		GasLimit: 1000000,
+		NoSend:   true,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}
+}
+
+func TestDeployLinked(t *testing.T) {
+	mathLib := AddressFromHex("0x3000000000000000000000000000000000000003")
+	linked, err := LinkBytecode(map[string]Address{"MathLib": mathLib})
+	if err != nil {
+		t.Fatalf("LinkBytecode failed: %v", err)
+	}
+	if strings.Contains(linked.Hex(), "__") || !bytes.Equal(linked.Bytes()[12:32], mathLib[:]) {
+		t.Fatalf("unexpected linked bytecode %s", linked)
+	}
+
+	input := ConstructorInput{
+		Config: Config{Owner: AddressFromHex("0x2000000000000000000000000000000000000002"), Label: "vault"},
+		Salt:   big.NewInt(7),
+	}
+	_, tx, err := Deploy(deployOpts(), unusedBackend{}, map[string]Address{"MathLib": mathLib}, input)
+	if err != nil {
+		t.Fatalf("Deploy failed: %v", err)
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(ABI()))
+	if err != nil {
+		t.Fatalf("parsing ABI: %v", err)
+	}
+	args, err := parsed.Pack("", input.Config, input.Salt)
+	if err != nil {
+		t.Fatalf("packing constructor arguments: %v", err)
+	}
+	if want := append(linked.Bytes(), args...); !bytes.Equal(tx.Data(), want) {
+		t.Errorf("unexpected creation data %x", tx.Data())
+	}
+
+	if _, _, err := Deploy(deployOpts(), unusedBackend{}, nil, input); err == nil || !strings.Contains(err.Error(), "MathLib") {
+		t.Errorf("expected an error for the missing library, got %v", err)
+	}
+}
+`})
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+func TestWithBind_VerifyDeployedCode(t *testing.T) {
+	// Vault deploys 600080fd followed by the CBOR map {"x": 1}, while its
+	// DeployedBytecode carries {"x": 2} as if built from other sources.
+	// Other has a different runtime.
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [],
+				"bin": "0x600a80600b6000396000f3600080fda16178010004",
+				"bin-runtime": "0x600080fda16178020004"
+			},
+			"Other.sol:Other": {
+				"abi": [],
+				"bin": "0x600a80600b6000396000f3600180fda16178010004",
+				"bin-runtime": "0x600180fda16178010004"
+			}
+		}
+	}`
+
+	outputDir := generateBindPackage(t, input, gen.Options{}, map[string]string{"vault": `package vault
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"generated-test/other"
+)
+
+func TestVerifyDeployedCode(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	deployer := crypto.PubkeyToAddress(key.PublicKey)
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{deployer: {Balance: big.NewInt(1e18)}}, 10000000)
+	defer sim.Close()
+
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatalf("creating transactor: %v", err)
+	}
+	addr, _, err := Deploy(opts, sim)
+	if err != nil {
+		t.Fatalf("Deploy failed: %v", err)
+	}
+	sim.Commit()
+
+	ctx := context.Background()
+	ok, err := VerifyDeployedCode(ctx, sim, addr)
+	if err != nil || !ok {
+		t.Errorf("expected the deployed code to verify, got %v, %v", ok, err)
+	}
+
+	ok, err = other.VerifyDeployedCode(ctx, sim, other.Address(addr))
+	if err != nil || ok {
+		t.Errorf("expected other code not to verify, got %v, %v", ok, err)
+	}
+
+	ok, err = VerifyDeployedCode(ctx, sim, AddressFromHex("0x2000000000000000000000000000000000000002"))
+	if err != nil || ok {
+		t.Errorf("expected an address without code not to verify, got %v, %v", ok, err)
+	}
+}
+`})
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+func TestWithBind_CallMsg(t *testing.T) {
+	outputDir := generateBindPackage(t, bindTestInput, gen.Options{}, map[string]string{"simpletoken": `package simpletoken
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestCallMsg(t *testing.T) {
+	token := AddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	to := AddressFromHex("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359")
+	amount := big.NewInt(1000)
+
+	msg, err := Methods().TransferMethod().CallMsg(token, to, amount)
+	if err != nil {
+		t.Fatalf("CallMsg failed: %v", err)
+	}
+	if msg.To == nil || *msg.To != token.Common() {
+		t.Errorf("expected To %s, got %v", token, msg.To)
+	}
+
+	calldata, err := Methods().TransferMethod().Pack(to, amount)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if !bytes.Equal(msg.Data, calldata.Bytes()) {
+		t.Errorf("CallMsg data %x does not match Pack %s", msg.Data, calldata)
+	}
+
+	if _, err := Methods().TransferMethod().CallMsg(token, struct{}{}); err == nil {
+		t.Error("expected CallMsg to report packing errors")
+	}
+}
+`})
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+func TestWithBind_EncodeArgs(t *testing.T) {
+	outputDir := generateBindPackage(t, bindTestInput, gen.Options{}, map[string]string{"simpletoken": `package simpletoken
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestEncodeArgs(t *testing.T) {
+	to := AddressFromHex("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359")
+	amount := big.NewInt(1000)
+
+	typed, err := Methods().TransferMethod().Pack(to, amount)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	for _, signature := range []string{"transfer(address,uint256)", "transfer(address, uint256)"} {
+		generic, err := EncodeArgs(signature, to, amount)
+		if err != nil {
+			t.Fatalf("EncodeArgs(%q) failed: %v", signature, err)
+		}
+		if generic != typed {
+			t.Errorf("EncodeArgs(%q) = %s, expected %s", signature, generic, typed)
+		}
+	}
+
+	// A function missing from the ABI
+	data, err := EncodeArgs("pause()")
+	if err != nil || data != "0x8456cb59" {
+		t.Errorf("unexpected pause() calldata %s, %v", data, err)
+	}
+
+	for name, signature := range map[string]string{
+		"no parentheses": "transfer",
+		"unknown type":   "transfer(address,money)",
+		"tuple":          "submit((address,uint256))",
+	} {
+		if _, err := EncodeArgs(signature, to, amount); err == nil {
+			t.Errorf("%s: expected an error for %q", name, signature)
+		}
+	}
+	if _, err := EncodeArgs("transfer(address,uint256)", to); err == nil {
+		t.Error("expected an error for a missing argument")
+	}
+}
+`})
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+func TestWithBind_Call(t *testing.T) {
+	input := `{
+		"contracts": {
+			"Store.sol:Store": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "setValue",
+						"inputs": [{"name": "v", "type": "uint256"}],
+						"outputs": [],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "function",
+						"name": "value",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "uint256"}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"setValue(uint256)": "55241077", "value()": "3fa4f245"}
+			}
+		}
+	}`
+
+	outputDir := generateBindPackage(t, input, gen.Options{}, map[string]string{"store": `package store
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeCaller records the last call and answers with fixed return data
+type fakeCaller struct {
+	msg  ethereum.CallMsg
+	data []byte
+	err  error
+}
+
+func (f *fakeCaller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	f.msg = msg
+	return f.data, f.err
+}
+
+func TestCall(t *testing.T) {
+	store := AddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+	// A void method returns no data, which is not decoded
+	caller := &fakeCaller{}
+	if err := Methods().SetValueMethod().Call(context.Background(), caller, store, big.NewInt(7)); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if want := Methods().SetValueMethod().MustPack(big.NewInt(7)).Bytes(); string(caller.msg.Data) != string(want) {
+		t.Errorf("unexpected call data %x", caller.msg.Data)
+	}
+
+	caller.err = errors.New("execution reverted")
+	if err := Methods().SetValueMethod().Call(context.Background(), caller, store, big.NewInt(7)); err == nil {
+		t.Error("expected the call error to be reported")
+	}
+
+	// Methods with return values decode them
+	caller = &fakeCaller{data: common.LeftPadBytes([]byte{42}, 32)}
+	value, err := Methods().ValueMethod().Call(context.Background(), caller, store)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if value.Int64() != 42 {
+		t.Errorf("expected 42, got %s", value)
+	}
+}
+`})
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+func TestWithBind_PayableTransact(t *testing.T) {
+	// The runtime code is a single STOP, so every call succeeds and keeps its value
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "deposit",
+						"inputs": [{"name": "memo", "type": "uint256"}],
+						"outputs": [],
+						"stateMutability": "payable"
+					},
+					{
+						"type": "function",
+						"name": "withdraw",
+						"inputs": [],
+						"outputs": [],
+						"stateMutability": "nonpayable"
+					}
+				],
+				"bin": "0x600180600b6000396000f300",
+				"bin-runtime": "0x00",
+				"hashes": {"deposit(uint256)": "b6b55f25", "withdraw()": "3ccfd60b"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	for _, method := range contracts[0].Methods {
+		if want := method.Name == "deposit"; method.Payable != want {
+			t.Errorf("method %s: expected payable %v, got %v", method.Name, want, method.Payable)
+		}
+	}
+
+	outputDir := generateBindPackage(t, input, gen.Options{}, map[string]string{"vault": `package vault
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestPayableTransact(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatalf("failed to create transactor: %v", err)
+	}
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{opts.From: {Balance: big.NewInt(params.Ether)}}, 10000000)
+	defer backend.Close()
+
+	vault, _, err := Deploy(opts, backend)
+	if err != nil {
+		t.Fatalf("Deploy failed: %v", err)
+	}
+	backend.Commit()
+
+	value := big.NewInt(12345)
+	tx, err := Methods().DepositMethod().Transact(opts, backend, vault, value, big.NewInt(7))
+	if err != nil {
+		t.Fatalf("Transact failed: %v", err)
+	}
+	backend.Commit()
+
+	if opts.Value != nil {
+		t.Errorf("expected opts to be left untouched, got value %s", opts.Value)
+	}
+	if tx.Value().Cmp(value) != 0 {
+		t.Errorf("expected value %s, got %s", value, tx.Value())
+	}
+	if want := Methods().DepositMethod().MustPack(big.NewInt(7)).Bytes(); !bytes.Equal(tx.Data(), want) {
+		t.Errorf("unexpected transaction data %x", tx.Data())
+	}
+	receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil || receipt.Status != 1 {
+		t.Fatalf("expected a successful receipt, got %+v, %v", receipt, err)
+	}
+	balance, err := backend.BalanceAt(context.Background(), vault.Common(), nil)
+	if err != nil {
+		t.Fatalf("BalanceAt failed: %v", err)
+	}
+	if balance.Cmp(value) != 0 {
+		t.Errorf("expected the vault to hold %s, got %s", value, balance)
+	}
+
+	msg, err := Methods().DepositMethod().CallMsgWithValue(vault, value, big.NewInt(7))
+	if err != nil {
+		t.Fatalf("CallMsgWithValue failed: %v", err)
+	}
+	if msg.Value.Cmp(value) != 0 || !bytes.Equal(msg.Data, tx.Data()) {
+		t.Errorf("unexpected call message %+v", msg)
+	}
+}
+`})
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "vault", "vault.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if strings.Contains(string(content), "func (m *WithdrawMethod) Transact(") {
+		t.Error("expected no Transact helper for a non-payable method")
+	}
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+// bindTupleInput has methods taking a struct and an array of structs, whose
+// typed Pack shadows the variadic one the bind helpers build on
+const bindTupleInput = `{
+	"contracts": {
+		"Exchange.sol:Exchange": {
+			"abi": [
+				{
+					"type": "function",
+					"name": "createOrder",
+					"inputs": [{"name": "o", "type": "tuple", "internalType": "struct Exchange.Order", "components": [
+						{"name": "maker", "type": "address", "internalType": "address"},
+						{"name": "price", "type": "uint256", "internalType": "uint256"},
+						{"name": "note", "type": "string", "internalType": "string"}
+					]}],
+					"outputs": [],
+					"stateMutability": "payable"
+				},
+				{
+					"type": "function",
+					"name": "fillOrders",
+					"inputs": [{"name": "orders", "type": "tuple[]", "internalType": "struct Exchange.Order[]", "components": [
+						{"name": "maker", "type": "address", "internalType": "address"},
+						{"name": "price", "type": "uint256", "internalType": "uint256"},
+						{"name": "note", "type": "string", "internalType": "string"}
+					]}],
+					"outputs": [],
+					"stateMutability": "nonpayable"
+				}
+			],
+			"bin": "0x600180600b6000396000f300",
+			"bin-runtime": "0x00",
+			"hashes": {"createOrder((address,uint256,string))": "bff6bffa", "fillOrders((address,uint256,string)[])": "d6e90068"}
+		}
+	}
+}`
+
+func TestWithBind_TupleArgs(t *testing.T) {
+	outputDir := generateBindPackage(t, bindTupleInput, gen.Options{}, map[string]string{"exchange": `package exchange
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// gethOrder mirrors Order for go-ethereum's ABI packer
+type gethOrder struct {
+	Maker common.Address
+	Price *big.Int
+	Note  string
+}
+
+func TestTupleArgs(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(ABI()))
+	if err != nil {
+		t.Fatalf("parsing ABI: %v", err)
+	}
+	exchange := AddressFromHex("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359")
+	order := Order{Maker: AddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"), Price: big.NewInt(7), Note: "gtc"}
+	other := Order{Maker: exchange, Price: big.NewInt(9), Note: strings.Repeat("ioc ", 20)}
+
+	// Structs given to CallMsg, and the other helpers built on the variadic
+	// Pack, are encoded like the typed Pack and go-ethereum do
+	msg, err := Methods().CreateOrderMethod().CallMsg(exchange, order)
+	if err != nil {
+		t.Fatalf("CallMsg failed: %v", err)
+	}
+	want, err := parsed.Pack("createOrder", gethOrder{order.Maker.Common(), order.Price, order.Note})
+	if err != nil {
+		t.Fatalf("go-ethereum Pack failed: %v", err)
+	}
+	if !bytes.Equal(msg.Data, want) {
+		t.Errorf("CallMsg data %x does not match go-ethereum %x", msg.Data, want)
+	}
+	if typed := Methods().CreateOrderMethod().MustPack(order); !bytes.Equal(typed.Bytes(), want) {
+		t.Errorf("typed Pack %s does not match go-ethereum %x", typed, want)
+	}
+
+	msg, err = Methods().FillOrdersMethod().CallMsg(exchange, []Order{order, other})
+	if err != nil {
+		t.Fatalf("CallMsg failed: %v", err)
+	}
+	want, err = parsed.Pack("fillOrders", []gethOrder{{order.Maker.Common(), order.Price, order.Note}, {other.Maker.Common(), other.Price, other.Note}})
+	if err != nil {
+		t.Fatalf("go-ethereum Pack failed: %v", err)
+	}
+	if !bytes.Equal(msg.Data, want) {
+		t.Errorf("CallMsg data %x does not match go-ethereum %x", msg.Data, want)
+	}
+
+	byName, ok := Methods().ByName("createOrder")
+	if !ok {
+		t.Fatal("expected createOrder in the registry")
+	}
+	if packed, err := byName.Pack(order); err != nil || !bytes.Equal(packed.Bytes(), Methods().CreateOrderMethod().MustPack(order).Bytes()) {
+		t.Errorf("ByName Pack: got %s (%v)", packed, err)
+	}
+}
+`})
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+func TestWithBind_DecodeReceiptLogs(t *testing.T) {
+	// transfer(to, amount) emits Transfer(msg.sender, to, amount) and returns true
+	input := `{
+		"contracts": {
+			"SimpleToken.sol:SimpleToken": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "transfer",
+						"inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}],
+						"outputs": [{"name": "", "type": "bool"}],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "event",
+						"name": "Transfer",
+						"inputs": [
+							{"name": "from", "type": "address", "indexed": true},
+							{"name": "to", "type": "address", "indexed": true},
+							{"name": "value", "type": "uint256", "indexed": false}
+						]
+					},
+					{
+						"type": "event",
+						"name": "Approval",
+						"inputs": [
+							{"name": "owner", "type": "address", "indexed": true},
+							{"name": "spender", "type": "address", "indexed": true},
+							{"name": "value", "type": "uint256", "indexed": false}
+						]
+					}
+				],
+				"bin": "0x603b600c600039603b6000f360206024600037600435337fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a3600160005260206000f3",
+				"bin-runtime": "0x60206024600037600435337fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a3600160005260206000f3",
+				"hashes": {"transfer(address,uint256)": "a9059cbb"}
+			}
+		}
+	}`
+
+	outputDir := generateBindPackage(t, input, gen.Options{}, map[string]string{"simpletoken": `package simpletoken
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestDecodeReceiptLogs(t *testing.T) {
+	ctx := context.Background()
+	key, _ := crypto.GenerateKey()
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatalf("failed to create transactor: %v", err)
+	}
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{opts.From: {Balance: big.NewInt(params.Ether)}}, 10000000)
+	defer backend.Close()
+
+	token, _, err := Deploy(opts, backend)
+	if err != nil {
+		t.Fatalf("Deploy failed: %v", err)
+	}
+	backend.Commit()
+
+	to := AddressFromHex("0x2000000000000000000000000000000000000002")
+	data := Methods().TransferMethod().MustPack(to, big.NewInt(500))
+	nonce, err := backend.PendingNonceAt(ctx, opts.From)
+	if err != nil {
+		t.Fatalf("PendingNonceAt failed: %v", err)
+	}
+	gasPrice, err := backend.SuggestGasPrice(ctx)
+	if err != nil {
+		t.Fatalf("SuggestGasPrice failed: %v", err)
+	}
+	tx, err := opts.Signer(opts.From, types.NewTransaction(nonce, token.Common(), common.Big0, 100000, gasPrice, data.Bytes()))
+	if err != nil {
+		t.Fatalf("signing failed: %v", err)
+	}
+	if err := backend.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("SendTransaction failed: %v", err)
+	}
+	backend.Commit()
+
+	receipt, err := backend.TransactionReceipt(ctx, tx.Hash())
+	if err != nil || receipt.Status != 1 {
+		t.Fatalf("expected a successful receipt, got %+v, %v", receipt, err)
+	}
+
+	// A log of an unknown event is skipped
+	receipt.Logs = append(receipt.Logs, &types.Log{Topics: []common.Hash{common.HexToHash("0x01")}})
+	logs, err := DecodeReceiptLogs(receipt)
+	if err != nil {
+		t.Fatalf("DecodeReceiptLogs failed: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 decoded log, got %d", len(logs))
+	}
+	transfer, ok := logs[0].(TransferEvent)
+	if !ok {
+		t.Fatalf("expected a TransferEvent, got %T", logs[0])
+	}
+	if transfer.From != AddressFromCommon(opts.From) || transfer.To != to || transfer.Value.Int64() != 500 {
+		t.Errorf("unexpected transfer %+v", transfer)
+	}
+
+	// A malformed log of a known event is an error
+	receipt.Logs = append(receipt.Logs, &types.Log{Topics: []common.Hash{GetTransferEvent().Topic.Common()}})
+	if _, err := DecodeReceiptLogs(receipt); err == nil {
+		t.Error("expected an error for a Transfer log missing its topics")
+	}
+}
+`})
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+// nativeTypesInput takes and returns addresses, and emits them in an event
+const nativeTypesInput = `{
+	"contracts": {
+		"SimpleToken.sol:SimpleToken": {
+			"abi": [
+				{"type": "function", "name": "balanceOf", "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"},
+				{"type": "function", "name": "owner", "inputs": [], "outputs": [{"name": "", "type": "address"}], "stateMutability": "view"},
+				{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"},
+				{"type": "event", "name": "Transfer", "inputs": [
+					{"name": "from", "type": "address", "indexed": true},
+					{"name": "to", "type": "address", "indexed": true},
+					{"name": "value", "type": "uint256", "indexed": false}
+				]}
+			],
+			"bin": "0x608060405234801561001057600080fd5b50",
+			"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+			"hashes": {"balanceOf(address)": "70a08231", "owner()": "8da5cb5b", "transfer(address,uint256)": "a9059cbb"}
+		}
+	}
+}`
+
+func TestWithBind_NativeTypes(t *testing.T) {
+	outputDir := generateBindPackage(t, nativeTypesInput, gen.Options{NativeTypes: true}, map[string]string{"simpletoken": `package simpletoken
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestNativeTypes(t *testing.T) {
+	holder := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	to := common.HexToAddress("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359")
+
+	// Arguments are go-ethereum types, packed as before
+	calldata, err := Methods().TransferMethod().Pack(to, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if !bytes.Equal(calldata.Bytes()[16:36], to.Bytes()) {
+		t.Errorf("unexpected transfer calldata %s", calldata)
+	}
+	msg, err := Methods().BalanceOfMethod().CallMsg(holder, holder)
+	if err != nil {
+		t.Fatalf("CallMsg failed: %v", err)
+	}
+	if *msg.To != holder || !bytes.Equal(msg.Data[16:], holder.Bytes()) {
+		t.Errorf("unexpected balanceOf call %+v", msg)
+	}
+
+	// Return values are go-ethereum types, assigned without conversion
+	var balance *big.Int
+	balance, err = Methods().BalanceOfMethod().Decode(common.LeftPadBytes([]byte{42}, 32))
+	if err != nil || balance.Int64() != 42 {
+		t.Errorf("unexpected balance %v, %v", balance, err)
+	}
+	var owner common.Address
+	owner, err = Methods().OwnerMethod().Decode(common.LeftPadBytes(holder.Bytes(), 32))
+	if err != nil || owner != holder {
+		t.Errorf("unexpected owner %s, %v", owner, err)
+	}
+
+	// Events decode straight from go-ethereum logs
+	var topic common.Hash = GetTransferEvent().Topic
+	receipt := &types.Receipt{Logs: []*types.Log{{
+		Topics: []common.Hash{topic, common.BytesToHash(holder.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:   common.LeftPadBytes([]byte{7}, 32),
+	}}}
+	logs, err := DecodeReceiptLogs(receipt)
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("DecodeReceiptLogs failed: %v", err)
+	}
+	if transfer := logs[0].(TransferEvent); transfer.From != holder || transfer.To != to || transfer.Value.Int64() != 7 {
+		t.Errorf("unexpected transfer %+v", transfer)
+	}
+}
+`})
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "simpletoken", "simpletoken.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	for _, expected := range []string{"type Address = common.Address", "type Hash = common.Hash"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("generated code should contain %q", expected)
+		}
+	}
+	if strings.Contains(string(content), "func (a Address)") {
+		t.Error("generated code should not declare methods on the aliased Address")
+	}
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated bind code failed: %v", err)
+	}
+}
+
+func TestWithBind_NativeTypesRequiresBind(t *testing.T) {
+	binaryPath := buildSolgen(t)
+	output, err := runSolgen(binaryPath, nativeTypesInput, "--out", t.TempDir(), "--native-types")
+	if err == nil {
+		t.Fatal("expected --native-types without --with-bind to fail")
+	}
+	if !strings.Contains(output, "--native-types requires --with-bind") {
+		t.Errorf("unexpected output: %s", output)
+	}
+}
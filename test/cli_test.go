@@ -3,7 +3,12 @@
 package test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"strings"
@@ -251,4 +256,685 @@ func TestValidateOutputDir(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestCLI_Check(t *testing.T) {
+	binaryPath := buildSolgen(t)
+	outputDir := t.TempDir()
+
+	output, err := runSolgen(binaryPath, generatorTestInput, "--out", outputDir)
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+
+	// Freshly generated code is up to date
+	output, err = runSolgen(binaryPath, generatorTestInput, "--out", outputDir, "--check")
+	if err != nil {
+		t.Fatalf("expected --check to pass on fresh output: %v\nOutput: %s", err, output)
+	}
+
+	// A hand-edited file is reported and left untouched
+	tokenFile := filepath.Join(outputDir, "token", "token.go")
+	edited := []byte("// Code generated by github.com/otherview/solgen. DO NOT EDIT.\n\npackage token\n")
+	if err := os.WriteFile(tokenFile, edited, 0644); err != nil {
+		t.Fatalf("failed to edit generated file: %v", err)
+	}
+
+	output, err = runSolgen(binaryPath, generatorTestInput, "--out", outputDir, "--check")
+	if err == nil {
+		t.Fatalf("expected --check to fail for an out of date file, got:\n%s", output)
+	}
+	if !strings.Contains(output, tokenFile) {
+		t.Errorf("expected %s to be listed, got:\n%s", tokenFile, output)
+	}
+	if strings.Contains(output, filepath.Join(outputDir, "nameregistry", "nameregistry.go")) {
+		t.Errorf("up to date files should not be listed, got:\n%s", output)
+	}
+
+	content, err := os.ReadFile(tokenFile)
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if string(content) != string(edited) {
+		t.Error("--check should not rewrite out of date files")
+	}
+
+	// A missing output directory is stale and is not created
+	missingDir := filepath.Join(t.TempDir(), "missing")
+	output, err = runSolgen(binaryPath, generatorTestInput, "--out", missingDir, "--check")
+	if err == nil {
+		t.Fatalf("expected --check to fail for a missing output directory, got:\n%s", output)
+	}
+	if !strings.Contains(output, filepath.Join(missingDir, "token", "token.go")) {
+		t.Errorf("expected the files of the missing directory to be listed, got:\n%s", output)
+	}
+	if _, err := os.Stat(missingDir); !os.IsNotExist(err) {
+		t.Errorf("--check should not create %s", missingDir)
+	}
+}
+
+func TestCLI_VerboseRenamedMethods(t *testing.T) {
+	input := `{
+		"contracts": {
+			"Token.sol:Token": {
+				"abi": [
+					{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}], "outputs": [], "stateMutability": "nonpayable"},
+					{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [], "stateMutability": "nonpayable"}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"transfer(address)": "1a695230", "transfer(address,uint256)": "a9059cbb"}
+			}
+		}
+	}`
+
+	binaryPath := buildSolgen(t)
+	output, err := runSolgen(binaryPath, input, "--out", t.TempDir(), "--verbose")
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+
+	// Only the default overload, with the fewest parameters, keeps TransferMethod()
+	for _, want := range []string{
+		"Note: Token.transfer(address) is generated as Transfer_AddressMethod() because transfer is overloaded, TransferMethod() returns this default overload\n",
+		"Note: Token.transfer(address,uint256) is generated as Transfer_Address_Uint256Method() because transfer is overloaded\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestCLI_Raw(t *testing.T) {
+	input := `{
+		"contracts": {
+			"Registry.sol:Registry": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "entry",
+						"inputs": [],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"internalType": "struct Registry.Entry",
+							"components": [
+								{"name": "owner", "type": "address", "internalType": "address"},
+								{"name": "id", "type": "uint256", "internalType": "uint256"}
+							]
+						}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"entry()": "67f239dd"}
+			}
+		}
+	}`
+
+	binaryPath := buildSolgen(t)
+
+	// An invalid Go type from the type map makes formatting fail
+	typeMapFile := filepath.Join(t.TempDir(), "typemap.json")
+	if err := os.WriteFile(typeMapFile, []byte(`{"address": {"type": "Account{"}}`), 0644); err != nil {
+		t.Fatalf("failed to write type map: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	output, err := runSolgen(binaryPath, input, "--out", outputDir, "--type-map", typeMapFile, "--raw")
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "Warning") {
+		t.Errorf("expected --raw, like --no-format, to skip formatting silently, got:\n%s", output)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "registry", "registry.go"))
+	if err != nil {
+		t.Fatalf("expected --raw to write the generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "Owner Account{") {
+		t.Errorf("expected the verbatim template output, got:\n%s", content)
+	}
+	if _, err := format.Source(content); err == nil {
+		t.Error("expected the raw output to be invalid Go for this type map")
+	}
+
+	// Valid code is left unformatted too
+	outputDir = t.TempDir()
+	output, err = runSolgen(binaryPath, input, "--out", outputDir, "--raw")
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+	content, err = os.ReadFile(filepath.Join(outputDir, "registry", "registry.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	formatted, err := format.Source(content)
+	if err != nil {
+		t.Fatalf("raw output should still be valid Go: %v", err)
+	}
+	if string(formatted) == string(content) {
+		t.Error("expected --raw to skip formatting")
+	}
+
+	// The flag is for debugging and stays out of the help output
+	output, _ = runSolgen(binaryPath, "", "--help")
+	if strings.Contains(output, "--raw") {
+		t.Errorf("expected --raw to be hidden, got:\n%s", output)
+	}
+}
+
+func TestCLI_NoFormat(t *testing.T) {
+	binaryPath := buildSolgen(t)
+	outputDir := t.TempDir()
+
+	output, err := runSolgen(binaryPath, generatorTestInput, "--out", outputDir, "--no-format")
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "Warning") {
+		t.Errorf("expected --no-format to skip formatting silently, got:\n%s", output)
+	}
+
+	for _, pkg := range []string{"token", "nameregistry"} {
+		filePath := filepath.Join(outputDir, pkg, pkg+".go")
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read generated file: %v", err)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), filePath, content, parser.ParseComments); err != nil {
+			t.Errorf("unformatted output of %s should still parse: %v", pkg, err)
+		}
+		formatted, err := format.Source(content)
+		if err != nil {
+			t.Fatalf("failed to format %s: %v", pkg, err)
+		}
+		if string(formatted) == string(content) {
+			t.Errorf("expected --no-format to leave %s unformatted", pkg)
+		}
+	}
+
+	if err := testGeneratedCode(t, outputDir); err != nil {
+		t.Errorf("unformatted code failed to compile: %v", err)
+	}
+}
+
+func TestCLI_HeaderFile(t *testing.T) {
+	binaryPath := buildSolgen(t)
+
+	headerFile := filepath.Join(t.TempDir(), "header.txt")
+	header := "// SPDX-License-Identifier: Apache-2.0\nCopyright 2026 Example Corp.\n\nLicensed under the Apache License, Version 2.0.\n"
+	if err := os.WriteFile(headerFile, []byte(header), 0644); err != nil {
+		t.Fatalf("failed to write header file: %v", err)
+	}
+
+	wantTop := strings.Join([]string{
+		"// Code generated by github.com/otherview/solgen. DO NOT EDIT.",
+		"// SPDX-License-Identifier: Apache-2.0",
+		"// Copyright 2026 Example Corp.",
+		"//",
+		"// Licensed under the Apache License, Version 2.0.",
+	}, "\n") + "\n"
+
+	for _, tt := range []struct {
+		name  string
+		args  []string
+		files []string
+	}{
+		{"per contract", nil, []string{filepath.Join("token", "token.go"), filepath.Join("nameregistry", "nameregistry.go")}},
+		{"single file", []string{"--single-file"}, []string{filepath.Join("bindings", "bindings.go")}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := t.TempDir()
+			args := append([]string{"--out", outputDir, "--header-file", headerFile}, tt.args...)
+			output, err := runSolgen(binaryPath, generatorTestInput, args...)
+			if err != nil {
+				t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+			}
+
+			for _, file := range tt.files {
+				content, err := os.ReadFile(filepath.Join(outputDir, file))
+				if err != nil {
+					t.Fatalf("failed to read generated file: %v", err)
+				}
+				if !strings.HasPrefix(string(content), wantTop) {
+					t.Errorf("%s: expected the custom header at the top, got:\n%s", file, content[:min(len(content), 300)])
+				}
+				if strings.Contains(string(content), "SPDX-License-Identifier: MIT") {
+					t.Errorf("%s: expected the custom header to replace the default one", file)
+				}
+			}
+			if err := testGeneratedCode(t, outputDir); err != nil {
+				t.Errorf("generated code failed to compile: %v", err)
+			}
+		})
+	}
+}
+
+func TestCLI_Layout(t *testing.T) {
+	binaryPath := buildSolgen(t)
+
+	layoutFile := filepath.Join(t.TempDir(), "layout.json")
+	if err := os.WriteFile(layoutFile, []byte(`{"Token": {"dir": "tokens/erc20", "package": "token"}, "Name_Registry": {"dir": "registry"}}`), 0644); err != nil {
+		t.Fatalf("failed to write layout: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	output, err := runSolgen(binaryPath, generatorTestInput, "--out", outputDir, "--layout", layoutFile, "--embed-abi")
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, tt := range []struct{ dir, file, pkg string }{
+		{"tokens/erc20", "token.go", "token"},
+		{"registry", "registry.go", "registry"},
+	} {
+		dir := filepath.Join(outputDir, filepath.FromSlash(tt.dir))
+		content, err := os.ReadFile(filepath.Join(dir, tt.file))
+		if err != nil {
+			t.Fatalf("expected the package in %s: %v", tt.dir, err)
+		}
+		if !strings.Contains(string(content), "\npackage "+tt.pkg+"\n") {
+			t.Errorf("%s: expected package %s", tt.dir, tt.pkg)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "abi.json")); err != nil {
+			t.Errorf("%s: expected the ABI next to the code: %v", tt.dir, err)
+		}
+	}
+	for _, dir := range []string{"token", "nameregistry"} {
+		if _, err := os.Stat(filepath.Join(outputDir, dir)); !os.IsNotExist(err) {
+			t.Errorf("expected no default %s directory", dir)
+		}
+	}
+	if err := testGeneratedCode(t, outputDir); err != nil {
+		t.Errorf("generated code failed to compile: %v", err)
+	}
+
+	for name, layout := range map[string]string{
+		"unknown contract":   `{"Vault": {"dir": "vault"}}`,
+		"default collision":  `{"Token": {"dir": "nameregistry"}}`,
+		"layout collision":   `{"Token": {"dir": "shared"}, "Name_Registry": {"dir": "shared"}}`,
+		"escaping directory": `{"Token": {"dir": "../token"}}`,
+	} {
+		if err := os.WriteFile(layoutFile, []byte(layout), 0644); err != nil {
+			t.Fatalf("failed to write layout: %v", err)
+		}
+		if output, err := runSolgen(binaryPath, generatorTestInput, "--out", t.TempDir(), "--layout", layoutFile); err == nil {
+			t.Errorf("%s: expected solgen to fail, got:\n%s", name, output)
+		}
+	}
+
+	if output, err := runSolgen(binaryPath, generatorTestInput, "--out", t.TempDir(), "--layout", layoutFile, "--single-file"); err == nil {
+		t.Errorf("expected --layout to be rejected with --single-file, got:\n%s", output)
+	}
+}
+
+func TestCLI_MultipleOutputs(t *testing.T) {
+	binaryPath := buildSolgen(t)
+
+	coreDir := filepath.Join(t.TempDir(), "pkg", "core")
+	peripheryDir := filepath.Join(t.TempDir(), "pkg", "periphery")
+	output, err := runSolgen(binaryPath, generatorTestInput,
+		"--out", coreDir, "--contracts", "Token",
+		"--out", peripheryDir, "--contracts", "Name_Registry")
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, tt := range []struct{ dir, want, unwanted string }{
+		{coreDir, "token", "nameregistry"},
+		{peripheryDir, "nameregistry", "token"},
+	} {
+		if _, err := os.Stat(filepath.Join(tt.dir, tt.want, tt.want+".go")); err != nil {
+			t.Errorf("expected %s in %s: %v", tt.want, tt.dir, err)
+		}
+		if _, err := os.Stat(filepath.Join(tt.dir, tt.unwanted)); !os.IsNotExist(err) {
+			t.Errorf("expected no %s in %s", tt.unwanted, tt.dir)
+		}
+		if err := testGeneratedCode(t, tt.dir); err != nil {
+			t.Errorf("generated code in %s failed to compile: %v", tt.dir, err)
+		}
+	}
+
+	// Both directories are checked in one run
+	output, err = runSolgen(binaryPath, generatorTestInput, "--check",
+		"--out", coreDir, "--contracts", "Token",
+		"--out", peripheryDir, "--contracts", "Name_Registry")
+	if err != nil {
+		t.Errorf("expected --check to pass on fresh output: %v\nOutput: %s", err, output)
+	}
+
+	for name, args := range map[string][]string{
+		"missing contracts": {"--out", t.TempDir(), "--out", t.TempDir(), "--contracts", "Token"},
+		"unknown contract":  {"--out", t.TempDir(), "--contracts", "Vault"},
+		"no contract":       {"--out", t.TempDir(), "--contracts", " , "},
+	} {
+		if output, err := runSolgen(binaryPath, generatorTestInput, args...); err == nil {
+			t.Errorf("%s: expected solgen to fail, got:\n%s", name, output)
+		}
+	}
+}
+
+func TestCLI_Verify(t *testing.T) {
+	binaryPath := buildSolgen(t)
+
+	// Outside of a module, a temporary go.mod is written for the build only
+	outputDir := t.TempDir()
+	output, err := runSolgen(binaryPath, bindTestInput, "--out", outputDir, "--verify")
+	if err != nil {
+		t.Fatalf("expected --verify to pass for SimpleToken: %v\nOutput: %s", err, output)
+	}
+	for _, name := range []string{"go.mod", "go.sum"} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected the temporary %s to be removed", name)
+		}
+	}
+
+	// The temporary module resolves the import of the runtime package
+	output, err = runSolgen(binaryPath, generatorTestInput, "--out", t.TempDir(), "--runtime-package", "example.com/app/abirt", "--verify")
+	if err != nil {
+		t.Errorf("expected --verify to pass with --runtime-package: %v\nOutput: %s", err, output)
+	}
+
+	// Packages that do not build fail the run
+	brokenDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(brokenDir, "broken"), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(brokenDir, "broken", "broken.go"), []byte("package broken\n\nvar x int = \"x\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write broken package: %v", err)
+	}
+	output, err = runSolgen(binaryPath, bindTestInput, "--out", brokenDir, "--verify")
+	if err == nil || !strings.Contains(output, "does not build") || !strings.Contains(output, "broken.go") {
+		t.Errorf("expected --verify to report the broken package, got %v\nOutput: %s", err, output)
+	}
+
+	if output, err := runSolgen(binaryPath, bindTestInput, "--out", t.TempDir(), "--verify", "--check"); err == nil {
+		t.Errorf("expected --verify to be rejected with --check, got:\n%s", output)
+	}
+}
+
+func TestCLI_DumpModel(t *testing.T) {
+	binaryPath := buildSolgen(t)
+	modelFile := filepath.Join(t.TempDir(), "model.json")
+
+	output, err := runSolgen(binaryPath, bindTestInput, "--out", t.TempDir(), "--dump-model", modelFile)
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+	data, err := os.ReadFile(modelFile)
+	if err != nil {
+		t.Fatalf("failed to read model: %v", err)
+	}
+
+	var model []struct {
+		Name    string
+		Methods []struct {
+			Signature string
+			Selector  string
+			Inputs    []struct {
+				Name    string
+				ABIType string
+				Type    struct{ TypeName string }
+			}
+		}
+		Events []struct {
+			Name  string
+			Topic string
+		}
+	}
+	if err := json.Unmarshal(data, &model); err != nil {
+		t.Fatalf("failed to parse model: %v\n%s", err, data)
+	}
+	if len(model) != 1 || len(model[0].Methods) != 1 || len(model[0].Events) != 1 {
+		t.Fatalf("unexpected model:\n%s", data)
+	}
+
+	transfer := model[0].Methods[0]
+	if transfer.Signature != "transfer(address,uint256)" || transfer.Selector != "0xa9059cbb" {
+		t.Errorf("unexpected transfer method: %+v", transfer)
+	}
+	inputs := []struct{ name, abiType, goType string }{
+		{"to", "address", "Address"},
+		{"amount", "uint256", "*big.Int"},
+	}
+	if len(transfer.Inputs) != len(inputs) {
+		t.Fatalf("expected %d transfer inputs, got %+v", len(inputs), transfer.Inputs)
+	}
+	for i, want := range inputs {
+		got := transfer.Inputs[i]
+		if got.Name != want.name || got.ABIType != want.abiType || got.Type.TypeName != want.goType {
+			t.Errorf("transfer input %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	if event := model[0].Events[0]; event.Name != "Transfer" || event.Topic != "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Errorf("unexpected Transfer event: %+v", event)
+	}
+
+	// The dump is stable, so it can be diffed between runs
+	output, err = runSolgen(binaryPath, bindTestInput, "--out", t.TempDir(), "--dump-model", modelFile)
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+	again, err := os.ReadFile(modelFile)
+	if err != nil {
+		t.Fatalf("failed to read model: %v", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Error("expected the same model from the same input")
+	}
+}
+
+func TestCLI_List(t *testing.T) {
+	input := `{
+		"contracts": {
+			"SimpleToken.sol:SimpleToken": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "transfer",
+						"inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}],
+						"outputs": [{"name": "", "type": "bool"}],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "function",
+						"name": "balanceOf",
+						"inputs": [{"name": "account", "type": "address"}],
+						"outputs": [{"name": "", "type": "uint256"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "event",
+						"name": "Transfer",
+						"inputs": [
+							{"name": "from", "type": "address", "indexed": true},
+							{"name": "to", "type": "address", "indexed": true},
+							{"name": "value", "type": "uint256", "indexed": false}
+						]
+					},
+					{
+						"type": "error",
+						"name": "InsufficientBalance",
+						"inputs": [{"name": "available", "type": "uint256"}, {"name": "required", "type": "uint256"}]
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"transfer(address,uint256)": "a9059cbb", "balanceOf(address)": "70a08231"}
+			},
+			"IToken.sol:IToken": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "totalSupply",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "uint256"}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "",
+				"bin-runtime": "",
+				"hashes": {"totalSupply()": "18160ddd"}
+			}
+		}
+	}`
+
+	binaryPath := buildSolgen(t)
+	output, err := runSolgen(binaryPath, input, "list")
+	if err != nil {
+		t.Fatalf("solgen list failed: %v\nOutput: %s", err, output)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and two contracts, got:\n%s", output)
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "CONTRACT SOURCE METHODS EVENTS ERRORS BYTECODE" {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	// Contracts are listed in source file order
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "IToken IToken.sol 1 0 0 no" {
+		t.Errorf("unexpected IToken summary %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "SimpleToken SimpleToken.sol 2 1 1 yes" {
+		t.Errorf("unexpected SimpleToken summary %q", lines[2])
+	}
+
+	// Like generation, listing requires input on stdin
+	if _, err := runSolgen(binaryPath, "", "list"); err == nil {
+		t.Error("expected list to fail without input")
+	}
+}
+
+func TestCLI_Diff(t *testing.T) {
+	oldInput := `{
+		"contracts": {
+			"Token.sol:Token": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "transfer",
+						"inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}],
+						"outputs": [{"name": "", "type": "bool"}],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "function",
+						"name": "balanceOf",
+						"inputs": [{"name": "account", "type": "address"}],
+						"outputs": [{"name": "", "type": "uint256"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "event",
+						"name": "Transfer",
+						"inputs": [
+							{"name": "from", "type": "address", "indexed": true},
+							{"name": "to", "type": "address", "indexed": true},
+							{"name": "value", "type": "uint256", "indexed": false}
+						]
+					},
+					{"type": "error", "name": "Paused", "inputs": []}
+				],
+				"hashes": {"transfer(address,uint256)": "a9059cbb", "balanceOf(address)": "70a08231"}
+			}
+		}
+	}`
+	newInput := `{
+		"contracts": {
+			"Token.sol:Token": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "transfer",
+						"inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint128"}],
+						"outputs": [{"name": "", "type": "bool"}],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "function",
+						"name": "balanceOf",
+						"inputs": [{"name": "account", "type": "address"}],
+						"outputs": [{"name": "", "type": "uint256"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "mint",
+						"inputs": [{"name": "to", "type": "address"}],
+						"outputs": [],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "event",
+						"name": "Transfer",
+						"inputs": [
+							{"name": "from", "type": "address", "indexed": true},
+							{"name": "to", "type": "address", "indexed": true},
+							{"name": "value", "type": "uint256", "indexed": false}
+						]
+					}
+				],
+				"hashes": {"transfer(address,uint128)": "fbb001d6", "balanceOf(address)": "70a08231", "mint(address)": "6a627842"}
+			}
+		}
+	}`
+
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "old.json")
+	newFile := filepath.Join(dir, "new.json")
+	if err := os.WriteFile(oldFile, []byte(oldInput), 0644); err != nil {
+		t.Fatalf("failed to write old input: %v", err)
+	}
+	if err := os.WriteFile(newFile, []byte(newInput), 0644); err != nil {
+		t.Fatalf("failed to write new input: %v", err)
+	}
+
+	binaryPath := buildSolgen(t)
+
+	output, err := runSolgen(binaryPath, "", "diff", oldFile, newFile)
+	if err == nil {
+		t.Fatalf("expected diff to fail on breaking changes, got:\n%s", output)
+	}
+	for _, expected := range []string{
+		"~ Token: method transfer(address,uint256) returns (bool) [0xa9059cbb] -> transfer(address,uint128) returns (bool) [0xfbb001d6]",
+		"+ Token: method mint(address) [0x6a627842]",
+		"- Token: error Paused() [0x9e87fac8]",
+		"2 breaking ABI changes",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in output, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "balanceOf") || strings.Contains(output, "Transfer(") {
+		t.Errorf("unchanged entries should not be reported, got:\n%s", output)
+	}
+
+	// Identical inputs have no changes
+	output, err = runSolgen(binaryPath, "", "diff", oldFile, oldFile)
+	if err != nil {
+		t.Fatalf("expected no changes between identical inputs: %v\nOutput: %s", err, output)
+	}
+	if strings.TrimSpace(output) != "" {
+		t.Errorf("expected no output for identical inputs, got:\n%s", output)
+	}
+
+	// Additions alone are not breaking
+	extendedInput := strings.Replace(oldInput, `{"type": "error", "name": "Paused", "inputs": []}`,
+		`{"type": "error", "name": "Paused", "inputs": []}, {"type": "error", "name": "Unauthorized", "inputs": []}`, 1)
+	extendedFile := filepath.Join(dir, "extended.json")
+	if err := os.WriteFile(extendedFile, []byte(extendedInput), 0644); err != nil {
+		t.Fatalf("failed to write extended input: %v", err)
+	}
+	output, err = runSolgen(binaryPath, "", "diff", oldFile, extendedFile)
+	if err != nil {
+		t.Fatalf("expected additions not to fail: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "+ Token: error Unauthorized()") {
+		t.Errorf("expected the added error to be reported, got:\n%s", output)
+	}
+}
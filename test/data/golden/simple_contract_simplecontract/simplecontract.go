@@ -8,12 +8,52 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"reflect"
 	"strings"
 )
 
 // Contract metadata
-var _abiJSON = "[\n\t\t\t\t\t{\n\t\t\t\t\t\t\"type\": \"constructor\",\n\t\t\t\t\t\t\"inputs\": [{\"name\": \"initialValue\", \"type\": \"uint256\"}]\n\t\t\t\t\t},\n\t\t\t\t\t{\n\t\t\t\t\t\t\"type\": \"function\", \n\t\t\t\t\t\t\"name\": \"getValue\",\n\t\t\t\t\t\t\"inputs\": [],\n\t\t\t\t\t\t\"outputs\": [{\"name\": \"\", \"type\": \"uint256\"}],\n\t\t\t\t\t\t\"stateMutability\": \"view\"\n\t\t\t\t\t},\n\t\t\t\t\t{\n\t\t\t\t\t\t\"type\": \"function\",\n\t\t\t\t\t\t\"name\": \"setValue\", \n\t\t\t\t\t\t\"inputs\": [{\"name\": \"newValue\", \"type\": \"uint256\"}],\n\t\t\t\t\t\t\"outputs\": [],\n\t\t\t\t\t\t\"stateMutability\": \"nonpayable\"\n\t\t\t\t\t},\n\t\t\t\t\t{\n\t\t\t\t\t\t\"type\": \"event\",\n\t\t\t\t\t\t\"name\": \"ValueChanged\",\n\t\t\t\t\t\t\"inputs\": [\n\t\t\t\t\t\t\t{\"name\": \"oldValue\", \"type\": \"uint256\", \"indexed\": false},\n\t\t\t\t\t\t\t{\"name\": \"newValue\", \"type\": \"uint256\", \"indexed\": false}\n\t\t\t\t\t\t]\n\t\t\t\t\t},\n\t\t\t\t\t{\n\t\t\t\t\t\t\"type\": \"error\",\n\t\t\t\t\t\t\"name\": \"InvalidValue\", \n\t\t\t\t\t\t\"inputs\": [{\"name\": \"provided\", \"type\": \"uint256\"}]\n\t\t\t\t\t}\n\t\t\t\t]"
+var _abiJSON = `[
+					{
+						"type": "constructor",
+						"inputs": [{"name": "initialValue", "type": "uint256"}]
+					},
+					{
+						"type": "function",
+						"name": "getValue",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "uint256"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "setValue",
+						"inputs": [{"name": "newValue", "type": "uint256"}],
+						"outputs": [],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "event",
+						"name": "ValueChanged",
+						"inputs": [
+							{"name": "oldValue", "type": "uint256", "indexed": false},
+							{"name": "newValue", "type": "uint256", "indexed": false}
+						]
+					},
+					{
+						"type": "error",
+						"name": "InvalidValue",
+						"inputs": [{"name": "provided", "type": "uint256"}]
+					}
+				]`
+
+// ContractName is the name of the contract in its Solidity source
+const ContractName = "SimpleContract"
+
+// SourceFile is the Solidity source file declaring the contract
+const SourceFile = "SimpleContract.sol"
 
 // ABI returns the contract ABI as a JSON string
 func ABI() string {
@@ -26,6 +66,38 @@ var Bytecode = HexData("0x608060405234801561001057600080fd5b5060405161012c380380
 // DeployedBytecode contains the contract runtime bytecode
 var DeployedBytecode = HexData("0x6080604052348015600f57600080fd5b506004361060325760003560e01c806320965255146037578063552410771460005b600080fd5b60005460405190815260200160405180910390f35b6000819055565b600080fd5b6000819050919050565b605c81604f565b8114606657600080fd5b50565b600081359050607a81605556565b92915050565b600060208284031215609357609260004a565b5b6000609f84828501606d565b9150509291505056fea2646970667358221220")
 
+// DeployedBytecodeStripped returns DeployedBytecode without its trailing
+// metadata, to compare it with on-chain code built from different metadata
+func DeployedBytecodeStripped() HexData {
+	return DeployedBytecode.StripMetadata()
+}
+
+// ValidateConstructorArgs checks constructor arguments before deployment: the
+// argument count, that no argument is nil, that unsigned integers are not
+// negative and that addresses are given as Address
+func ValidateConstructorArgs(args ...any) error {
+	if len(args) != 1 {
+		return fmt.Errorf("constructor expects 1 arguments, got %d", len(args))
+	}
+	if args[0] == nil {
+		return errors.New("constructor argument 0 (uint256 initialValue) is nil")
+	}
+	switch v := args[0].(type) {
+	case *big.Int:
+		if v == nil {
+			return errors.New("constructor argument 0 (uint256 initialValue) is nil")
+		}
+		if v.Sign() < 0 {
+			return fmt.Errorf("constructor argument 0 (uint256 initialValue) is negative: %s", v)
+		}
+	case int64:
+		if v < 0 {
+			return fmt.Errorf("constructor argument 0 (uint256 initialValue) is negative: %d", v)
+		}
+	}
+	return nil
+}
+
 // Address represents a 20-byte Ethereum address
 type Address [20]byte
 
@@ -34,6 +106,17 @@ func (a Address) String() string {
 	return "0x" + hex.EncodeToString(a[:])
 }
 
+// MarshalText encodes the address as 0x-prefixed hex, so it is a string in JSON,
+// also as a map key
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an address from hex, with or without the 0x prefix
+func (a *Address) UnmarshalText(text []byte) error {
+	return unmarshalHexText(a[:], text, "address")
+}
+
 // Hash represents a 32-byte hash
 type Hash [32]byte
 
@@ -47,6 +130,31 @@ func (h Hash) Bytes() []byte {
 	return h[:]
 }
 
+// MarshalText encodes the hash as 0x-prefixed hex, so it is a string in JSON,
+// also as a map key
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText decodes a hash from hex, with or without the 0x prefix
+func (h *Hash) UnmarshalText(text []byte) error {
+	return unmarshalHexText(h[:], text, "hash")
+}
+
+// unmarshalHexText decodes hex text of exactly len(dst) bytes into dst
+func unmarshalHexText(dst, text []byte, kind string) error {
+	s := strings.TrimPrefix(string(text), "0x")
+	if len(s) != 2*len(dst) {
+		return fmt.Errorf("invalid %s hex length %d, expected %d", kind, len(s), 2*len(dst))
+	}
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("invalid %s hex: %w", kind, err)
+	}
+	copy(dst, decoded)
+	return nil
+}
+
 // AddressFromHex creates an Address from a hex string
 func AddressFromHex(s string) Address {
 	var addr Address
@@ -105,6 +213,68 @@ func (h HexData) Bytes() []byte {
 	return decoded
 }
 
+// StripMetadata returns the bytecode without the CBOR metadata solc appends to
+// it, whose length is given by the last 2 bytes. The metadata holds a hash of
+// the sources and settings, so it differs between otherwise identical builds.
+// Bytecode that does not end with a CBOR map is returned unchanged.
+func (h HexData) StripMetadata() HexData {
+	hexStr := strings.TrimPrefix(string(h), "0x")
+	if len(hexStr) < 4 {
+		return h
+	}
+	suffix, err := hex.DecodeString(hexStr[len(hexStr)-4:])
+	if err != nil {
+		return h
+	}
+	length := int(suffix[0])<<8 | int(suffix[1])
+	size := (length + 2) * 2
+	if size > len(hexStr) {
+		return h
+	}
+	start := len(hexStr) - size
+	// CBOR maps have major type 5 in the top 3 bits of their first byte
+	header, err := hex.DecodeString(hexStr[start : start+2])
+	if err != nil || header[0]>>5 != 5 {
+		return h
+	}
+	return HexData("0x" + hexStr[:start])
+}
+
+// Largest values of the unsigned Solidity types that fit Go integers, e.g. type(uint64).max
+const (
+	MaxUint8  uint8  = 1<<8 - 1
+	MaxUint16 uint16 = 1<<16 - 1
+	MaxUint32 uint32 = 1<<32 - 1
+	MaxUint64 uint64 = 1<<64 - 1
+)
+
+// MaxUint128 returns type(uint128).max as a new *big.Int
+func MaxUint128() *big.Int {
+	return maxUint(128)
+}
+
+// MaxUint256 returns type(uint256).max, the usual unlimited ERC-20 allowance.
+// Every call returns a new *big.Int, which the caller may modify.
+func MaxUint256() *big.Int {
+	return maxUint(256)
+}
+
+// MaxInt256 returns type(int256).max as a new *big.Int
+func MaxInt256() *big.Int {
+	return maxUint(255)
+}
+
+// MinInt256 returns type(int256).min as a new *big.Int
+func MinInt256() *big.Int {
+	return new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))
+}
+
+// maxUint returns 2^bits - 1
+func maxUint(bits uint) *big.Int {
+	n := new(big.Int).Lsh(big.NewInt(1), bits)
+	return n.Sub(n, big.NewInt(1))
+}
+
 // ABI Encoding Implementation
 
 // encodeUint256 encodes a uint256 value to 32 bytes (big-endian)
@@ -145,8 +315,12 @@ func encodeInt256(val interface{}) ([]byte, error) {
 	result := make([]byte, 32)
 	switch v := val.(type) {
 	case *big.Int:
-		// Check if value fits in 256 bits (considering sign)
-		if v.BitLen() >= 256 {
+		// Check the value is within [-2^255, 2^255-1]
+		magnitude := new(big.Int).Set(v)
+		if v.Sign() < 0 {
+			magnitude.Neg(v).Sub(magnitude, big.NewInt(1))
+		}
+		if magnitude.BitLen() > 255 {
 			return nil, errors.New("value too large for int256")
 		}
 
@@ -213,6 +387,209 @@ func encodeString(str string) ([]byte, error) {
 	return encodeBytes([]byte(str))
 }
 
+// encodeBytesArray encodes a bytes[] value: its length, the offset of every
+// element relative to the end of the length, then the elements
+func encodeBytesArray(elems [][]byte) ([]byte, error) {
+	result, err := encodeUint256(uint64(len(elems)))
+	if err != nil {
+		return nil, err
+	}
+
+	var tail []byte
+	for _, elem := range elems {
+		offset, err := encodeUint256(uint64(32*len(elems) + len(tail)))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, offset...)
+
+		data, err := encodeBytes(elem)
+		if err != nil {
+			return nil, err
+		}
+		tail = append(tail, data...)
+	}
+	return append(result, tail...), nil
+}
+
+// encodeFixedBytes encodes a bytesN value, right-padded to 32 bytes
+func encodeFixedBytes(data []byte) ([]byte, error) {
+	if len(data) > 32 {
+		return nil, fmt.Errorf("%d bytes too large for a fixed bytes value", len(data))
+	}
+	result := make([]byte, 32)
+	copy(result, data)
+	return result, nil
+}
+
+// encodeTuple lays out encoded values as an ABI tuple: static values in the
+// head, dynamic ones as an offset in the head, relative to the start of the
+// tuple, to their data in the tail
+func encodeTuple(values [][]byte, dynamic []bool) []byte {
+	headSize := 0
+	for i, value := range values {
+		if dynamic[i] {
+			headSize += 32
+		} else {
+			headSize += len(value)
+		}
+	}
+
+	var head, tail []byte
+	for i, value := range values {
+		if !dynamic[i] {
+			head = append(head, value...)
+			continue
+		}
+		offset, _ := encodeUint256(uint64(headSize + len(tail)))
+		head = append(head, offset...)
+		tail = append(tail, value...)
+	}
+	return append(head, tail...)
+}
+
+// encodeArray encodes n elements as a fixed-size array, which is laid out as a
+// tuple of its elements
+func encodeArray(n int, elem func(i int) ([]byte, error), dynamic bool) ([]byte, error) {
+	values := make([][]byte, n)
+	flags := make([]bool, n)
+	for i := range values {
+		data, err := elem(i)
+		if err != nil {
+			return nil, fmt.Errorf("encoding element %d: %w", i, err)
+		}
+		values[i], flags[i] = data, dynamic
+	}
+	return encodeTuple(values, flags), nil
+}
+
+// encodeSlice encodes n elements as a dynamic array: its length followed by
+// the elements laid out as a fixed-size array
+func encodeSlice(n int, elem func(i int) ([]byte, error), dynamic bool) ([]byte, error) {
+	data, err := encodeArray(n, elem, dynamic)
+	if err != nil {
+		return nil, err
+	}
+	length, _ := encodeUint256(uint64(n))
+	return append(length, data...), nil
+}
+
+// TupleEncoder is implemented by the generated structs, so that Pack and the
+// helpers built on it encode them, and slices and arrays of them, as ABI tuples
+type TupleEncoder interface {
+	EncodeTuple() ([]byte, error)
+	IsDynamicTuple() bool
+}
+
+// encodeArg encodes a single argument of the types accepted by Pack
+func encodeArg(arg any) ([]byte, error) {
+	switch v := arg.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, errors.New("encoding big.Int: nil value")
+		}
+		encode := encodeUint256
+		if v.Sign() < 0 {
+			encode = encodeInt256
+		}
+		data, err := encode(v)
+		if err != nil {
+			return nil, fmt.Errorf("encoding big.Int: %w", err)
+		}
+		return data, nil
+	case uint8, uint16, uint32, uint64:
+		data, err := encodeUint256(widenUint(v))
+		if err != nil {
+			return nil, fmt.Errorf("encoding %T: %w", v, err)
+		}
+		return data, nil
+	case int8, int16, int32, int64, int:
+		data, err := encodeInt256(widenInt(v))
+		if err != nil {
+			return nil, fmt.Errorf("encoding %T: %w", v, err)
+		}
+		return data, nil
+	case Address:
+		return encodeAddress(v)
+	case bool:
+		return encodeBool(v)
+	case [32]byte:
+		return v[:], nil
+	case Hash:
+		return v[:], nil
+	case string:
+		return encodeString(v)
+	case []byte:
+		return encodeBytes(v)
+	case []*big.Int:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, false)
+	case []uint64:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, false)
+	case []Address:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, false)
+	case []bool:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, false)
+	case [][32]byte:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, false)
+	case []string:
+		return encodeSlice(len(v), func(i int) ([]byte, error) { return encodeArg(v[i]) }, true)
+	case TupleEncoder:
+		return v.EncodeTuple()
+	default:
+		if tuples, ok := tupleArray(arg); ok {
+			elem := func(i int) ([]byte, error) { return tuples.Index(i).Interface().(TupleEncoder).EncodeTuple() }
+			if tuples.Kind() == reflect.Slice {
+				return encodeSlice(tuples.Len(), elem, isDynamicArg(reflect.Zero(tuples.Type().Elem()).Interface()))
+			}
+			return encodeArray(tuples.Len(), elem, isDynamicArg(reflect.Zero(tuples.Type().Elem()).Interface()))
+		}
+		return nil, fmt.Errorf("unsupported argument type: %T", arg)
+	}
+}
+
+// tupleArray returns arg as a slice or array of generated structs, whose
+// types the argument type switch cannot name
+func tupleArray(arg any) (reflect.Value, bool) {
+	v := reflect.ValueOf(arg)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return v, false
+	}
+	if v.Type().Elem().Kind() != reflect.Struct {
+		return v, false
+	}
+	_, ok := reflect.Zero(v.Type().Elem()).Interface().(TupleEncoder)
+	return v, ok
+}
+
+// isDynamicArg reports whether an argument accepted by Pack is encoded behind an offset
+func isDynamicArg(arg any) bool {
+	switch v := arg.(type) {
+	case string, []byte, []*big.Int, []uint64, []Address, []bool, [][32]byte, []string:
+		return true
+	case TupleEncoder:
+		return v.IsDynamicTuple()
+	}
+	if tuples, ok := tupleArray(arg); ok {
+		return tuples.Kind() == reflect.Slice || isDynamicArg(reflect.Zero(tuples.Type().Elem()).Interface())
+	}
+	return false
+}
+
+// EncodeMulticall encodes calldatas, e.g. the outputs of Pack, as the bytes[]
+// argument of the common multicall(bytes[]) function. The result is the
+// calldata without the selector, to append to the multicall selector.
+func EncodeMulticall(calls ...HexData) HexData {
+	elems := make([][]byte, len(calls))
+	for i, call := range calls {
+		elems[i] = call.Bytes()
+	}
+
+	// A single dynamic argument, so its data starts right after its offset
+	offset, _ := encodeUint256(uint64(32))
+	encoded, _ := encodeBytesArray(elems)
+	return HexData("0x" + hex.EncodeToString(append(offset, encoded...)))
+}
+
 // ABI Decoding Implementation
 
 // decodeUint256 decodes a uint256 from 32 bytes to *big.Int
@@ -247,24 +624,6 @@ func decodeInt256(data []byte) (*big.Int, error) {
 	return result, nil
 }
 
-// decodeAddress decodes an address from 32 bytes
-func decodeAddress(data []byte) (Address, error) {
-	if len(data) < 32 {
-		return Address{}, errors.New("insufficient data for address")
-	}
-	var addr Address
-	copy(addr[:], data[12:32])
-	return addr, nil
-}
-
-// decodeBool decodes a boolean from 32 bytes
-func decodeBool(data []byte) (bool, error) {
-	if len(data) < 32 {
-		return false, errors.New("insufficient data for bool")
-	}
-	return data[31] != 0, nil
-}
-
 // decodeBytes decodes dynamic bytes
 func decodeBytes(data []byte, offset int) ([]byte, int, error) {
 	if len(data) < offset+32 {
@@ -322,54 +681,41 @@ func decodeBytes32(data []byte) ([32]byte, error) {
 	return result, nil
 }
 
-// decodeArray decodes dynamic arrays
-func decodeArray(data []byte, offset int, elemDecoder func([]byte) (interface{}, error)) ([]interface{}, int, error) {
+// decodeArrayLength decodes the length of a dynamic array at offset, checking
+// that data holds its 32-byte elements
+func decodeArrayLength(data []byte, offset int) (int, error) {
 	if len(data) < offset+32 {
-		return nil, 0, errors.New("insufficient data for array length")
+		return 0, errors.New("insufficient data for array length")
 	}
-
 	lengthBig, err := decodeUint256(data[offset : offset+32])
 	if err != nil {
-		return nil, 0, fmt.Errorf("decoding array length: %w", err)
+		return 0, fmt.Errorf("decoding array length: %w", err)
 	}
 	if !lengthBig.IsUint64() {
-		return nil, 0, errors.New("array length too large")
+		return 0, errors.New("array length too large")
 	}
-	length := int(lengthBig.Uint64())
-
-	currentOffset := offset + 32
-	result := make([]interface{}, length)
+	length := lengthBig.Uint64()
+	if length > uint64(len(data)-offset-32)/32 {
+		return 0, fmt.Errorf("insufficient data for %d array elements", length)
+	}
+	return int(length), nil
+}
 
-	for i := 0; i < length; i++ {
-		if len(data) < currentOffset+32 {
-			return nil, 0, fmt.Errorf("insufficient data for array element %d", i)
-		}
-		elem, err := elemDecoder(data[currentOffset : currentOffset+32])
+// decodeFixedBytesElems decodes the length bytesN elements of a bytesN array,
+// each left-aligned in its own 32-byte word from offset
+func decodeFixedBytesElems(data []byte, offset, length, size int) ([][]byte, error) {
+	if len(data) < offset+length*32 {
+		return nil, fmt.Errorf("insufficient data for %d fixed bytes elements", length)
+	}
+	elems := make([][]byte, length)
+	for i := range elems {
+		elem, err := decodeFixedBytes(data[offset+i*32:], size)
 		if err != nil {
-			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
+			return nil, fmt.Errorf("decoding array element %d: %w", i, err)
 		}
-		result[i] = elem
-		currentOffset += 32
+		elems[i] = elem
 	}
-
-	return result, currentOffset, nil
-}
-
-// Array element decoders (internal use)
-func decodeUint256ArrayElement(data []byte) (interface{}, error) {
-	return decodeUint256(data)
-}
-
-func decodeInt256ArrayElement(data []byte) (interface{}, error) {
-	return decodeInt256(data)
-}
-
-func decodeAddressArrayElement(data []byte) (interface{}, error) {
-	return decodeAddress(data)
-}
-
-func decodeBoolArrayElement(data []byte) (interface{}, error) {
-	return decodeBool(data)
+	return elems, nil
 }
 
 // decodeUint8 decodes a uint8 from 32 bytes
@@ -457,19 +803,51 @@ func decodeInt64(data []byte) (int64, error) {
 		}
 	}
 
+	// The sign bit of the low 8 bytes must match the sign extension
+	if (data[24]&0x80 != 0) != isNegative {
+		return 0, errors.New("value exceeds int64 range")
+	}
+
+	// The low 8 bytes are already the two's complement int64
 	var result int64
 	for i := 24; i < 32; i++ {
 		result = (result << 8) | int64(data[i])
 	}
 
-	// Sign extend if necessary
-	if isNegative {
-		result |= ^((1 << 32) - 1) // Set upper 32 bits
-	}
-
 	return result, nil
 }
 
+// decodeInt8 decodes a int8 from 32 bytes
+func decodeInt8(data []byte) (int8, error) {
+	val, err := decodeSmallInt(data, 8)
+	return int8(val), err
+}
+
+// decodeInt16 decodes a int16 from 32 bytes
+func decodeInt16(data []byte) (int16, error) {
+	val, err := decodeSmallInt(data, 16)
+	return int16(val), err
+}
+
+// decodeInt32 decodes a int32 from 32 bytes
+func decodeInt32(data []byte) (int32, error) {
+	val, err := decodeSmallInt(data, 32)
+	return int32(val), err
+}
+
+// decodeSmallInt decodes a signed integer of the given bits from 32 bytes,
+// rejecting values that do not fit, e.g. 200 as an int8
+func decodeSmallInt(data []byte, bits int) (int64, error) {
+	if len(data) < 32 {
+		return 0, fmt.Errorf("insufficient data for int%d", bits)
+	}
+	val, err := decodeInt64(data)
+	if err != nil || val < -1<<(bits-1) || val >= 1<<(bits-1) {
+		return 0, fmt.Errorf("value exceeds int%d range", bits)
+	}
+	return val, nil
+}
+
 // decodeHash decodes a 32-byte hash
 func decodeHash(data []byte) (Hash, error) {
 	if len(data) < 32 {
@@ -489,6 +867,166 @@ func decodeString(data []byte, offset int) (string, int, error) {
 	return string(bytes), nextOffset, nil
 }
 
+// decodeOffset reads the offset word at headOffset and returns the position it
+// points to, relative to base, as used for dynamic values in a tuple head
+func decodeOffset(data []byte, base, headOffset int) (int, error) {
+	if len(data) < headOffset+32 {
+		return 0, errors.New("insufficient data for offset")
+	}
+	offset, err := decodeUint256(data[headOffset : headOffset+32])
+	if err != nil {
+		return 0, fmt.Errorf("decoding offset: %w", err)
+	}
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-base) {
+		return 0, errors.New("offset out of bounds")
+	}
+	return base + int(offset.Uint64()), nil
+}
+
+// decodeBytesReader reads the encoding of a single bytes or string return value
+// from r, and returns a reader streaming its content from r along with its length
+func decodeBytesReader(r io.Reader) (io.Reader, uint64, error) {
+	word := make([]byte, 32)
+	if _, err := io.ReadFull(r, word); err != nil {
+		return nil, 0, fmt.Errorf("reading offset: %w", err)
+	}
+	offset, err := decodeUint256(word)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decoding offset: %w", err)
+	}
+	if !offset.IsInt64() || offset.Int64() < 32 {
+		return nil, 0, errors.New("offset out of bounds")
+	}
+	// Skip anything between the head and the length word
+	if _, err := io.CopyN(io.Discard, r, offset.Int64()-32); err != nil {
+		return nil, 0, fmt.Errorf("skipping to bytes length: %w", err)
+	}
+
+	if _, err := io.ReadFull(r, word); err != nil {
+		return nil, 0, fmt.Errorf("reading bytes length: %w", err)
+	}
+	length, err := decodeUint256(word)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decoding bytes length: %w", err)
+	}
+	if !length.IsInt64() {
+		return nil, 0, errors.New("bytes length too large")
+	}
+	return &exactReader{r: r, n: length.Int64()}, length.Uint64(), nil
+}
+
+// exactReader reads n bytes from r, reporting io.ErrUnexpectedEOF if r ends early
+type exactReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read implements io.Reader
+func (e *exactReader) Read(p []byte) (int, error) {
+	if e.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > e.n {
+		p = p[:e.n]
+	}
+	n, err := e.r.Read(p)
+	e.n -= int64(n)
+	if err == io.EOF && e.n > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// DecodeMulticall decodes the bytes[] returned by multicall(bytes[]) into the
+// return data of every call, to pass to the Decode of the matching method
+func DecodeMulticall(data []byte) ([]HexData, error) {
+	arrayOffset, err := decodeOffset(data, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("decoding multicall results: %w", err)
+	}
+	length, err := decodeArrayLength(data, arrayOffset)
+	if err != nil {
+		return nil, fmt.Errorf("decoding multicall results: %w", err)
+	}
+
+	// Element offsets are relative to the end of the array length
+	base := arrayOffset + 32
+	results := make([]HexData, length)
+	for i := range results {
+		elemOffset, err := decodeOffset(data, base, base+32*i)
+		if err != nil {
+			return nil, fmt.Errorf("decoding multicall result %d: %w", i, err)
+		}
+		result, _, err := decodeBytes(data, elemOffset)
+		if err != nil {
+			return nil, fmt.Errorf("decoding multicall result %d: %w", i, err)
+		}
+		results[i] = HexData("0x" + hex.EncodeToString(result))
+	}
+	return results, nil
+}
+
+// decodeAddress decodes an address from 32 bytes
+func decodeAddress(data []byte) (Address, error) {
+	if len(data) < 32 {
+		return Address{}, errors.New("insufficient data for address")
+	}
+	var addr Address
+	copy(addr[:], data[12:32])
+	return addr, nil
+}
+
+// decodeBool decodes a boolean from 32 bytes
+func decodeBool(data []byte) (bool, error) {
+	if len(data) < 32 {
+		return false, errors.New("insufficient data for bool")
+	}
+	return data[31] != 0, nil
+}
+
+// decodeArray decodes dynamic arrays
+func decodeArray(data []byte, offset int, elemDecoder func([]byte) (interface{}, error)) ([]interface{}, int, error) {
+	length, err := decodeArrayLength(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	currentOffset := offset + 32
+	result := make([]interface{}, length)
+
+	for i := 0; i < length; i++ {
+		elem, err := elemDecoder(data[currentOffset : currentOffset+32])
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+		result[i] = elem
+		currentOffset += 32
+	}
+
+	return result, currentOffset, nil
+}
+
+// Array element decoders (internal use)
+func decodeUint256ArrayElement(data []byte) (interface{}, error) {
+	return decodeUint256(data)
+}
+
+func decodeInt256ArrayElement(data []byte) (interface{}, error) {
+	return decodeInt256(data)
+}
+
+func decodeAddressArrayElement(data []byte) (interface{}, error) {
+	return decodeAddress(data)
+}
+
+func decodeBoolArrayElement(data []byte) (interface{}, error) {
+	return decodeBool(data)
+}
+
+func decodeBytes32ArrayElement(data []byte) (interface{}, error) {
+	return decodeBytes32(data)
+}
+
 // Method information
 func GetGetValueMethod() MethodInfo {
 	return MethodInfo{
@@ -550,6 +1088,12 @@ type EventDecoder struct {
 	Topic Hash
 }
 
+// RawLog is an undecoded log, as returned by eth_getLogs
+type RawLog struct {
+	Topics []Hash
+	Data   []byte
+}
+
 // PackableError represents an error with unpacking capabilities
 type PackableError struct {
 	Name      string
@@ -557,6 +1101,21 @@ type PackableError struct {
 	Selector  HexData
 }
 
+// StructCodec decodes and encodes a struct as an ABI tuple, for tooling that
+// goes over the structs of a contract without reflection
+type StructCodec struct {
+	Name   string
+	Decode func(data []byte) (any, error)
+	Encode func(value any) ([]byte, error)
+}
+
+// ArgDescriptor describes a method argument as declared in the ABI
+type ArgDescriptor struct {
+	Name   string // Solidity name, empty for unnamed arguments
+	Type   string // canonical Solidity type, e.g. uint256
+	GoType string // Go type of the generated binding
+}
+
 // MethodInfo represents method metadata
 type MethodInfo struct {
 	Name      string
@@ -590,50 +1149,154 @@ func (pm *PackableMethod) Pack(args ...any) (HexData, error) {
 		return pm.Selector, nil
 	}
 
-	// Encode arguments using our ABI implementation
-	var encodedArgs []byte
-	for _, arg := range args {
-		switch v := arg.(type) {
-		case *big.Int:
-			data, err := encodeUint256(v)
-			if err != nil {
-				return "", fmt.Errorf("encoding big.Int: %w", err)
-			}
-			encodedArgs = append(encodedArgs, data...)
-		case Address:
-			data, err := encodeAddress(v)
-			if err != nil {
-				return "", fmt.Errorf("encoding address: %w", err)
-			}
-			encodedArgs = append(encodedArgs, data...)
-		case bool:
-			data, err := encodeBool(v)
-			if err != nil {
-				return "", fmt.Errorf("encoding bool: %w", err)
-			}
-			encodedArgs = append(encodedArgs, data...)
-		case string:
-			data, err := encodeString(v)
-			if err != nil {
-				return "", fmt.Errorf("encoding string: %w", err)
-			}
-			encodedArgs = append(encodedArgs, data...)
-		case []byte:
-			data, err := encodeBytes(v)
-			if err != nil {
-				return "", fmt.Errorf("encoding bytes: %w", err)
+	// Integers must fit the size of their Solidity type, e.g. 300 is not a uint8
+	if argTypes := signatureArgTypes(pm.Signature); len(argTypes) == len(args) {
+		for i, arg := range args {
+			if err := checkArgBits(arg, argTypes[i]); err != nil {
+				return "", fmt.Errorf("packing %s argument %d: %w", pm.Name, i, err)
 			}
-			encodedArgs = append(encodedArgs, data...)
-		default:
-			return "", fmt.Errorf("unsupported argument type: %T", arg)
 		}
 	}
 
+	// Encode arguments using our ABI implementation. Static arguments are
+	// written in the head, dynamic ones as an offset to their data in the tail.
+	values := make([][]byte, len(args))
+	dynamic := make([]bool, len(args))
+	for i, arg := range args {
+		data, err := encodeArg(arg)
+		if err != nil {
+			return "", err
+		}
+		values[i], dynamic[i] = data, isDynamicArg(arg)
+	}
+	encodedArgs := encodeTuple(values, dynamic)
+
 	// Combine selector and encoded arguments
 	result := hex.EncodeToString(append(selectorBytes, encodedArgs...))
 	return HexData("0x" + result), nil
 }
 
+// widenUint widens the unsigned integer types accepted by Pack
+func widenUint(v any) uint64 {
+	switch n := v.(type) {
+	case uint8:
+		return uint64(n)
+	case uint16:
+		return uint64(n)
+	case uint32:
+		return uint64(n)
+	default:
+		return n.(uint64)
+	}
+}
+
+// widenInt widens the signed integer types accepted by Pack
+func widenInt(v any) int64 {
+	switch n := v.(type) {
+	case int8:
+		return int64(n)
+	case int16:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case int:
+		return int64(n)
+	default:
+		return n.(int64)
+	}
+}
+
+// signatureArgTypes returns the top-level argument types of a signature,
+// e.g. ["uint8", "(address,uint256)[]"] for "f(uint8,(address,uint256)[])"
+func signatureArgTypes(signature string) []string {
+	start, end := strings.Index(signature, "("), strings.LastIndex(signature, ")")
+	if start == -1 || end <= start+1 {
+		return nil
+	}
+
+	var argTypes []string
+	depth, from := 0, start+1
+	for i := start + 1; i < end; i++ {
+		switch signature[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				argTypes = append(argTypes, signature[from:i])
+				from = i + 1
+			}
+		}
+	}
+	return append(argTypes, signature[from:end])
+}
+
+// checkArgBits checks that an integer argument, or the elements of an integer
+// slice, fit the intN or uintN Solidity type they are packed as. Other
+// arguments and types are left to encodeArg.
+func checkArgBits(arg any, abiType string) error {
+	elemType, _, _ := strings.Cut(abiType, "[")
+	signed := strings.HasPrefix(elemType, "int")
+	if !signed && !strings.HasPrefix(elemType, "uint") {
+		return nil
+	}
+	bits := 256
+	if size := strings.TrimPrefix(strings.TrimPrefix(elemType, "u"), "int"); size != "" {
+		if _, err := fmt.Sscan(size, &bits); err != nil {
+			return nil
+		}
+	}
+
+	switch v := arg.(type) {
+	case *big.Int:
+		return checkIntBits(v, bits, signed, elemType)
+	case uint8, uint16, uint32, uint64:
+		return checkIntBits(new(big.Int).SetUint64(widenUint(v)), bits, signed, elemType)
+	case int8, int16, int32, int64, int:
+		return checkIntBits(big.NewInt(widenInt(v)), bits, signed, elemType)
+	case []*big.Int:
+		for i, elem := range v {
+			if err := checkIntBits(elem, bits, signed, elemType); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+	case []uint64:
+		for i, elem := range v {
+			if err := checkIntBits(new(big.Int).SetUint64(elem), bits, signed, elemType); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkIntBits checks that v fits a signed or unsigned integer of the given bits
+func checkIntBits(v *big.Int, bits int, signed bool, typeName string) error {
+	if v == nil {
+		return nil
+	}
+	if !signed {
+		if v.Sign() < 0 {
+			return fmt.Errorf("negative value %s for %s", v, typeName)
+		}
+		if v.BitLen() > bits {
+			return fmt.Errorf("value %s overflows %s", v, typeName)
+		}
+		return nil
+	}
+
+	// Two's complement range [-2^(bits-1), 2^(bits-1)-1]
+	magnitude := new(big.Int).Set(v)
+	if v.Sign() < 0 {
+		magnitude.Neg(v).Sub(magnitude, big.NewInt(1))
+	}
+	if magnitude.BitLen() > bits-1 {
+		return fmt.Errorf("value %s overflows %s", v, typeName)
+	}
+	return nil
+}
+
 // MustPack encodes method arguments and panics on error
 func (pm *PackableMethod) MustPack(args ...any) HexData {
 	result, err := pm.Pack(args...)
@@ -643,7 +1306,63 @@ func (pm *PackableMethod) MustPack(args ...any) HexData {
 	return result
 }
 
+// PackWithSelector encodes method arguments like Pack, but after the given
+// selector instead of the method's own, e.g. for forwarders and meta-transactions
+func (pm *PackableMethod) PackWithSelector(sel [4]byte, args ...any) (HexData, error) {
+	packed, err := pm.Pack(args...)
+	if err != nil {
+		return "", err
+	}
+	return withSelector(packed, sel), nil
+}
+
+// withSelector replaces the 4-byte selector of packed calldata
+func withSelector(packed HexData, sel [4]byte) HexData {
+	data := packed.Bytes()
+	copy(data, sel[:])
+	return HexData("0x" + hex.EncodeToString(data))
+}
+
+// PackArgs encodes method arguments like Pack, without the method selector, as
+// abi.encode(args) does, e.g. for hashing them off-chain
+func (pm *PackableMethod) PackArgs(args ...any) (HexData, error) {
+	packed, err := pm.Pack(args...)
+	if err != nil {
+		return "", err
+	}
+	return withoutSelector(packed), nil
+}
+
+// withoutSelector strips the 4-byte selector of packed calldata
+func withoutSelector(packed HexData) HexData {
+	return HexData("0x" + hex.EncodeToString(packed.Bytes()[4:]))
+}
+
+// PackPadded encodes method arguments like Pack and right-pads the calldata with
+// zero bytes to a multiple of alignment bytes. The padding follows the encoded
+// arguments, which the contract ignores when decoding them.
+func (pm *PackableMethod) PackPadded(alignment int, args ...any) (HexData, error) {
+	packed, err := pm.Pack(args...)
+	if err != nil {
+		return "", err
+	}
+	return padCalldata(packed, alignment)
+}
+
+// padCalldata right-pads packed calldata with zero bytes to a multiple of alignment
+func padCalldata(packed HexData, alignment int) (HexData, error) {
+	if alignment < 1 {
+		return "", fmt.Errorf("invalid calldata alignment %d", alignment)
+	}
+	data := packed.Bytes()
+	if rem := len(data) % alignment; rem != 0 {
+		data = append(data, make([]byte, alignment-rem)...)
+	}
+	return HexData("0x" + hex.EncodeToString(data)), nil
+}
+
 // GetValueMethod returns a packable method for getValue
+// getValue() [0x20965255]
 func (mr MethodRegistry) GetValueMethod() *GetValueMethod {
 	return &GetValueMethod{
 		PackableMethod: PackableMethod{
@@ -655,6 +1374,7 @@ func (mr MethodRegistry) GetValueMethod() *GetValueMethod {
 }
 
 // SetValueMethod returns a packable method for setValue
+// setValue(uint256) [0x55241077]
 func (mr MethodRegistry) SetValueMethod() *SetValueMethod {
 	return &SetValueMethod{
 		PackableMethod: PackableMethod{
@@ -670,16 +1390,88 @@ func Methods() MethodRegistry {
 	return MethodRegistry{}
 }
 
+// methodsByName indexes every method by its generated name, and the default
+// overloads also by their Solidity name
+var methodsByName = map[string]PackableMethod{
+	"getValue": {Name: "getValue", Signature: "getValue()", Selector: HexData("0x20965255")},
+	"setValue": {Name: "setValue", Signature: "setValue(uint256)", Selector: HexData("0x55241077")},
+}
+
+// methodsBySelector indexes every method by its 4-byte selector
+var methodsBySelector = map[[4]byte]PackableMethod{
+	{0x20, 0x96, 0x52, 0x55}: methodsByName["getValue"],
+	{0x55, 0x24, 0x10, 0x77}: methodsByName["setValue"],
+}
+
+// ByName returns the method with the given name. Overloaded methods are found by
+// their generated names, and their Solidity name returns the default overload,
+// like the accessor named after it
+func (mr MethodRegistry) ByName(name string) (PackableMethod, bool) {
+	method, ok := methodsByName[name]
+	return method, ok
+}
+
+// BySelector returns the method with the given 4-byte selector
+func (mr MethodRegistry) BySelector(selector [4]byte) (PackableMethod, bool) {
+	method, ok := methodsBySelector[selector]
+	return method, ok
+}
+
+// callSizes holds the calldata size, selector included, of the methods taking only static arguments
+var callSizes = map[[4]byte]int{
+	{0x20, 0x96, 0x52, 0x55}: 4,
+	{0x55, 0x24, 0x10, 0x77}: 36,
+}
+
+// SplitCalls splits concatenated calldata, as sent by some batchers, into its
+// calls. Each call must be to a method taking only static arguments, whose
+// calldata size follows from its selector.
+func SplitCalls(data []byte) ([]HexData, error) {
+	var calls []HexData
+	for offset := 0; offset < len(data); {
+		if len(data)-offset < 4 {
+			return nil, fmt.Errorf("truncated selector at offset %d", offset)
+		}
+		var selector [4]byte
+		copy(selector[:], data[offset:])
+		size, ok := callSizes[selector]
+		if !ok {
+			if method, known := methodsBySelector[selector]; known {
+				return nil, fmt.Errorf("cannot split %s at offset %d: dynamic arguments", method.Name, offset)
+			}
+			return nil, fmt.Errorf("unknown selector 0x%x at offset %d", selector, offset)
+		}
+		if len(data)-offset < size {
+			return nil, fmt.Errorf("truncated call to %s at offset %d: expected %d bytes, got %d", methodsBySelector[selector].Name, offset, size, len(data)-offset)
+		}
+		calls = append(calls, HexData("0x"+hex.EncodeToString(data[offset:offset+size])))
+		offset += size
+	}
+	return calls, nil
+}
+
 // GetValueMethod represents the getValue method with type-safe decode functionality
 type GetValueMethod struct {
 	PackableMethod
 }
 
+// Args describes the getValue arguments as declared in the ABI
+func (m *GetValueMethod) Args() []ArgDescriptor {
+	return nil
+}
+
 // SetValueMethod represents the setValue method with type-safe decode functionality
 type SetValueMethod struct {
 	PackableMethod
 }
 
+// Args describes the setValue arguments as declared in the ABI
+func (m *SetValueMethod) Args() []ArgDescriptor {
+	return []ArgDescriptor{
+		{Name: "newValue", Type: "uint256", GoType: "*big.Int"},
+	}
+}
+
 // ValueChangedEventDecoder returns a decoder for ValueChanged events
 func (er EventRegistry) ValueChangedEventDecoder() *ValueChangedEventDecoder {
 	return &ValueChangedEventDecoder{
@@ -716,6 +1508,24 @@ func Errors() ErrorRegistry {
 	return ErrorRegistry{}
 }
 
+// Error selectors, the first 4 bytes of the revert data of each custom error
+const (
+	ErrorSelectorInvalidValue HexData = "0x6072742c"
+)
+
+// DecodeError decodes revert data into the struct of the custom error whose
+// selector it starts with. Contracts without custom errors reject every selector.
+func DecodeError(data []byte) (any, error) {
+	if len(data) < 4 {
+		return nil, errors.New("insufficient data for error selector")
+	}
+	switch HexData("0x" + hex.EncodeToString(data[:4])) {
+	case ErrorSelectorInvalidValue:
+		return Errors().InvalidValueError().Decode(data)
+	}
+	return nil, fmt.Errorf("unknown error selector 0x%x", data[:4])
+}
+
 // InvalidValueErrorDecoder represents the InvalidValue error with type-safe decode functionality
 type InvalidValueErrorDecoder struct {
 	PackableError
@@ -732,6 +1542,20 @@ type InvalidValueError struct {
 	Provided *big.Int `json:"provided"`
 }
 
+// StructNames returns the names of the contract structs, sorted
+func StructNames() []string {
+	return []string{}
+}
+
+// structCodecs indexes the decoder and encoder of every struct by name
+var structCodecs = map[string]StructCodec{}
+
+// StructCodecByName returns the decoder and encoder of the named struct
+func StructCodecByName(name string) (StructCodec, bool) {
+	codec, ok := structCodecs[name]
+	return codec, ok
+}
+
 // Decode decodes return values for getValue method
 func (m *GetValueMethod) Decode(data []byte) (*big.Int, error) {
 	return m.decodeImpl(data)
@@ -756,6 +1580,17 @@ func (m *GetValueMethod) decodeImpl(data []byte) (*big.Int, error) {
 	return decodeUint256(data[offset : offset+32])
 }
 
+// Signature returns the canonical signature of the ValueChanged event
+func (e *ValueChangedEventDecoder) Signature() string {
+	return "ValueChanged(uint256,uint256)"
+}
+
+// SignatureHash returns keccak256 of the event signature, the topics[0] of
+// non-anonymous ValueChanged logs
+func (e *ValueChangedEventDecoder) SignatureHash() Hash {
+	return e.Topic
+}
+
 // Decode decodes log data for ValueChanged event
 func (e *ValueChangedEventDecoder) Decode(data []byte) (ValueChangedEvent, error) {
 	return e.decodeImpl(data)
@@ -770,6 +1605,42 @@ func (e *ValueChangedEventDecoder) MustDecode(data []byte) ValueChangedEvent {
 	return result
 }
 
+// DecodeLog decodes a ValueChanged log from its topics and data. Indexed value types
+// are decoded from their topic, indexed parameters of reference types are only
+// available as the keccak256 hash held in their topic.
+func (e *ValueChangedEventDecoder) DecodeLog(topics []Hash, data []byte) (ValueChangedEvent, error) {
+	var result ValueChangedEvent
+	if len(topics) != 1 {
+		return result, fmt.Errorf("expected 1 topics for event ValueChanged, got %d", len(topics))
+	}
+	if topics[0] != e.Topic {
+		return result, errors.New("topic does not match event ValueChanged")
+	}
+
+	result, err := e.decodeImpl(data)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// DecodeLogs decodes the ValueChanged logs among logs, in order, skipping the logs
+// of other events, whose first topic is not the ValueChanged topic
+func (e *ValueChangedEventDecoder) DecodeLogs(logs []RawLog) ([]ValueChangedEvent, error) {
+	var results []ValueChangedEvent
+	for i, log := range logs {
+		if len(log.Topics) == 0 || log.Topics[0] != e.Topic {
+			continue
+		}
+		result, err := e.DecodeLog(log.Topics, log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding log %d: %w", i, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 // decodeImpl contains the actual decode logic
 func (e *ValueChangedEventDecoder) decodeImpl(data []byte) (ValueChangedEvent, error) {
 	// Decode event parameters (only non-indexed parameters are in data)
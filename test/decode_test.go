@@ -4,11 +4,18 @@ package test
 
 import (
 	"encoding/hex"
+	"fmt"
+	"math/big"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/otherview/solgen/internal/gen"
+	"github.com/otherview/solgen/internal/types"
 )
 
 func TestDecode_SimpleTokenFullWorkflow(t *testing.T) {
@@ -154,8 +161,8 @@ func TestDecode_MethodEncodingDecoding(t *testing.T) {
 		t.Logf("   To address: 0x%s", toAddress)
 		t.Logf("   Amount: %d wei (1 ETH)", amount)
 
-		// Note: We can't actually call the generated methods here since they're in a different package
-		// But we've verified the contract generates and compiles, which tests the decoding logic
+		// The generated Pack and Decode are exercised inside the generated package
+		// by TestRoundTrip_GeneratedPackage
 	})
 
 	t.Run("BalanceOf Method", func(t *testing.T) {
@@ -279,6 +286,111 @@ func TestDecode_ErrorDecoding(t *testing.T) {
 	})
 }
 
+// TestDecode_GeneratedPackages generates the decoding fixtures below into one
+// module and runs their in-package tests, which need the compiled code
+func TestDecode_GeneratedPackages(t *testing.T) {
+	runGeneratedFixtures(t, map[string]generatedFixture{
+		"qualified_errors":                         qualifiedErrorsFixture,
+		"dynamic_struct_fields":                    dynamicStructFieldsFixture,
+		"dynamic_struct_array_return":              dynamicStructArrayReturnFixture,
+		"indexed_array_event_param":                indexedArrayEventParamFixture,
+		"indexed_value_event_params":               indexedValueEventParamsFixture,
+		"partially_indexed_event_data":             partiallyIndexedEventDataFixture,
+		"event_signature_hash":                     eventSignatureHashFixture,
+		"proxy_implementation_slot":                proxyImplementationSlotFixture,
+		"event_decode_logs":                        eventDecodeLogsFixture,
+		"bytes_return_reader":                      bytesReturnReaderFixture,
+		"struct_error_param":                       structErrorParamFixture,
+		"bytes32_array_return":                     bytes32ArrayReturnFixture,
+		"int256_array_return":                      int256ArrayReturnFixture,
+		"negative_signed_returns":                  negativeSignedReturnsFixture,
+		"int256_array_differential":                int256ArrayDifferentialFixture,
+		"error_selector_constants":                 errorSelectorConstantsFixture,
+		"fixed_bytes_array_event_and_error_params": fixedBytesArrayEventAndErrorParamsFixture,
+		"erc20_transfer_filter":                    erc20TransferFilterFixture,
+		"strict_decode": func(t *testing.T, outputDir string) map[string]string {
+			return strictDecodeFixture(t, outputDir, true)
+		},
+		"lenient_decode": func(t *testing.T, outputDir string) map[string]string {
+			return strictDecodeFixture(t, outputDir, false)
+		},
+		"eip712_domain":           eip712DomainFixture,
+		"eip712_domain_no_keccak": eip712DomainNoKeccakFixture,
+		"method_args":             methodArgsFixture,
+	})
+}
+
+func qualifiedErrorsFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"SimpleToken.sol:SimpleToken": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "transfer",
+						"inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}],
+						"outputs": [{"name": "", "type": "bool"}],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "event",
+						"name": "Transfer",
+						"inputs": [
+							{"name": "from", "type": "address", "indexed": true},
+							{"name": "to", "type": "address", "indexed": true},
+							{"name": "value", "type": "uint256", "indexed": false}
+						],
+						"anonymous": false
+					},
+					{
+						"type": "error",
+						"name": "InsufficientBalance",
+						"inputs": [{"name": "needed", "type": "uint256"}]
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"transfer(address,uint256)": "a9059cbb"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{Qualified: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"simpletoken": `package simpletoken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQualifiedErrors(t *testing.T) {
+	_, err := Methods().TransferMethod().Decode(nil)
+	if err == nil || err.Error() != "simpletoken.transfer: insufficient data for return value" {
+		t.Errorf("unexpected method error %v", err)
+	}
+	if errors.Unwrap(err) == nil {
+		t.Error("expected the qualified error to wrap the decoding error")
+	}
+
+	if _, err := Events().TransferEventDecoder().Decode(nil); err == nil || err.Error() != "simpletoken.Transfer: insufficient data for event parameter value" {
+		t.Errorf("unexpected event error %v", err)
+	}
+
+	if _, err := Errors().InsufficientBalanceError().Decode(nil); err == nil || err.Error() != "simpletoken.InsufficientBalance: insufficient data for error selector" {
+		t.Errorf("unexpected error decoder error %v", err)
+	}
+}
+`}
+}
+
 func TestDecode_EncodingRoundtrip(t *testing.T) {
 	t.Run("Uint256 Encoding/Decoding", func(t *testing.T) {
 		testValues := []uint64{
@@ -369,3 +481,1812 @@ func TestDecode_EncodingRoundtrip(t *testing.T) {
 		t.Logf("✅ Bool encoding/decoding roundtrip test passed")
 	})
 }
+
+func dynamicStructFieldsFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Registry.sol:Registry": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "record",
+						"inputs": [],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"internalType": "struct Registry.Record",
+							"components": [
+								{"name": "a", "type": "uint256", "internalType": "uint256"},
+								{"name": "b", "type": "string", "internalType": "string"},
+								{"name": "c", "type": "address", "internalType": "address"}
+							]
+						}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"record()": "266cf109"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	if len(contracts[0].Structs) != 1 || !contracts[0].Structs[0].Dynamic {
+		t.Fatalf("expected a single dynamic Record struct, got %+v", contracts[0].Structs)
+	}
+
+	// Encode the return data with go-ethereum, the string sits in the tail after c
+	recordType, err := abi.NewType("tuple", "struct Registry.Record", []abi.ArgumentMarshaling{
+		{Name: "a", Type: "uint256"},
+		{Name: "b", Type: "string"},
+		{Name: "c", Type: "address"},
+	})
+	if err != nil {
+		t.Fatalf("failed to build tuple type: %v", err)
+	}
+	record := struct {
+		A *big.Int
+		B string
+		C common.Address
+	}{big.NewInt(42), "a string longer than thirty-two bytes", common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")}
+	encoded, err := abi.Arguments{{Type: recordType}}.Pack(record)
+	if err != nil {
+		t.Fatalf("failed to encode record: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"registry": `package registry
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestDynamicStructDecode(t *testing.T) {
+	data, _ := hex.DecodeString("` + hex.EncodeToString(encoded) + `")
+
+	record, err := Methods().RecordMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if record.A.Int64() != 42 {
+		t.Errorf("expected a 42, got %s", record.A)
+	}
+	if record.B != "a string longer than thirty-two bytes" {
+		t.Errorf("unexpected b %q", record.B)
+	}
+	if record.C != AddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed") {
+		t.Errorf("unexpected c %s", record.C)
+	}
+
+	// A truncated tail must be reported, not read out of bounds
+	if _, err := Methods().RecordMethod().Decode(data[:len(data)-64]); err == nil {
+		t.Error("expected an error for truncated data")
+	}
+}
+`}
+}
+
+// shopABI returns an array of dynamic structs, alone and after another value
+const shopABI = `[
+	{"type": "function", "name": "orders", "inputs": [], "stateMutability": "view", "outputs": [
+		{"name": "", "type": "tuple[]", "internalType": "struct Shop.Order[]", "components": [
+			{"name": "sku", "type": "string", "internalType": "string"},
+			{"name": "qty", "type": "uint256[]", "internalType": "uint256[]"}
+		]}
+	]},
+	{"type": "function", "name": "book", "inputs": [], "stateMutability": "view", "outputs": [
+		{"name": "count", "type": "uint256", "internalType": "uint256"},
+		{"name": "list", "type": "tuple[]", "internalType": "struct Shop.Order[]", "components": [
+			{"name": "sku", "type": "string", "internalType": "string"},
+			{"name": "qty", "type": "uint256[]", "internalType": "uint256[]"}
+		]}
+	]}
+]`
+
+func dynamicStructArrayReturnFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Shop.sol:Shop": {
+				"abi": ` + shopABI + `,
+				"hashes": {"orders()": "4fb764c9", "book()": "05a8da72"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	// Encode the return data with go-ethereum, every order sits behind its own offset
+	parsed, err := abi.JSON(strings.NewReader(shopABI))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	type order struct {
+		Sku string
+		Qty []*big.Int
+	}
+	orders := []order{
+		{Sku: "a sku longer than thirty-two bytes", Qty: []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}},
+		{Sku: "", Qty: []*big.Int{}},
+		{Sku: "last", Qty: []*big.Int{big.NewInt(7)}},
+	}
+	ordersData, err := parsed.Methods["orders"].Outputs.Pack(orders)
+	if err != nil {
+		t.Fatalf("failed to encode orders: %v", err)
+	}
+	bookData, err := parsed.Methods["book"].Outputs.Pack(big.NewInt(3), orders)
+	if err != nil {
+		t.Fatalf("failed to encode book: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"shop": `package shop
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func checkOrders(t *testing.T, orders []Order) {
+	t.Helper()
+	if len(orders) != 3 {
+		t.Fatalf("expected 3 orders, got %d", len(orders))
+	}
+	if orders[0].Sku != "a sku longer than thirty-two bytes" || len(orders[0].Qty) != 3 || orders[0].Qty[2].Int64() != 3 {
+		t.Errorf("unexpected first order %+v", orders[0])
+	}
+	if orders[1].Sku != "" || len(orders[1].Qty) != 0 {
+		t.Errorf("unexpected empty order %+v", orders[1])
+	}
+	if orders[2].Sku != "last" || len(orders[2].Qty) != 1 || orders[2].Qty[0].Int64() != 7 {
+		t.Errorf("unexpected last order %+v", orders[2])
+	}
+}
+
+func TestDynamicStructArrayDecode(t *testing.T) {
+	ordersData, _ := hex.DecodeString("` + hex.EncodeToString(ordersData) + `")
+	orders, err := Methods().OrdersMethod().Decode(ordersData)
+	if err != nil {
+		t.Fatalf("decoding orders: %v", err)
+	}
+	checkOrders(t, orders)
+
+	bookData, _ := hex.DecodeString("` + hex.EncodeToString(bookData) + `")
+	book, err := Methods().BookMethod().Decode(bookData)
+	if err != nil {
+		t.Fatalf("decoding book: %v", err)
+	}
+	if book.Count.Int64() != 3 {
+		t.Errorf("expected count 3, got %s", book.Count)
+	}
+	checkOrders(t, book.List)
+
+	// A truncated tail must be reported, not read out of bounds
+	if _, err := Methods().OrdersMethod().Decode(ordersData[:len(ordersData)-64]); err == nil {
+		t.Error("expected an error for truncated data")
+	}
+}
+`}
+}
+
+func indexedArrayEventParamFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Batcher.sol:Batcher": {
+				"abi": [
+					{
+						"type": "event",
+						"name": "Batch",
+						"inputs": [
+							{"name": "ids", "type": "uint256[]", "indexed": true, "internalType": "uint256[]"},
+							{"name": "count", "type": "uint256", "indexed": false, "internalType": "uint256"}
+						],
+						"anonymous": false
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	if !contracts[0].Events[0].Inputs[0].Hashed {
+		t.Fatal("indexed uint256[] parameter should be hashed")
+	}
+
+	// The topic holds keccak256(abi.encode(ids)), the values cannot be recovered
+	uint256Slice, err := abi.NewType("uint256[]", "", nil)
+	if err != nil {
+		t.Fatalf("failed to build array type: %v", err)
+	}
+	encoded, err := abi.Arguments{{Type: uint256Slice}}.Pack([]*big.Int{big.NewInt(1), big.NewInt(2)})
+	if err != nil {
+		t.Fatalf("failed to encode ids: %v", err)
+	}
+	idsHash := crypto.Keccak256Hash(encoded[32:])
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "batcher", "batcher.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "IdsHash Hash") {
+		t.Error("generated event struct should have an IdsHash field")
+	}
+
+	return map[string]string{"batcher": `package batcher
+
+import "testing"
+
+func TestIndexedArrayDecodeLog(t *testing.T) {
+	decoder := Events().BatchEventDecoder()
+	data := make([]byte, 32)
+	data[31] = 2
+
+	topics := []Hash{decoder.Topic, HashFromHex("` + idsHash.Hex() + `")}
+	event, err := decoder.DecodeLog(topics, data)
+	if err != nil {
+		t.Fatalf("DecodeLog failed: %v", err)
+	}
+	if event.IdsHash != topics[1] {
+		t.Errorf("expected ids hash %s, got %s", topics[1], event.IdsHash)
+	}
+	if event.Ids != nil {
+		t.Errorf("expected no ids values, got %v", event.Ids)
+	}
+	if event.Count.Int64() != 2 {
+		t.Errorf("expected count 2, got %s", event.Count)
+	}
+
+	if _, err := decoder.DecodeLog(topics[:1], data); err == nil {
+		t.Error("expected an error for a missing topic")
+	}
+	if _, err := decoder.DecodeLog([]Hash{topics[1], topics[1]}, data); err == nil {
+		t.Error("expected an error for a mismatched event topic")
+	}
+}
+`}
+}
+
+func indexedValueEventParamsFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Registry.sol:Registry": {
+				"abi": [
+					{
+						"type": "event",
+						"name": "Named",
+						"inputs": [
+							{"name": "key", "type": "bytes32", "indexed": true, "internalType": "bytes32"},
+							{"name": "value", "type": "uint256", "indexed": false, "internalType": "uint256"}
+						],
+						"anonymous": false
+					},
+					{
+						"type": "event",
+						"name": "Tagged",
+						"inputs": [
+							{"name": "owner", "type": "address", "indexed": true, "internalType": "address"},
+							{"name": "note", "type": "string", "indexed": false, "internalType": "string"},
+							{"name": "tag", "type": "bytes4", "indexed": true, "internalType": "bytes4"},
+							{"name": "delta", "type": "int16", "indexed": true, "internalType": "int16"}
+						],
+						"anonymous": false
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"registry": `package registry
+
+import "testing"
+
+func TestIndexedValueDecodeLog(t *testing.T) {
+	named := Events().NamedEventDecoder()
+	key := HashFromHex("0x6b65790000000000000000000000000000000000000000000000000000000001")
+	data := make([]byte, 32)
+	data[31] = 7
+
+	event, err := named.DecodeLog([]Hash{named.Topic, key}, data)
+	if err != nil {
+		t.Fatalf("DecodeLog failed: %v", err)
+	}
+	if event.Key != [32]byte(key) {
+		t.Errorf("expected key %s, got %x", key, event.Key)
+	}
+	if event.Value.Int64() != 7 {
+		t.Errorf("expected value 7, got %s", event.Value)
+	}
+
+	// Indexed parameters come from the topics in order, the string from the data
+	tagged := Events().TaggedEventDecoder()
+	topics := []Hash{
+		tagged.Topic,
+		HashFromHex("0x000000000000000000000000000000000000000000000000000000000000beef"),
+		HashFromHex("0xdeadbeef00000000000000000000000000000000000000000000000000000000"),
+		HashFromHex("0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe"),
+	}
+	data = make([]byte, 96)
+	data[31] = 32
+	data[63] = 2
+	copy(data[64:], "hi")
+
+	result, err := tagged.DecodeLog(topics, data)
+	if err != nil {
+		t.Fatalf("DecodeLog failed: %v", err)
+	}
+	if result.Owner != AddressFromHex("0x000000000000000000000000000000000000beef") {
+		t.Errorf("unexpected owner %s", result.Owner)
+	}
+	if result.Tag != [4]byte{0xde, 0xad, 0xbe, 0xef} {
+		t.Errorf("unexpected tag %x", result.Tag)
+	}
+	if result.Delta != -2 {
+		t.Errorf("expected delta -2, got %d", result.Delta)
+	}
+	if result.Note != "hi" {
+		t.Errorf("expected note hi, got %q", result.Note)
+	}
+
+	// A value not fitting the indexed type is rejected
+	topics[3] = HashFromHex("0x0000000000000000000000000000000000000000000000010000000000000000")
+	if _, err := tagged.DecodeLog(topics, data); err == nil {
+		t.Error("expected an error for an out of range int16 topic")
+	}
+}
+`}
+}
+
+func partiallyIndexedEventDataFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Registry.sol:Registry": {
+				"abi": [
+					{
+						"type": "event",
+						"name": "Renamed",
+						"inputs": [
+							{"name": "owner", "type": "address", "indexed": true, "internalType": "address"},
+							{"name": "label", "type": "string", "indexed": true, "internalType": "string"},
+							{"name": "value", "type": "uint256", "indexed": false, "internalType": "uint256"}
+						],
+						"anonymous": false
+					}
+				],
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "registry", "registry.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "// Indexed parameters are held in the topics: Decode leaves Owner, LabelHash\n// zero, and DecodeLog decodes them as well") {
+		t.Error("expected Decode to document the fields it leaves zero")
+	}
+
+	return map[string]string{"registry": `package registry
+
+import "testing"
+
+func TestPartiallyIndexedDecode(t *testing.T) {
+	decoder := Events().RenamedEventDecoder()
+	data := make([]byte, 32)
+	data[31] = 9
+
+	// Decode only reads the data, the indexed fields stay zero
+	event, err := decoder.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if event.Value.Int64() != 9 {
+		t.Errorf("expected value 9, got %s", event.Value)
+	}
+	if event.Owner != (Address{}) || event.LabelHash != (Hash{}) || event.Label != "" {
+		t.Errorf("expected the indexed fields to be zero, got %+v", event)
+	}
+
+	// DecodeLog fills them from the topics
+	owner := AddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	var ownerTopic Hash
+	copy(ownerTopic[12:], owner[:])
+	labelHash := HashFromHex("0x1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8")
+	event, err = decoder.DecodeLog([]Hash{decoder.Topic, ownerTopic, labelHash}, data)
+	if err != nil {
+		t.Fatalf("DecodeLog failed: %v", err)
+	}
+	if event.Owner != owner || event.LabelHash != labelHash || event.Value.Int64() != 9 {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
+`}
+}
+
+func eventSignatureHashFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Registry.sol:Registry": {
+				"abi": [
+					{
+						"type": "event",
+						"name": "Named",
+						"inputs": [
+							{"name": "key", "type": "bytes32", "indexed": true, "internalType": "bytes32"},
+							{"name": "value", "type": "uint256", "indexed": false, "internalType": "uint256"}
+						],
+						"anonymous": false
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{WithKeccak: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	topic := crypto.Keccak256Hash([]byte("Named(bytes32,uint256)"))
+	return map[string]string{"registry": `package registry
+
+import "testing"
+
+func TestEventSignatureHash(t *testing.T) {
+	decoder := Events().NamedEventDecoder()
+	if decoder.Signature() != "Named(bytes32,uint256)" {
+		t.Errorf("unexpected signature %s", decoder.Signature())
+	}
+	if decoder.SignatureHash() != decoder.Topic {
+		t.Errorf("expected the stored topic %s, got %s", decoder.Topic, decoder.SignatureHash())
+	}
+	if decoder.SignatureHash() != HashFromHex("` + topic.Hex() + `") {
+		t.Errorf("unexpected signature hash %s", decoder.SignatureHash())
+	}
+	if Keccak256([]byte(decoder.Signature())) != decoder.SignatureHash() {
+		t.Error("expected the signature hash to be keccak256 of the signature")
+	}
+}
+`}
+}
+
+func proxyImplementationSlotFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Proxy.sol:Proxy": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "upgradeTo",
+						"inputs": [{"name": "implementation", "type": "address", "internalType": "address"}],
+						"outputs": [],
+						"stateMutability": "nonpayable"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"upgradeTo(address)": "3659cfe6"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{WithProxy: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	// The slot is keccak256("eip1967.proxy.implementation") - 1
+	slot := new(big.Int).SetBytes(crypto.Keccak256([]byte("eip1967.proxy.implementation")))
+	slot.Sub(slot, big.NewInt(1))
+	return map[string]string{"proxy": `package proxy
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestImplementationSlot(t *testing.T) {
+	if got := hex.EncodeToString(ImplementationSlot().Bytes()); got != "` + fmt.Sprintf("%064x", slot) + `" {
+		t.Errorf("unexpected implementation slot %s", got)
+	}
+
+	// Value read with eth_getStorageAt from a proxy
+	value, _ := hex.DecodeString("0000000000000000000000005aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	implementation, err := AddressFromStorage(value)
+	if err != nil {
+		t.Fatalf("AddressFromStorage failed: %v", err)
+	}
+	if implementation != AddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed") {
+		t.Errorf("unexpected implementation %s", implementation)
+	}
+
+	if _, err := AddressFromStorage(value[1:]); err == nil {
+		t.Error("expected an error for a short storage value")
+	}
+	value[0] = 1
+	if _, err := AddressFromStorage(value); err == nil {
+		t.Error("expected an error for a value with dirty upper bytes")
+	}
+}
+`}
+}
+
+func eventDecodeLogsFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Token.sol:Token": {
+				"abi": [
+					{
+						"type": "event",
+						"name": "Transfer",
+						"inputs": [
+							{"name": "from", "type": "address", "indexed": true, "internalType": "address"},
+							{"name": "to", "type": "address", "indexed": true, "internalType": "address"},
+							{"name": "value", "type": "uint256", "indexed": false, "internalType": "uint256"}
+						],
+						"anonymous": false
+					},
+					{
+						"type": "event",
+						"name": "Approval",
+						"inputs": [
+							{"name": "owner", "type": "address", "indexed": true, "internalType": "address"},
+							{"name": "spender", "type": "address", "indexed": true, "internalType": "address"},
+							{"name": "value", "type": "uint256", "indexed": false, "internalType": "uint256"}
+						],
+						"anonymous": false
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"token": `package token
+
+import "testing"
+
+func word(b byte) []byte {
+	data := make([]byte, 32)
+	data[31] = b
+	return data
+}
+
+func TestDecodeLogs(t *testing.T) {
+	transfer := Events().TransferEventDecoder()
+	approval := Events().ApprovalEventDecoder()
+	alice := Hash{31: 0xa1}
+	bob := Hash{31: 0xb0}
+
+	logs := []RawLog{
+		{Topics: []Hash{transfer.Topic, alice, bob}, Data: word(1)},
+		{Topics: []Hash{approval.Topic, alice, bob}, Data: word(2)},
+		{},
+		{Topics: []Hash{transfer.Topic, bob, alice}, Data: word(3)},
+	}
+
+	transfers, err := transfer.DecodeLogs(logs)
+	if err != nil {
+		t.Fatalf("DecodeLogs failed: %v", err)
+	}
+	if len(transfers) != 2 {
+		t.Fatalf("expected 2 transfers, got %d", len(transfers))
+	}
+	if transfers[0].From[19] != 0xa1 || transfers[0].To[19] != 0xb0 || transfers[0].Value.Int64() != 1 {
+		t.Errorf("unexpected first transfer %+v", transfers[0])
+	}
+	if transfers[1].From[19] != 0xb0 || transfers[1].To[19] != 0xa1 || transfers[1].Value.Int64() != 3 {
+		t.Errorf("unexpected second transfer %+v", transfers[1])
+	}
+
+	approvals, err := approval.DecodeLogs(logs)
+	if err != nil {
+		t.Fatalf("DecodeLogs failed: %v", err)
+	}
+	if len(approvals) != 1 || approvals[0].Value.Int64() != 2 {
+		t.Errorf("unexpected approvals %+v", approvals)
+	}
+
+	// A matching log that fails to decode is reported with its index
+	logs[3].Topics = logs[3].Topics[:2]
+	if _, err := transfer.DecodeLogs(logs); err == nil || err.Error() != "decoding log 3: expected 3 topics for event Transfer, got 2" {
+		t.Errorf("expected an error for log 3, got %v", err)
+	}
+}
+`}
+}
+
+func bytesReturnReaderFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Archive.sol:Archive": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "blob",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "bytes", "internalType": "bytes"}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"blob()": "c05d6ed6"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"archive": `package archive
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// encodeBytesReturn ABI-encodes a single bytes return value
+func encodeBytesReturn(content []byte) []byte {
+	word := func(v int) []byte {
+		data := make([]byte, 32)
+		for i := 31; v > 0; i-- {
+			data[i] = byte(v)
+			v >>= 8
+		}
+		return data
+	}
+	padded := make([]byte, (len(content)+31)/32*32)
+	copy(padded, content)
+	return append(append(word(32), word(len(content))...), padded...)
+}
+
+func TestDecodeReader(t *testing.T) {
+	content := make([]byte, 1<<20)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	encoded := encodeBytesReturn(content)
+
+	r, length, err := Methods().BlobMethod().DecodeReader(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("DecodeReader failed: %v", err)
+	}
+	if length != uint64(len(content)) {
+		t.Fatalf("expected length %d, got %d", len(content), length)
+	}
+	streamed, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading content failed: %v", err)
+	}
+	if !bytes.Equal(streamed, content) {
+		t.Error("streamed content does not match")
+	}
+
+	// The in-memory decoder agrees with the streaming one
+	decoded, err := Methods().BlobMethod().Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !bytes.Equal(decoded, content) {
+		t.Error("decoded content does not match")
+	}
+
+	// Truncated content is reported while streaming
+	r, _, err = Methods().BlobMethod().DecodeReader(bytes.NewReader(encoded[:1000]))
+	if err != nil {
+		t.Fatalf("DecodeReader failed: %v", err)
+	}
+	if _, err := io.ReadAll(r); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+`}
+}
+
+func structErrorParamFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Exchange.sol:Exchange": {
+				"abi": [
+					{
+						"type": "error",
+						"name": "Rejected",
+						"inputs": [{
+							"name": "order",
+							"type": "tuple",
+							"internalType": "struct Exchange.Order",
+							"components": [
+								{"name": "id", "type": "uint256", "internalType": "uint256"},
+								{"name": "maker", "type": "address", "internalType": "address"},
+								{"name": "note", "type": "string", "internalType": "string"}
+							]
+						}]
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	contract := contracts[0]
+	if len(contract.Structs) != 1 || contract.Structs[0].Name != "Order" {
+		t.Fatalf("expected the Order struct to be registered from the error, got %+v", contract.Structs)
+	}
+	if param := contract.Errors[0].Inputs[0]; param.Type.TypeName != "Order" || !param.Type.IsStruct {
+		t.Fatalf("expected an Order struct error parameter, got %+v", param.Type)
+	}
+
+	orderType, err := abi.NewType("tuple", "struct Exchange.Order", []abi.ArgumentMarshaling{
+		{Name: "id", Type: "uint256"},
+		{Name: "maker", Type: "address"},
+		{Name: "note", Type: "string"},
+	})
+	if err != nil {
+		t.Fatalf("failed to build tuple type: %v", err)
+	}
+	order := struct {
+		Id    *big.Int
+		Maker common.Address
+		Note  string
+	}{big.NewInt(9), common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"), "price moved"}
+	encoded, err := abi.Arguments{{Type: orderType}}.Pack(order)
+	if err != nil {
+		t.Fatalf("failed to encode order: %v", err)
+	}
+	revertData := append(crypto.Keccak256([]byte("Rejected((uint256,address,string))"))[:4], encoded...)
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"exchange": `package exchange
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestStructErrorDecode(t *testing.T) {
+	data, _ := hex.DecodeString("` + hex.EncodeToString(revertData) + `")
+
+	rejected, err := Errors().RejectedError().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if rejected.Order.Id.Int64() != 9 {
+		t.Errorf("expected id 9, got %s", rejected.Order.Id)
+	}
+	if rejected.Order.Maker != AddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed") {
+		t.Errorf("unexpected maker %s", rejected.Order.Maker)
+	}
+	if rejected.Order.Note != "price moved" {
+		t.Errorf("unexpected note %q", rejected.Order.Note)
+	}
+}
+`}
+}
+
+func bytes32ArrayReturnFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Merkle.sol:Merkle": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "proof",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "bytes32[]", "internalType": "bytes32[]"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "proofWithRoot",
+						"inputs": [],
+						"outputs": [
+							{"name": "root", "type": "bytes32", "internalType": "bytes32"},
+							{"name": "siblings", "type": "bytes32[]", "internalType": "bytes32[]"},
+							{"name": "index", "type": "uint256", "internalType": "uint256"}
+						],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"proof()": "faf924cf", "proofWithRoot()": "146631e9"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	if typeName := contracts[0].Methods[0].Outputs[0].Type.TypeName; typeName != "[][32]byte" {
+		t.Fatalf("expected bytes32[] to map to [][32]byte, got %s", typeName)
+	}
+
+	bytes32Type, _ := abi.NewType("bytes32", "", nil)
+	bytes32ArrayType, _ := abi.NewType("bytes32[]", "", nil)
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+
+	siblings := [][32]byte{crypto.Keccak256Hash([]byte("a")), crypto.Keccak256Hash([]byte("b")), crypto.Keccak256Hash([]byte("c"))}
+	root := crypto.Keccak256Hash([]byte("root"))
+
+	single, err := abi.Arguments{{Type: bytes32ArrayType}}.Pack(siblings)
+	if err != nil {
+		t.Fatalf("failed to encode proof: %v", err)
+	}
+	multi, err := abi.Arguments{{Type: bytes32Type}, {Type: bytes32ArrayType}, {Type: uint256Type}}.Pack(root, siblings, big.NewInt(5))
+	if err != nil {
+		t.Fatalf("failed to encode proof with root: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"merkle": `package merkle
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestBytes32ArrayDecode(t *testing.T) {
+	siblings := []string{"` + hex.EncodeToString(siblings[0][:]) + `", "` + hex.EncodeToString(siblings[1][:]) + `", "` + hex.EncodeToString(siblings[2][:]) + `"}
+
+	data, _ := hex.DecodeString("` + hex.EncodeToString(single) + `")
+	proof, err := Methods().ProofMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(proof) != len(siblings) {
+		t.Fatalf("expected %d proof elements, got %d", len(siblings), len(proof))
+	}
+	for i, sibling := range siblings {
+		if hex.EncodeToString(proof[i][:]) != sibling {
+			t.Errorf("proof[%d] = %x, expected %s", i, proof[i], sibling)
+		}
+	}
+
+	data, _ = hex.DecodeString("` + hex.EncodeToString(multi) + `")
+	result, err := Methods().ProofWithRootMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if hex.EncodeToString(result.Root[:]) != "` + hex.EncodeToString(root[:]) + `" {
+		t.Errorf("unexpected root %x", result.Root)
+	}
+	if len(result.Siblings) != len(siblings) {
+		t.Fatalf("expected %d siblings, got %d", len(siblings), len(result.Siblings))
+	}
+	for i, sibling := range siblings {
+		if hex.EncodeToString(result.Siblings[i][:]) != sibling {
+			t.Errorf("siblings[%d] = %x, expected %s", i, result.Siblings[i], sibling)
+		}
+	}
+	if result.Index.Int64() != 5 {
+		t.Errorf("expected index 5, got %s", result.Index)
+	}
+}
+`}
+}
+
+func int256ArrayReturnFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Ledger.sol:Ledger": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "deltas",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "int256[]", "internalType": "int256[]"}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"deltas()": "758e3124"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	if outputType := contracts[0].Methods[0].Outputs[0].Type; !outputType.IsSlice || !outputType.IsSigned {
+		t.Fatalf("expected int256[] to be a signed slice, got %+v", outputType)
+	}
+
+	int256ArrayType, _ := abi.NewType("int256[]", "", nil)
+	encoded, err := abi.Arguments{{Type: int256ArrayType}}.Pack([]*big.Int{big.NewInt(-1), big.NewInt(42), big.NewInt(-1000000)})
+	if err != nil {
+		t.Fatalf("failed to encode deltas: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"ledger": `package ledger
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestInt256ArrayDecode(t *testing.T) {
+	data, _ := hex.DecodeString("` + hex.EncodeToString(encoded) + `")
+
+	deltas, err := Methods().DeltasMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	expected := []int64{-1, 42, -1000000}
+	if len(deltas) != len(expected) {
+		t.Fatalf("expected %d deltas, got %d", len(expected), len(deltas))
+	}
+	for i, want := range expected {
+		if deltas[i].Int64() != want {
+			t.Errorf("deltas[%d] = %s, expected %d", i, deltas[i], want)
+		}
+	}
+}
+`}
+}
+
+func negativeSignedReturnsFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Pool.sol:Pool": {
+				"abi": [
+					{"type": "function", "name": "balance", "inputs": [], "outputs": [{"name": "", "type": "int256", "internalType": "int256"}], "stateMutability": "view"},
+					{"type": "function", "name": "liquidity", "inputs": [], "outputs": [{"name": "", "type": "int128", "internalType": "int128"}], "stateMutability": "view"},
+					{"type": "function", "name": "tick", "inputs": [], "outputs": [{"name": "", "type": "int24", "internalType": "int24"}], "stateMutability": "view"}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"balance()": "b69ef8a8", "liquidity()": "1a686502", "tick()": "3eaf5d9f"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	for _, method := range contracts[0].Methods {
+		if outputType := method.Outputs[0].Type; outputType.TypeName != "*big.Int" || !outputType.IsSigned {
+			t.Errorf("%s: expected a signed *big.Int, got %+v", method.Signature, outputType)
+		}
+	}
+
+	// -42 is sign-extended to the full word whatever the integer width
+	encoded := strings.Repeat("ff", 31) + "d6"
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"pool": `package pool
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestNegativeSignedReturns(t *testing.T) {
+	data, _ := hex.DecodeString("` + encoded + `")
+
+	balance, err := Methods().BalanceMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if balance.Int64() != -42 {
+		t.Errorf("expected balance -42, got %s", balance)
+	}
+
+	liquidity, err := Methods().LiquidityMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if liquidity.Int64() != -42 {
+		t.Errorf("expected liquidity -42, got %s", liquidity)
+	}
+
+	tick, err := Methods().TickMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if tick.Int64() != -42 {
+		t.Errorf("expected tick -42, got %s", tick)
+	}
+}
+`}
+}
+
+func int256ArrayDifferentialFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Oracle.sol:Oracle": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "samples",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "int256[]", "internalType": "int256[]"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "window",
+						"inputs": [],
+						"outputs": [
+							{"name": "low", "type": "int128[]", "internalType": "int128[]"},
+							{"name": "high", "type": "int256[]", "internalType": "int256[]"}
+						],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "reading",
+						"inputs": [],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"internalType": "struct Oracle.Reading",
+							"components": [
+								{"name": "round", "type": "uint256", "internalType": "uint256"},
+								{"name": "answers", "type": "int256[]", "internalType": "int256[]"}
+							]
+						}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"samples()": "c6958b89", "window()": "461645bf", "reading()": "fffd52c6"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	minInt256 := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))
+	maxInt256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1))
+	minInt128 := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+	values := []*big.Int{minInt256, big.NewInt(-1), big.NewInt(0), big.NewInt(1), maxInt256, big.NewInt(-123456789)}
+	low := []*big.Int{minInt128, big.NewInt(-2), big.NewInt(7)}
+
+	int256ArrayType, _ := abi.NewType("int256[]", "", nil)
+	int128ArrayType, _ := abi.NewType("int128[]", "", nil)
+	readingType, err := abi.NewType("tuple", "struct Oracle.Reading", []abi.ArgumentMarshaling{
+		{Name: "round", Type: "uint256"},
+		{Name: "answers", Type: "int256[]"},
+	})
+	if err != nil {
+		t.Fatalf("failed to build tuple type: %v", err)
+	}
+
+	samplesArgs := abi.Arguments{{Type: int256ArrayType}}
+	windowArgs := abi.Arguments{{Name: "low", Type: int128ArrayType}, {Name: "high", Type: int256ArrayType}}
+	readingArgs := abi.Arguments{{Type: readingType}}
+
+	samples, err := samplesArgs.Pack(values)
+	if err != nil {
+		t.Fatalf("failed to encode samples: %v", err)
+	}
+	window, err := windowArgs.Pack(low, values)
+	if err != nil {
+		t.Fatalf("failed to encode window: %v", err)
+	}
+	reading, err := readingArgs.Pack(struct {
+		Round   *big.Int
+		Answers []*big.Int
+	}{big.NewInt(3), values})
+	if err != nil {
+		t.Fatalf("failed to encode reading: %v", err)
+	}
+
+	// go-ethereum's own decoding is the reference the generated decoders must agree with
+	quoted := func(nums []*big.Int) string {
+		parts := make([]string, len(nums))
+		for i, n := range nums {
+			parts[i] = `"` + n.String() + `"`
+		}
+		return "[]string{" + strings.Join(parts, ", ") + "}"
+	}
+	unpacked, err := samplesArgs.Unpack(samples)
+	if err != nil {
+		t.Fatalf("go-ethereum failed to decode samples: %v", err)
+	}
+	expectedSamples := quoted(unpacked[0].([]*big.Int))
+	unpacked, err = windowArgs.Unpack(window)
+	if err != nil {
+		t.Fatalf("go-ethereum failed to decode window: %v", err)
+	}
+	expectedLow, expectedHigh := quoted(unpacked[0].([]*big.Int)), quoted(unpacked[1].([]*big.Int))
+	unpacked, err = readingArgs.Unpack(reading)
+	if err != nil {
+		t.Fatalf("go-ethereum failed to decode reading: %v", err)
+	}
+	expectedAnswers := quoted(unpacked[0].(struct {
+		Round   *big.Int   `json:"round"`
+		Answers []*big.Int `json:"answers"`
+	}).Answers)
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"oracle": `package oracle
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func assertInts(t *testing.T, name string, got []*big.Int, expected []string) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("%s: expected %d values, got %d", name, len(expected), len(got))
+	}
+	for i, want := range expected {
+		if got[i].String() != want {
+			t.Errorf("%s[%d] = %s, expected %s", name, i, got[i], want)
+		}
+	}
+}
+
+func TestInt256ArrayDifferential(t *testing.T) {
+	data, _ := hex.DecodeString("` + hex.EncodeToString(samples) + `")
+	samples, err := Methods().SamplesMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("decoding samples failed: %v", err)
+	}
+	assertInts(t, "samples", samples, ` + expectedSamples + `)
+
+	data, _ = hex.DecodeString("` + hex.EncodeToString(window) + `")
+	window, err := Methods().WindowMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("decoding window failed: %v", err)
+	}
+	assertInts(t, "low", window.Low, ` + expectedLow + `)
+	assertInts(t, "high", window.High, ` + expectedHigh + `)
+
+	data, _ = hex.DecodeString("` + hex.EncodeToString(reading) + `")
+	reading, err := Methods().ReadingMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("decoding reading failed: %v", err)
+	}
+	assertInts(t, "answers", reading.Answers, ` + expectedAnswers + `)
+}
+`}
+}
+
+func errorSelectorConstantsFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{
+						"type": "error",
+						"name": "InsufficientBalance",
+						"inputs": [
+							{"name": "available", "type": "uint256", "internalType": "uint256"},
+							{"name": "required", "type": "uint256", "internalType": "uint256"}
+						]
+					},
+					{"type": "error", "name": "Unauthorized", "inputs": [{"name": "caller", "type": "address", "internalType": "address"}]}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	insufficientSelector := crypto.Keccak256([]byte("InsufficientBalance(uint256,uint256)"))[:4]
+	unauthorizedSelector := crypto.Keccak256([]byte("Unauthorized(address)"))[:4]
+
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+	encoded, err := abi.Arguments{{Type: uint256Type}, {Type: uint256Type}}.Pack(big.NewInt(10), big.NewInt(25))
+	if err != nil {
+		t.Fatalf("failed to encode error data: %v", err)
+	}
+	revertData := append(append([]byte{}, insufficientSelector...), encoded...)
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"vault": `package vault
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestErrorSelectorConstants(t *testing.T) {
+	if ErrorSelectorInsufficientBalance != "0x` + hex.EncodeToString(insufficientSelector) + `" {
+		t.Errorf("unexpected InsufficientBalance selector %s", ErrorSelectorInsufficientBalance)
+	}
+	if ErrorSelectorUnauthorized != "0x` + hex.EncodeToString(unauthorizedSelector) + `" {
+		t.Errorf("unexpected Unauthorized selector %s", ErrorSelectorUnauthorized)
+	}
+	if ErrorSelectorUnauthorized != Errors().UnauthorizedError().Selector {
+		t.Error("the selector constant should match the registry")
+	}
+
+	data, _ := hex.DecodeString("` + hex.EncodeToString(revertData) + `")
+	switch HexData("0x" + hex.EncodeToString(data[:4])) {
+	case ErrorSelectorInsufficientBalance:
+	default:
+		t.Fatalf("revert data not routed to InsufficientBalance")
+	}
+
+	decoded, err := DecodeError(data)
+	if err != nil {
+		t.Fatalf("DecodeError failed: %v", err)
+	}
+	insufficient, ok := decoded.(InsufficientBalanceError)
+	if !ok {
+		t.Fatalf("expected an InsufficientBalanceError, got %T", decoded)
+	}
+	if insufficient.Available.Int64() != 10 || insufficient.Required.Int64() != 25 {
+		t.Errorf("unexpected error values %s %s", insufficient.Available, insufficient.Required)
+	}
+
+	if _, err := DecodeError([]byte{0xde, 0xad, 0xbe, 0xef}); err == nil {
+		t.Error("expected an unknown selector to be rejected")
+	}
+}
+`}
+}
+
+func fixedBytesArrayEventAndErrorParamsFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Prover.sol:Prover": {
+				"abi": [
+					{
+						"type": "event",
+						"name": "Roots",
+						"inputs": [
+							{"name": "tags", "type": "bytes4[]", "indexed": false, "internalType": "bytes4[]"},
+							{"name": "leaves", "type": "bytes32[]", "indexed": false, "internalType": "bytes32[]"}
+						],
+						"anonymous": false
+					},
+					{
+						"type": "error",
+						"name": "BadProof",
+						"inputs": [
+							{"name": "pair", "type": "bytes32[2]", "internalType": "bytes32[2]"},
+							{"name": "index", "type": "uint256", "internalType": "uint256"}
+						]
+					}
+				],
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"prover": `package prover
+
+import "testing"
+
+func word(b ...byte) []byte {
+	w := make([]byte, 32)
+	copy(w, b)
+	return w
+}
+
+func uintWord(v byte) []byte {
+	w := make([]byte, 32)
+	w[31] = v
+	return w
+}
+
+func concat(words ...[]byte) []byte {
+	var data []byte
+	for _, w := range words {
+		data = append(data, w...)
+	}
+	return data
+}
+
+func TestFixedBytesArrayParams(t *testing.T) {
+	// tags at 64: [0xa9059cbb], leaves at 128: [0x11.., 0x22..]
+	data := concat(uintWord(64), uintWord(128),
+		uintWord(1), word(0xa9, 0x05, 0x9c, 0xbb),
+		uintWord(2), word(0x11), word(0x22))
+	event, err := Events().RootsEventDecoder().Decode(data)
+	if err != nil {
+		t.Fatalf("decoding Roots: %v", err)
+	}
+	if len(event.Tags) != 1 || event.Tags[0] != [4]byte{0xa9, 0x05, 0x9c, 0xbb} {
+		t.Errorf("unexpected tags %x", event.Tags)
+	}
+	if len(event.Leaves) != 2 || event.Leaves[0][0] != 0x11 || event.Leaves[1][0] != 0x22 {
+		t.Errorf("unexpected leaves %x", event.Leaves)
+	}
+
+	// bytes32[2] is stored in place, so index follows in the third word
+	errorData := concat([]byte{0, 0, 0, 0}, word(0xaa), word(0xbb), uintWord(7))
+	proof, err := Errors().BadProofError().Decode(errorData)
+	if err != nil {
+		t.Fatalf("decoding BadProof: %v", err)
+	}
+	if proof.Pair[0][0] != 0xaa || proof.Pair[1][0] != 0xbb || proof.Index.Int64() != 7 {
+		t.Errorf("unexpected proof %+v", proof)
+	}
+
+	// Arrays running past the data are rejected
+	if _, err := Events().RootsEventDecoder().Decode(data[:len(data)-32]); err == nil {
+		t.Error("expected an error for truncated leaves")
+	}
+	if _, err := Errors().BadProofError().Decode(errorData[:4+32]); err == nil {
+		t.Error("expected an error for a truncated pair")
+	}
+}
+`}
+}
+
+func erc20TransferFilterFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"SimpleToken.sol:SimpleToken": {
+				"abi": [
+					{
+						"type": "event",
+						"name": "Transfer",
+						"inputs": [
+							{"name": "from", "type": "address", "indexed": true},
+							{"name": "to", "type": "address", "indexed": true},
+							{"name": "value", "type": "uint256", "indexed": false}
+						],
+						"anonymous": false
+					},
+					{
+						"type": "event",
+						"name": "Approval",
+						"inputs": [
+							{"name": "owner", "type": "address", "indexed": true},
+							{"name": "spender", "type": "address", "indexed": true},
+							{"name": "value", "type": "uint256", "indexed": false}
+						],
+						"anonymous": false
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {}
+			},
+			"Collectible.sol:Collectible": {
+				"abi": [
+					{
+						"type": "event",
+						"name": "Transfer",
+						"inputs": [
+							{"name": "from", "type": "address", "indexed": true},
+							{"name": "to", "type": "address", "indexed": true},
+							{"name": "tokenId", "type": "uint256", "indexed": true}
+						],
+						"anonymous": false
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	// The ERC721 Transfer indexes its token id and gets no ERC20 filter
+	collectible, err := os.ReadFile(filepath.Join(outputDir, "collectible", "collectible.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated code: %v", err)
+	}
+	if strings.Contains(string(collectible), "TransferFilter") {
+		t.Error("expected no TransferFilter for the ERC721 Transfer event")
+	}
+
+	return map[string]string{"simpletoken": `package simpletoken
+
+import "testing"
+
+func TestTransferFilter(t *testing.T) {
+	transfer := Events().TransferEventDecoder()
+	alice := AddressFromHex("0x00000000000000000000000000000000000000a1")
+	aliceTopic := HashFromHex("0x00000000000000000000000000000000000000000000000000000000000000a1")
+
+	topics := transfer.TransferFilter(&alice, nil)
+	if len(topics) != 3 {
+		t.Fatalf("expected 3 topics, got %d", len(topics))
+	}
+	if len(topics[0]) != 1 || topics[0][0] != transfer.SignatureHash() {
+		t.Errorf("unexpected event topic %v", topics[0])
+	}
+	if len(topics[1]) != 1 || topics[1][0] != aliceTopic {
+		t.Errorf("unexpected from topic %v", topics[1])
+	}
+	if topics[2] != nil {
+		t.Errorf("expected a nil to topic matching any address, got %v", topics[2])
+	}
+
+	approval := Events().ApprovalEventDecoder()
+	topics = approval.ApprovalFilter(nil, &alice)
+	if topics[0][0] != approval.Topic || topics[1] != nil || topics[2][0] != aliceTopic {
+		t.Errorf("unexpected approval topics %v", topics)
+	}
+}
+`}
+}
+
+// strictDecodeFixture checks that the same dirty words decode by default and
+// fail with --strict-decode
+func strictDecodeFixture(t *testing.T, outputDir string, strict bool) map[string]string {
+	input := `{
+		"contracts": {
+			"Registry.sol:Registry": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "owner",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "address"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "paused",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "bool"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "event",
+						"name": "OwnerSet",
+						"inputs": [{"name": "owner", "type": "address", "indexed": true}],
+						"anonymous": false
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"owner()": "8da5cb5b", "paused()": "5c975abb"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{StrictDecode: strict}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"registry": fmt.Sprintf(`package registry
+
+import "testing"
+
+const strict = %t
+
+func word(b byte) []byte {
+	data := make([]byte, 32)
+	data[31] = b
+	return data
+}
+
+func TestStrictDecode(t *testing.T) {
+	dirtyAddress := word(0xa1)
+	dirtyAddress[0] = 0xff
+	dirtyBool := word(1)
+	dirtyBool[0] = 0x01
+
+	ownerSet := Events().OwnerSetEventDecoder()
+	_, addressErr := Methods().OwnerMethod().Decode(dirtyAddress)
+	_, boolErr := Methods().PausedMethod().Decode(word(2))
+	_, paddedBoolErr := Methods().PausedMethod().Decode(dirtyBool)
+	_, topicErr := ownerSet.DecodeLog([]Hash{ownerSet.Topic, Hash(dirtyAddress)}, nil)
+
+	for name, err := range map[string]error{
+		"address with dirty padding":         addressErr,
+		"bool of 2":                          boolErr,
+		"bool with dirty padding":            paddedBoolErr,
+		"indexed address with dirty padding": topicErr,
+	} {
+		if strict && err == nil {
+			t.Errorf("%%s: expected an error in strict mode", name)
+		}
+		if !strict && err != nil {
+			t.Errorf("%%s: unexpected error %%v", name, err)
+		}
+	}
+
+	// Clean values decode in both modes
+	owner, err := Methods().OwnerMethod().Decode(word(0xa1))
+	if err != nil || owner[19] != 0xa1 {
+		t.Errorf("unexpected owner %%v (%%v)", owner, err)
+	}
+	paused, err := Methods().PausedMethod().Decode(word(1))
+	if err != nil || !paused {
+		t.Errorf("unexpected paused %%v (%%v)", paused, err)
+	}
+}
+`, strict)}
+}
+
+// eip712Domain returns the Permit contract and an in-package test decoding its
+// eip712Domain() return data and checking the separator against go-ethereum
+func eip712Domain(t *testing.T) ([]*types.Contract, string) {
+	input := `{
+		"contracts": {
+			"Permit.sol:Permit": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "eip712Domain",
+						"inputs": [],
+						"outputs": [
+							{"name": "fields", "type": "bytes1", "internalType": "bytes1"},
+							{"name": "name", "type": "string", "internalType": "string"},
+							{"name": "version", "type": "string", "internalType": "string"},
+							{"name": "chainId", "type": "uint256", "internalType": "uint256"},
+							{"name": "verifyingContract", "type": "address", "internalType": "address"},
+							{"name": "salt", "type": "bytes32", "internalType": "bytes32"},
+							{"name": "extensions", "type": "uint256[]", "internalType": "uint256[]"}
+						],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"eip712Domain()": "84b0196e"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	// Return data of eip712Domain() for a domain using name, version, chainId
+	// and verifyingContract, with the separator go-ethereum computes for it
+	verifyingContract := common.HexToAddress("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")
+	var outputs abi.Arguments
+	for _, typ := range []string{"bytes1", "string", "string", "uint256", "address", "bytes32", "uint256[]"} {
+		abiType, err := abi.NewType(typ, "", nil)
+		if err != nil {
+			t.Fatalf("failed to build type %s: %v", typ, err)
+		}
+		outputs = append(outputs, abi.Argument{Type: abiType})
+	}
+	data, err := outputs.Pack([1]byte{0x0f}, "Permit Token", "1", big.NewInt(1), verifyingContract, [32]byte{}, []*big.Int{})
+	if err != nil {
+		t.Fatalf("failed to encode the domain: %v", err)
+	}
+	typeHash := crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	separator := crypto.Keccak256Hash(typeHash, crypto.Keccak256([]byte("Permit Token")), crypto.Keccak256([]byte("1")),
+		common.LeftPadBytes(big.NewInt(1).Bytes(), 32), common.LeftPadBytes(verifyingContract.Bytes(), 32))
+
+	generatedTest := fmt.Sprintf(`package permit
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestEIP712Domain(t *testing.T) {
+	data, _ := hex.DecodeString(%q)
+	domain, err := Methods().Eip712DomainMethod().DecodeDomain(data)
+	if err != nil {
+		t.Fatalf("DecodeDomain failed: %%v", err)
+	}
+	if domain.Fields != 0x0f || domain.Name != "Permit Token" || domain.Version != "1" || domain.ChainId.Int64() != 1 {
+		t.Errorf("unexpected domain %%+v", domain)
+	}
+	if domain.VerifyingContract != AddressFromHex("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC") {
+		t.Errorf("unexpected verifying contract %%s", domain.VerifyingContract)
+	}
+
+	separator, err := domain.Separator()
+	if err != nil {
+		t.Fatalf("Separator failed: %%v", err)
+	}
+	if separator != HashFromHex(%q) {
+		t.Errorf("unexpected separator %%s", separator)
+	}
+
+	// Only the flagged fields are part of the domain
+	domain.Salt[0] = 1
+	if salted, _ := domain.Separator(); salted != separator {
+		t.Error("expected the unflagged salt to be left out of the separator")
+	}
+}
+`, hex.EncodeToString(data), separator.Hex())
+	return contracts, generatedTest
+}
+
+// eip712DomainFixture hashes the separator with the dependency-free Keccak256
+func eip712DomainFixture(t *testing.T, outputDir string) map[string]string {
+	contracts, generatedTest := eip712Domain(t)
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{WithKeccak: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+	return map[string]string{"permit": generatedTest}
+}
+
+// eip712DomainNoKeccakFixture checks that without a Keccak-256 the domain is
+// decoded but has no Separator
+func eip712DomainNoKeccakFixture(t *testing.T, outputDir string) map[string]string {
+	contracts, _ := eip712Domain(t)
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(outputDir, "permit", "permit.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated code: %v", err)
+	}
+	if !strings.Contains(string(content), "DecodeDomain(data []byte) (EIP712Domain, error)") || strings.Contains(string(content), "Separator()") {
+		t.Error("expected DecodeDomain without Separator")
+	}
+	return nil
+}
+
+// TestDecode_EIP712Domain hashes the separator with go-ethereum, which needs a
+// module of its own
+func TestDecode_EIP712Domain(t *testing.T) {
+	contracts, generatedTest := eip712Domain(t)
+	outputDir := t.TempDir()
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{WithBind: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+	writeGeneratedTests(t, outputDir, map[string]string{"permit": generatedTest})
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+// methodArgsFixture checks that Args describes the method arguments with
+// their Solidity names, ABI types and generated Go types
+func methodArgsFixture(t *testing.T, outputDir string) map[string]string {
+	contracts, err := processCombinedJSON([]byte(bindTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"simpletoken": `package simpletoken
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	want := []ArgDescriptor{
+		{Name: "to", Type: "address", GoType: "Address"},
+		{Name: "amount", Type: "uint256", GoType: "*big.Int"},
+	}
+	if args := Methods().TransferMethod().Args(); !reflect.DeepEqual(args, want) {
+		t.Errorf("expected %+v, got %+v", want, args)
+	}
+}
+`}
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/otherview/solgen/internal/gen"
+	"github.com/otherview/solgen/internal/input"
+	"github.com/otherview/solgen/internal/parse"
+)
+
+func TestEtherscan_ABIOnlyBindings(t *testing.T) {
+	// Sample getabi response, the ABI is a JSON encoded string inside "result"
+	response := `{
+		"status": "1",
+		"message": "OK",
+		"result": "[{\"constant\":true,\"inputs\":[{\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"to\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"}]"
+	}`
+
+	result, err := input.Etherscan([]byte(response), "Token")
+	if err != nil {
+		t.Fatalf("input.Etherscan failed: %v", err)
+	}
+
+	contracts, err := parse.ResultWithVersion(result, "unknown")
+	if err != nil {
+		t.Fatalf("parse.ResultWithVersion failed: %v", err)
+	}
+	if len(contracts) != 1 {
+		t.Fatalf("expected 1 contract, got %d", len(contracts))
+	}
+
+	contract := contracts[0]
+	if contract.Name != "Token" || contract.PackageName != "token" {
+		t.Errorf("unexpected contract naming: name %q, package %q", contract.Name, contract.PackageName)
+	}
+	if contract.Bytecode != "" || contract.DeployedBytecode != "" {
+		t.Errorf("ABI-only contract should have no bytecode")
+	}
+
+	// Selectors are derived from the ABI when no compiler hashes are available
+	selectors := map[string]string{"transfer": "0xa9059cbb", "balanceOf": "0x70a08231"}
+	for _, method := range contract.Methods {
+		if want := selectors[method.Name]; string(method.Selector) != want {
+			t.Errorf("method %s: expected selector %s, got %s", method.Name, want, method.Selector)
+		}
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "token", "token.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+	if !strings.Contains(contentStr, "func ABI() string") {
+		t.Error("generated file should contain the ABI accessor")
+	}
+	if strings.Contains(contentStr, "var Bytecode") {
+		t.Error("ABI-only bindings should not declare Bytecode")
+	}
+
+	if err := testGeneratedCode(t, outputDir); err != nil {
+		t.Errorf("generated code compilation failed: %v", err)
+	}
+}
+
+func TestEtherscan_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		contract string
+		wantErr  string
+	}{
+		{
+			name:     "not verified",
+			response: `{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`,
+			contract: "Token",
+			wantErr:  "Contract source code not verified",
+		},
+		{
+			name:     "invalid ABI string",
+			response: `{"status":"1","message":"OK","result":"not json"}`,
+			contract: "Token",
+			wantErr:  "not a valid ABI",
+		},
+		{
+			name:     "missing contract name",
+			response: `{"status":"1","message":"OK","result":"[]"}`,
+			wantErr:  "contract name is required",
+		},
+		{
+			name:     "invalid envelope",
+			response: `{invalid}`,
+			contract: "Token",
+			wantErr:  "parsing etherscan response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := input.Etherscan([]byte(tt.response), tt.contract)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,1054 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/otherview/solgen/internal/gen"
+	"github.com/otherview/solgen/internal/types"
+)
+
+// generatorTestInput holds two minimal contracts for generator API tests
+const generatorTestInput = `{
+	"contracts": {
+		"Token.sol:Token": {
+			"abi": [
+				{
+					"type": "function",
+					"name": "totalSupply",
+					"inputs": [],
+					"outputs": [{"name": "", "type": "uint256"}],
+					"stateMutability": "view"
+				}
+			],
+			"bin": "0x608060405234801561001057600080fd5b50",
+			"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+			"hashes": {"totalSupply()": "18160ddd"}
+		},
+		"Registry.sol:Name_Registry": {
+			"abi": [
+				{
+					"type": "function",
+					"name": "owner",
+					"inputs": [],
+					"outputs": [{"name": "", "type": "address"}],
+					"stateMutability": "view"
+				}
+			],
+			"bin": "0x608060405234801561001057600080fd5b50",
+			"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+			"hashes": {"owner()": "8da5cb5b"}
+		}
+	}
+}`
+
+// recordingHooks records every generator callback
+type recordingHooks struct {
+	contracts map[string][]string // contract name -> reported package names
+	files     []string
+}
+
+func (h *recordingHooks) OnContract(name, packageName string) {
+	h.contracts[name] = append(h.contracts[name], packageName)
+}
+
+func (h *recordingHooks) OnFileWritten(path string) {
+	h.files = append(h.files, path)
+}
+
+func TestGenerator_Hooks(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(generatorTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		options      gen.Options
+		wantPackages map[string]string
+		wantFiles    []string
+	}{
+		{
+			name:         "package per contract",
+			options:      gen.Options{},
+			wantPackages: map[string]string{"Token": "token", "Name_Registry": "nameregistry"},
+			wantFiles:    []string{filepath.Join("nameregistry", "nameregistry.go"), filepath.Join("token", "token.go")},
+		},
+		{
+			name:         "single file",
+			options:      gen.Options{SingleFile: true, PackageName: "bindings"},
+			wantPackages: map[string]string{"Token": "bindings", "Name_Registry": "bindings"},
+			wantFiles:    []string{filepath.Join("bindings", "bindings.go")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := &recordingHooks{contracts: make(map[string][]string)}
+			outputDir := t.TempDir()
+			tt.options.Hooks = hooks
+
+			if err := gen.NewGeneratorWithOptions(outputDir, tt.options).Generate(contracts); err != nil {
+				t.Fatalf("code generation failed: %v", err)
+			}
+
+			if len(hooks.contracts) != len(tt.wantPackages) {
+				t.Errorf("expected OnContract for %d contracts, got %v", len(tt.wantPackages), hooks.contracts)
+			}
+			for name, wantPackage := range tt.wantPackages {
+				got := hooks.contracts[name]
+				if len(got) != 1 || got[0] != wantPackage {
+					t.Errorf("contract %s: expected a single OnContract call with package %q, got %v", name, wantPackage, got)
+				}
+			}
+
+			if len(hooks.files) != len(tt.wantFiles) {
+				t.Fatalf("expected %d written files, got %v", len(tt.wantFiles), hooks.files)
+			}
+			for i, file := range tt.wantFiles {
+				if want := filepath.Join(outputDir, file); hooks.files[i] != want {
+					t.Errorf("file %d: expected %s, got %s", i, want, hooks.files[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Manifest(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(generatorTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	generator := gen.NewGeneratorWithOptions(outputDir, gen.Options{Manifest: "manifest.txt"})
+	written, err := generator.GenerateWithManifest(contracts)
+	if err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	// The returned paths must match the generated files on disk
+	var onDisk []string
+	err = filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".go") {
+			onDisk = append(onDisk, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk output directory: %v", err)
+	}
+
+	sorted := append([]string(nil), written...)
+	sort.Strings(sorted)
+	if !reflect.DeepEqual(sorted, onDisk) {
+		t.Errorf("returned paths %v do not match files on disk %v", written, onDisk)
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(outputDir, "manifest.txt"))
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	var wantManifest strings.Builder
+	for _, path := range written {
+		rel, _ := filepath.Rel(outputDir, path)
+		wantManifest.WriteString(filepath.ToSlash(rel) + "\n")
+	}
+	if string(manifest) != wantManifest.String() {
+		t.Errorf("unexpected manifest content:\n%s\nwant:\n%s", manifest, wantManifest.String())
+	}
+}
+
+func TestGenerator_ContractIdentity(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(generatorTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	testFiles := make(map[string]string)
+	for _, contract := range contracts {
+		testFiles[contract.PackageName] = fmt.Sprintf(`package %s
+
+import "testing"
+
+func TestContractIdentity(t *testing.T) {
+	if ContractName != %q {
+		t.Errorf("unexpected contract name %%s", ContractName)
+	}
+	if SourceFile != %q {
+		t.Errorf("unexpected source file %%s", SourceFile)
+	}
+}
+`, contract.PackageName, contract.Name, contract.SourceFile)
+	}
+	writeGeneratedTests(t, outputDir, testFiles)
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+
+	// The single-file package keeps one pair of constants per contract
+	outputDir = t.TempDir()
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{SingleFile: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(outputDir, "bindings", "bindings.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	for _, want := range []string{`const TokenContractName = "Token"`, `const TokenSourceFile = "Token.sol"`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected %q in the single-file package", want)
+		}
+	}
+	if err := testGeneratedCode(t, outputDir); err != nil {
+		t.Errorf("generated code failed to compile: %v", err)
+	}
+}
+
+func TestGenerator_RuntimeOnly(t *testing.T) {
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{"type": "constructor", "inputs": [{"name": "owner", "type": "address"}, {"name": "cap", "type": "uint256"}], "stateMutability": "nonpayable"},
+					{"type": "function", "name": "cap", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"cap()": "355274ea"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{RuntimeOnly: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "vault", "vault.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "var DeployedBytecode = HexData(") {
+		t.Error("runtime-only package should keep DeployedBytecode")
+	}
+	for _, unexpected := range []string{"var Bytecode = ", "ConstructorInput"} {
+		if strings.Contains(string(content), unexpected) {
+			t.Errorf("runtime-only package should not contain %q", unexpected)
+		}
+	}
+
+	// The parsed contract itself is left untouched
+	if contracts[0].Bytecode == "" || contracts[0].Constructor == nil {
+		t.Error("runtime-only generation should not modify the parsed contract")
+	}
+
+	if err := testGeneratedCode(t, outputDir); err != nil {
+		t.Errorf("generated code failed to compile: %v", err)
+	}
+}
+
+func TestGenerator_PruneUnusedStructs(t *testing.T) {
+	// Config and the Limits nested in it are only used by the constructor,
+	// which --runtime-only leaves out
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{
+						"type": "constructor",
+						"inputs": [{
+							"name": "config",
+							"type": "tuple",
+							"internalType": "struct Vault.Config",
+							"components": [
+								{"name": "owner", "type": "address", "internalType": "address"},
+								{"name": "limits", "type": "tuple", "internalType": "struct Vault.Limits", "components": [
+									{"name": "cap", "type": "uint256", "internalType": "uint256"}
+								]}
+							]
+						}],
+						"stateMutability": "nonpayable"
+					},
+					{"type": "function", "name": "cap", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"},
+					{
+						"type": "function",
+						"name": "position",
+						"inputs": [{"name": "id", "type": "uint256"}],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"internalType": "struct Vault.Position",
+							"components": [
+								{"name": "amount", "type": "uint256", "internalType": "uint256"},
+								{"name": "holder", "type": "address", "internalType": "address"}
+							]
+						}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"cap()": "355274ea", "position(uint256)": "f7a95a9e"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	generate := func(options gen.Options) string {
+		t.Helper()
+		outputDir := t.TempDir()
+		if err := gen.NewGeneratorWithOptions(outputDir, options).Generate(contracts); err != nil {
+			t.Fatalf("code generation failed: %v", err)
+		}
+		if err := testGeneratedCode(t, outputDir); err != nil {
+			t.Errorf("generated code failed to compile: %v", err)
+		}
+		content, err := os.ReadFile(filepath.Join(outputDir, "vault", "vault.go"))
+		if err != nil {
+			t.Fatalf("failed to read generated file: %v", err)
+		}
+		return string(content)
+	}
+
+	// Without pruning the unreferenced structs are still generated
+	if content := generate(gen.Options{RuntimeOnly: true}); !strings.Contains(content, "type Config struct") {
+		t.Error("expected Config without --prune-unused-structs")
+	}
+
+	content := generate(gen.Options{RuntimeOnly: true, Prune: true})
+	for _, unexpected := range []string{"type Config struct", "func decodeConfig(", "type Limits struct", "func decodeLimits("} {
+		if strings.Contains(content, unexpected) {
+			t.Errorf("pruned package should not contain %q", unexpected)
+		}
+	}
+	for _, expected := range []string{"type Position struct", "func decodePosition("} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("pruned package should keep %q", expected)
+		}
+	}
+
+	// The constructor refers to Config and Limits when it is generated
+	if content := generate(gen.Options{Prune: true}); !strings.Contains(content, "type Limits struct") {
+		t.Error("expected Limits to be kept for the constructor")
+	}
+
+	if len(contracts[0].Structs) != 3 {
+		t.Errorf("pruning should not modify the parsed contract, got %d structs", len(contracts[0].Structs))
+	}
+}
+
+func TestGenerator_DeployedBytecodeStripped(t *testing.T) {
+	// solc metadata: {"ipfs": <34-byte multihash>, "solc": 0.8.24}, followed by its 2-byte length 0x33
+	code := "6080604052348015600f57600080fd5b50"
+	metadata := "a2646970667358221220" + strings.Repeat("ab", 32) + "64736f6c63430008180033"
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x` + code + metadata + `",
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "vault", "vault.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	for _, want := range []string{
+		`var DeployedBytecode = HexData("0x` + code + metadata + `")`,
+		"func DeployedBytecodeStripped() HexData {\n\treturn DeployedBytecode.StripMetadata()",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected %q in the generated package", want)
+		}
+	}
+}
+
+func TestGenerator_ValidateConstructorArgs(t *testing.T) {
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{"type": "constructor", "inputs": [{"name": "owner", "type": "address"}, {"name": "cap", "type": "uint256"}, {"name": "label", "type": "string"}], "stateMutability": "nonpayable"}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50"
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{WithBind: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"vault": `package vault
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestValidateConstructorArgs(t *testing.T) {
+	owner := AddressFromHex("0x1111111111111111111111111111111111111111")
+	if err := ValidateConstructorArgs(owner, big.NewInt(100), "vault"); err != nil {
+		t.Errorf("valid arguments rejected: %v", err)
+	}
+	if err := ValidateConstructorArgs(owner, uint64(100), "vault"); err != nil {
+		t.Errorf("valid uint64 argument rejected: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args []any
+		want string
+	}{
+		{"negative uint", []any{owner, big.NewInt(-1), "vault"}, "argument 1 (uint256 cap) is negative: -1"},
+		{"negative int64 for uint", []any{owner, int64(-5), "vault"}, "is negative: -5"},
+		{"nil big.Int", []any{owner, (*big.Int)(nil), "vault"}, "argument 1 (uint256 cap) is nil"},
+		{"nil argument", []any{owner, big.NewInt(1), nil}, "argument 2 (string label) is nil"},
+		{"address as string", []any{"0x1111111111111111111111111111111111111111", big.NewInt(1), "vault"}, "must be an Address, got string"},
+		{"missing argument", []any{owner, big.NewInt(1)}, "expects 3 arguments, got 2"},
+	}
+	for _, tt := range tests {
+		err := ValidateConstructorArgs(tt.args...)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// Deploy validates its input before touching the backend
+	_, _, err := Deploy(nil, nil, ConstructorInput{Owner: owner, Cap: big.NewInt(-1), Label: "vault"})
+	if err == nil || !strings.Contains(err.Error(), "is negative") {
+		t.Errorf("expected Deploy to reject a negative cap, got %v", err)
+	}
+}
+`})
+
+	if err := testGeneratedBindCode(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+func TestGenerator_Clean(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(generatorTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	// A previous run embedded the ABIs next to the code
+	outputDir := t.TempDir()
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{EmbedABI: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	files := map[string]string{
+		// Left behind by a contract that has since been renamed
+		"oldtoken/oldtoken.go": "// Code generated by github.com/otherview/solgen. DO NOT EDIT.\n\npackage oldtoken\n\n//go:embed abi.json\nvar abiJSON string\n",
+		"oldtoken/abi.json":    "[]",
+		// Hand-written files, including one next to generated code
+		"token/extra.go": "package token\n\n// Extra is hand-written\nconst Extra = 1\n",
+		"notes.txt":      "keep me\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(outputDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	// Empty directories the generator did not write to are the user's
+	for _, dir := range []string{"scratch", "token/testdata"} {
+		if err := os.MkdirAll(filepath.Join(outputDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+	}
+
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{Clean: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "oldtoken")); !os.IsNotExist(err) {
+		t.Error("stale generated package should have been removed")
+	}
+	for _, removed := range []string{"token/abi.json", "nameregistry/abi.json"} {
+		if _, err := os.Stat(filepath.Join(outputDir, removed)); !os.IsNotExist(err) {
+			t.Errorf("expected the embedded %s to be removed with its code", removed)
+		}
+	}
+	for _, kept := range []string{"token/extra.go", "notes.txt", "token/token.go", "nameregistry/nameregistry.go", "scratch", "token/testdata"} {
+		if _, err := os.Stat(filepath.Join(outputDir, kept)); err != nil {
+			t.Errorf("expected %s to exist: %v", kept, err)
+		}
+	}
+}
+
+func TestGenerator_RuntimePackage(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(generatorTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	options := gen.Options{RuntimePackage: "generated-test/abirt"}
+	if err := gen.NewGeneratorWithOptions(outputDir, options).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	runtime, err := os.ReadFile(filepath.Join(outputDir, "abirt", "abirt.go"))
+	if err != nil {
+		t.Fatalf("failed to read runtime package: %v", err)
+	}
+	for _, expected := range []string{"package abirt", "type Address [20]byte", "func DecodeUint256("} {
+		if !strings.Contains(string(runtime), expected) {
+			t.Errorf("runtime package should contain %q", expected)
+		}
+	}
+
+	for _, pkg := range []string{"token", "nameregistry"} {
+		content, err := os.ReadFile(filepath.Join(outputDir, pkg, pkg+".go"))
+		if err != nil {
+			t.Fatalf("failed to read generated file: %v", err)
+		}
+		if !strings.Contains(string(content), `"generated-test/abirt"`) {
+			t.Errorf("package %s should import the runtime package", pkg)
+		}
+		if !strings.Contains(string(content), "= abirt.Address\n") {
+			t.Errorf("package %s should alias the runtime Address type", pkg)
+		}
+		if strings.Contains(string(content), "type Address [20]byte") {
+			t.Errorf("package %s should not declare its own runtime", pkg)
+		}
+	}
+
+	// Both contract packages share the same Address type
+	writeGeneratedTests(t, outputDir, map[string]string{"token": `package token
+
+import (
+	"testing"
+
+	"generated-test/nameregistry"
+)
+
+func TestSharedAddress(t *testing.T) {
+	var addr nameregistry.Address = AddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	if addr.String() != "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed" {
+		t.Errorf("unexpected address %s", addr)
+	}
+}
+`})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+
+	singleDir := t.TempDir()
+	options.SingleFile = true
+	if err := gen.NewGeneratorWithOptions(singleDir, options).Generate(contracts); err != nil {
+		t.Fatalf("single-file code generation failed: %v", err)
+	}
+	if err := testGeneratedCode(t, singleDir); err != nil {
+		t.Errorf("single-file code importing the runtime package failed to compile: %v", err)
+	}
+}
+
+func TestGenerator_EmptyABI(t *testing.T) {
+	input := `{
+		"contracts": {
+			"MathLib.sol:MathLib": {
+				"abi": [],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "mathlib", "mathlib.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	// Section headers are only emitted when the section has entries
+	for _, unexpected := range []string{"// Method information", "// Event information", "// Error information"} {
+		if strings.Contains(string(content), unexpected) {
+			t.Errorf("generated file should not contain the empty section %q", unexpected)
+		}
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"mathlib": `package mathlib
+
+import "testing"
+
+func TestEmptyRegistries(t *testing.T) {
+	if _, ok := Methods().ByName("anything"); ok {
+		t.Error("expected an empty method registry")
+	}
+	if _, ok := Methods().BySelector([4]byte{0xa9, 0x05, 0x9c, 0xbb}); ok {
+		t.Error("expected an empty method registry")
+	}
+	if len(methodsByName) != 0 || len(methodsBySelector) != 0 {
+		t.Error("expected no indexed methods")
+	}
+	if ABI() != "[]" {
+		t.Errorf("expected an empty ABI, got %s", ABI())
+	}
+}
+`})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+func TestGenerator_NoCustomErrors(t *testing.T) {
+	// Compiled before solc 0.8.4, so the ABI has no error entries
+	input := `{
+		"contracts": {
+			"Counter.sol:Counter": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "count",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "uint256"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "event",
+						"name": "Incremented",
+						"inputs": [{"name": "by", "type": "uint256", "indexed": false}],
+						"anonymous": false
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"count()": "06661abd"}
+			}
+		},
+		"version": "0.7.6+commit.7338295f"
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	for name, options := range map[string]gen.Options{
+		"default":    {},
+		"with-equal": {WithEqual: true},
+		"single":     {SingleFile: true, PackageName: "counter"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			outputDir := t.TempDir()
+			if err := gen.NewGeneratorWithOptions(outputDir, options).Generate(contracts); err != nil {
+				t.Fatalf("code generation failed: %v", err)
+			}
+			if err := testGeneratedCode(t, outputDir); err != nil {
+				t.Errorf("generated code failed to compile: %v", err)
+			}
+		})
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"counter": `package counter
+
+import "testing"
+
+func TestEmptyErrorRegistry(t *testing.T) {
+	var registry ErrorRegistry = Errors()
+	_ = registry
+
+	if _, err := DecodeError([]byte{0x08, 0xc3, 0x79, 0xa0, 0x00}); err == nil {
+		t.Error("expected every selector to be unknown")
+	}
+	if _, err := DecodeError(nil); err == nil {
+		t.Error("expected an error for short revert data")
+	}
+}
+`})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+// BenchmarkGenerate compares generating many contracts with and without gofmt
+func BenchmarkGenerate(b *testing.B) {
+	parsed, err := processCombinedJSON([]byte(generatorTestInput))
+	if err != nil {
+		b.Fatalf("failed to parse input: %v", err)
+	}
+
+	var contracts []*types.Contract
+	for i := 0; i < 50; i++ {
+		for _, contract := range parsed {
+			copied := *contract
+			copied.PackageName = fmt.Sprintf("%s%d", contract.PackageName, i)
+			contracts = append(contracts, &copied)
+		}
+	}
+
+	for _, bm := range []struct {
+		name     string
+		noFormat bool
+	}{
+		{"format", false},
+		{"no-format", true},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			outputDir := b.TempDir()
+			generator := gen.NewGeneratorWithOptions(outputDir, gen.Options{NoFormat: bm.noFormat})
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := generator.Generate(contracts); err != nil {
+					b.Fatalf("generation failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_EmbedABI(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(generatorTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	t.Run("single file", func(t *testing.T) {
+		outputDir := t.TempDir()
+		generator := gen.NewGeneratorWithOptions(outputDir, gen.Options{SingleFile: true, EmbedABI: true})
+		if err := generator.Generate(contracts); err != nil {
+			t.Fatalf("code generation failed: %v", err)
+		}
+		for _, name := range []string{"token.abi.json", "nameregistry.abi.json"} {
+			if _, err := os.Stat(filepath.Join(outputDir, "bindings", name)); err != nil {
+				t.Errorf("expected %s: %v", name, err)
+			}
+		}
+		if err := testGeneratedCode(t, outputDir); err != nil {
+			t.Errorf("generated code failed to compile: %v", err)
+		}
+	})
+
+	outputDir := t.TempDir()
+	written, err := gen.NewGeneratorWithOptions(outputDir, gen.Options{EmbedABI: true}).GenerateWithManifest(contracts)
+	if err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+	if want := filepath.Join(outputDir, "token", "abi.json"); !slices.Contains(written, want) {
+		t.Errorf("expected %s among the written files %v", want, written)
+	}
+
+	abiFile, err := os.ReadFile(filepath.Join(outputDir, "token", "abi.json"))
+	if err != nil {
+		t.Fatalf("expected the ABI file: %v", err)
+	}
+	if string(abiFile) != contracts[0].ABIJson && string(abiFile) != contracts[1].ABIJson {
+		t.Errorf("unexpected ABI file content %s", abiFile)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "token", "token.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "//go:embed abi.json\nvar _abiFS embed.FS") {
+		t.Error("expected the go:embed directive")
+	}
+	if strings.Contains(string(content), `var _abiJSON = "`) {
+		t.Error("expected the ABI not to be inlined")
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"token": `package token
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEmbeddedABI(t *testing.T) {
+	abiFile, err := os.ReadFile("abi.json")
+	if err != nil {
+		t.Fatalf("failed to read abi.json: %v", err)
+	}
+	if ABI() != string(abiFile) {
+		t.Errorf("expected ABI() to return the embedded file, got %s", ABI())
+	}
+}
+`})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+// largeABIInput builds a contract whose ABI has the given number of methods,
+// about 315 bytes each
+func largeABIInput(tb testing.TB, methods int) string {
+	tb.Helper()
+
+	var entries []string
+	hashes := make(map[string]string)
+	for i := 0; i < methods; i++ {
+		name := fmt.Sprintf("method%d", i)
+		entries = append(entries, fmt.Sprintf(`{"type":"function","name":%q,"inputs":[{"name":"owner","type":"address","internalType":"address"},{"name":"amount","type":"uint256","internalType":"uint256"},{"name":"memo","type":"string","internalType":"string"}],"outputs":[{"name":"","type":"bool","internalType":"bool"}],"stateMutability":"nonpayable"}`, name))
+		hashes[name+"(address,uint256,string)"] = fmt.Sprintf("%08x", i+1)
+	}
+	hashesJSON, err := json.Marshal(hashes)
+	if err != nil {
+		tb.Fatalf("failed to marshal hashes: %v", err)
+	}
+	return fmt.Sprintf(`{"contracts": {"Large.sol:Large": {"abi": [%s], "bin": "0x", "bin-runtime": "0x", "hashes": %s}}}`,
+		strings.Join(entries, ","), hashesJSON)
+}
+
+// generatedABI parses the generated file and returns the value of its inlined
+// ABI literal, along with the literal itself
+func generatedABI(t *testing.T, filePath string) (string, string) {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse generated file: %v", err)
+	}
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			if valueSpec.Names[0].Name != "_abiJSON" {
+				continue
+			}
+			lit := valueSpec.Values[0].(*ast.BasicLit)
+			value, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				t.Fatalf("failed to unquote the ABI literal: %v", err)
+			}
+			return value, lit.Value
+		}
+	}
+	t.Fatal("no _abiJSON variable in the generated file")
+	return "", ""
+}
+
+func TestGenerator_LargeABI(t *testing.T) {
+	input := largeABIInput(t, 160)
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	if size := len(contracts[0].ABIJson); size < 50_000 {
+		t.Fatalf("expected an ABI of at least 50KB, got %d bytes", size)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	value, literal := generatedABI(t, filepath.Join(outputDir, "large", "large.go"))
+	if !strings.HasPrefix(literal, "`") {
+		t.Error("expected the ABI as a raw string literal")
+	}
+	if value != contracts[0].ABIJson {
+		t.Error("expected the ABI literal to hold the ABI unchanged")
+	}
+	parsed, err := abi.JSON(strings.NewReader(value))
+	if err != nil {
+		t.Fatalf("generated ABI does not parse: %v", err)
+	}
+	if len(parsed.Methods) != 160 {
+		t.Errorf("expected 160 methods, got %d", len(parsed.Methods))
+	}
+
+	// ABIs that cannot be a raw string fall back to an interpreted literal
+	input = strings.Replace(input, `"stateMutability":"nonpayable"}`, "\"stateMutability\":\"nonpayable\",\"notice\":\"see `docs`\"}", 1)
+	contracts, err = processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+	outputDir = t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+	value, literal = generatedABI(t, filepath.Join(outputDir, "large", "large.go"))
+	if !strings.HasPrefix(literal, `"`) {
+		t.Error("expected an interpreted string literal for an ABI containing a backquote")
+	}
+	if value != contracts[0].ABIJson {
+		t.Error("expected the ABI literal to hold the ABI unchanged")
+	}
+}
+
+func TestGenerator_DeprecatedMethods(t *testing.T) {
+	// Token has a devdoc object, Legacy the JSON encoded string of older solc
+	input := `{
+		"contracts": {
+			"Token.sol:Token": {
+				"abi": [
+					{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "value", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"},
+					{"type": "function", "name": "approve", "inputs": [{"name": "spender", "type": "address"}, {"name": "value", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"}
+				],
+				"hashes": {"transfer(address,uint256)": "a9059cbb", "approve(address,uint256)": "095ea7b3"},
+				"devdoc": {
+					"kind": "dev",
+					"methods": {
+						"transfer(address,uint256)": {"custom:deprecated": "Use safeTransfer instead,\n  it checks the recipient.", "details": "Moves tokens."},
+						"approve(address,uint256)": {"details": "Sets the allowance."}
+					},
+					"version": 1
+				}
+			},
+			"Legacy.sol:Legacy": {
+				"abi": [
+					{"type": "function", "name": "balanceOf", "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"}
+				],
+				"hashes": {"balanceOf(address)": "70a08231"},
+				"devdoc": "{\"methods\":{\"balanceOf(address)\":{\"custom:deprecated\":\"\"}}}"
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	expected := map[string]map[string]string{
+		"token": {
+			"TransferMethod": "Use safeTransfer instead, it checks the recipient.",
+			"ApproveMethod":  "",
+		},
+		"legacy": {
+			"BalanceOfMethod": "marked @custom:deprecated in the contract devdoc.",
+		},
+	}
+	for pkg, accessors := range expected {
+		path := filepath.Join(outputDir, pkg, pkg+".go")
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("failed to parse generated file: %v", err)
+		}
+		docs := make(map[string]string)
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && fn.Doc != nil {
+				docs[fn.Name.Name] = fn.Doc.Text()
+			}
+		}
+		for accessor, notice := range accessors {
+			_, deprecation, found := strings.Cut(docs[accessor], "\nDeprecated: ")
+			if notice == "" {
+				if found {
+					t.Errorf("%s.%s should not be deprecated", pkg, accessor)
+				}
+				continue
+			}
+			if strings.TrimSpace(deprecation) != notice {
+				t.Errorf("%s.%s: expected deprecation %q, got doc %q", pkg, accessor, notice, docs[accessor])
+			}
+		}
+	}
+
+	if err := testGeneratedCode(t, outputDir); err != nil {
+		t.Errorf("generated code failed to compile: %v", err)
+	}
+}
+
+// BenchmarkGenerate_LargeABI generates a contract with a 50KB ABI
+func BenchmarkGenerate_LargeABI(b *testing.B) {
+	contracts, err := processCombinedJSON([]byte(largeABIInput(b, 160)))
+	if err != nil {
+		b.Fatalf("failed to parse input: %v", err)
+	}
+
+	outputDir := b.TempDir()
+	generator := gen.NewGenerator(outputDir)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := generator.Generate(contracts); err != nil {
+			b.Fatalf("generation failed: %v", err)
+		}
+	}
+	b.StopTimer()
+
+	info, err := os.Stat(filepath.Join(outputDir, "large", "large.go"))
+	if err != nil {
+		b.Fatalf("failed to stat generated file: %v", err)
+	}
+	b.ReportMetric(float64(info.Size()), "source-bytes")
+}
@@ -348,4 +348,21 @@ func TestGolden_CompileGenerated(t *testing.T) {
 	if err := testGeneratedCode(t, outputDir); err != nil {
 		t.Errorf("generated code compilation failed: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestGolden_MethodSignatureComments(t *testing.T) {
+	goldenFile := filepath.Join("..", "test", "data", "golden", "simple_contract_simplecontract", "simplecontract.go")
+	content, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
+	}
+
+	for _, expected := range []string{
+		"// getValue() [0x20965255]\nfunc (mr MethodRegistry) GetValueMethod() *GetValueMethod {",
+		"// setValue(uint256) [0x55241077]\nfunc (mr MethodRegistry) SetValueMethod() *SetValueMethod {",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("golden file should document the method accessor with %q", expected)
+		}
+	}
+}
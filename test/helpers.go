@@ -11,12 +11,18 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/otherview/solgen/internal/input"
 	"github.com/otherview/solgen/internal/parse"
 	"github.com/otherview/solgen/internal/types"
 )
 
 // processCombinedJSON parses combined JSON format and returns contracts
 func processCombinedJSON(data []byte) ([]*types.Contract, error) {
+	return processCombinedJSONWithOptions(data, parse.Options{})
+}
+
+// processCombinedJSONWithOptions parses combined JSON format with the given parse options
+func processCombinedJSONWithOptions(data []byte, options parse.Options) ([]*types.Contract, error) {
 	var combined types.CombinedJSON
 	if err := json.Unmarshal(data, &combined); err != nil {
 		return nil, fmt.Errorf("parsing combined JSON: %w", err)
@@ -29,7 +35,7 @@ func processCombinedJSON(data []byte) ([]*types.Contract, error) {
 	}
 
 	// Parse using existing parser
-	contracts, err := parse.ResultWithVersion(result, "0.8.20")
+	contracts, err := parse.ResultWithOptions(result, "0.8.20", options)
 	if err != nil {
 		return nil, fmt.Errorf("parsing contracts: %w", err)
 	}
@@ -60,12 +66,15 @@ func convertCombinedToStandard(combined types.CombinedJSON) (*types.CompileResul
 			ABI: contract.ABI,
 			EVM: types.EVMResult{
 				Bytecode: types.BytecodeResult{
-					Object: contract.Bin,
+					Object:         contract.Bin,
+					LinkReferences: input.LinkReferences(contract.Bin),
 				},
 				DeployedBytecode: types.BytecodeResult{
-					Object: contract.BinRuntime,
+					Object:         contract.BinRuntime,
+					LinkReferences: input.LinkReferences(contract.BinRuntime),
 				},
 			},
+			DevDoc: contract.DevDoc,
 		}
 
 		// Add method identifiers if available
@@ -76,6 +85,14 @@ func convertCombinedToStandard(combined types.CombinedJSON) (*types.CompileResul
 		result.Contracts[filename][contractName] = contractResult
 	}
 
+	// Source ASTs, present when ast was selected, describe the enums
+	for sourceFile, source := range combined.Sources {
+		if result.Sources == nil {
+			result.Sources = make(map[string]types.SourceResult)
+		}
+		result.Sources[sourceFile] = types.SourceResult{AST: source.AST}
+	}
+
 	return result, nil
 }
 
@@ -106,4 +123,112 @@ go 1.21
 		return fmt.Errorf("go build failed: %v\nOutput: %s", err, string(output))
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// writeGeneratedTests adds in-package test files, keyed by package name, next to the generated code
+func writeGeneratedTests(t *testing.T, outputDir string, testFiles map[string]string) {
+	t.Helper()
+	for pkg, testFile := range testFiles {
+		if err := os.WriteFile(filepath.Join(outputDir, pkg, "generated_test.go"), []byte(testFile), 0644); err != nil {
+			t.Fatalf("failed to write generated package test: %v", err)
+		}
+	}
+}
+
+// runGeneratedTests runs the tests placed next to dependency-free generated packages
+func runGeneratedTests(t *testing.T, outputDir string) error {
+	goModContent := `module generated-test
+
+go 1.21
+`
+	if err := os.WriteFile(filepath.Join(outputDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+		return err
+	}
+
+	testCmd := exec.Command("go", "test", "./...")
+	testCmd.Dir = outputDir
+	if output, err := testCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("go test failed: %v\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// generatedFixture generates contracts into outputDir, checking the generated
+// files if needed, and returns the in-package tests to run against them, keyed
+// by package name
+type generatedFixture func(t *testing.T, outputDir string) map[string]string
+
+// runGeneratedFixtures generates each fixture into its own directory of a
+// single module, then builds and tests them all with one go test, which is much
+// faster than a module per fixture
+func runGeneratedFixtures(t *testing.T, fixtures map[string]generatedFixture) {
+	t.Helper()
+	moduleDir := t.TempDir()
+	for name, fixture := range fixtures {
+		outputDir := filepath.Join(moduleDir, name)
+		t.Run(name, func(t *testing.T) {
+			writeGeneratedTests(t, outputDir, fixture(t, outputDir))
+		})
+	}
+	if t.Failed() {
+		return
+	}
+
+	if err := runGeneratedTests(t, moduleDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+// testGeneratedBindCode verifies that code generated with --with-bind compiles
+// against go-ethereum and runs any tests placed next to the generated packages
+func testGeneratedBindCode(t *testing.T, outputDir string) error {
+	// Pin go-ethereum to the version used by solgen itself so its go.sum can be reused
+	goModContent := `module generated-test
+
+go 1.21
+
+require github.com/ethereum/go-ethereum v1.13.5
+`
+	if err := os.WriteFile(filepath.Join(outputDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+		return err
+	}
+
+	goSum, err := os.ReadFile(filepath.Join("..", "go.sum"))
+	if err != nil {
+		return fmt.Errorf("reading go.sum: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "go.sum"), goSum, 0644); err != nil {
+		return err
+	}
+
+	// -mod=mod resolves only the modules the generated packages import, go mod tidy
+	// would fetch the test dependencies of all of go-ethereum
+	testCmd := exec.Command("go", "test", "-mod=mod", "./...")
+	testCmd.Dir = outputDir
+	if output, err := testCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("go test failed: %v\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// buildSolgen builds the solgen CLI into a temporary directory and returns its path
+func buildSolgen(t *testing.T) string {
+	t.Helper()
+	binaryPath := filepath.Join(t.TempDir(), "solgen")
+	projectRoot, _ := filepath.Abs("..")
+
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/solgen")
+	buildCmd.Dir = projectRoot
+	if output, err := buildCmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build solgen binary: %v\nOutput: %s", err, string(output))
+	}
+	return binaryPath
+}
+
+// runSolgen runs the solgen binary with the given stdin and arguments and returns its combined output
+func runSolgen(binaryPath, stdin string, args ...string) (string, error) {
+	cmd := exec.Command(binaryPath, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
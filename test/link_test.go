@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"testing"
+
+	"github.com/otherview/solgen/internal/input"
+)
+
+func TestLinkReferences_FromPlaceholders(t *testing.T) {
+	// Same placeholder bytecode as the CLI test: one byte, the placeholder, one byte
+	refs := input.LinkReferences("0x73__$libraryPlaceholder$__73")
+	libRefs := refs[""]["libraryPlaceholder"]
+	if len(libRefs) != 1 || libRefs[0].Start != 1 || libRefs[0].Length != 12 {
+		t.Errorf("unexpected link references: %+v", refs)
+	}
+
+	// Legacy placeholders name the library source file and contract
+	legacy := input.LinkReferences("6080__contracts/Math.sol:MathLib____________6000")
+	mathRefs := legacy["contracts/Math.sol"]["MathLib"]
+	if len(mathRefs) != 1 || mathRefs[0].Start != 2 || mathRefs[0].Length != 20 {
+		t.Errorf("unexpected legacy link references: %+v", legacy)
+	}
+
+	if refs := input.LinkReferences("0x608060405234801561001057600080fd5b50"); refs != nil {
+		t.Errorf("expected no link references for linked bytecode, got %+v", refs)
+	}
+}
+
+func TestLinkReferences_CombinedConstructor(t *testing.T) {
+	combined := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{"type": "constructor", "inputs": [], "stateMutability": "nonpayable"}
+				],
+				"bin": "0x73__$libraryPlaceholder$__73",
+				"bin-runtime": "0x73__$libraryPlaceholder$__73"
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(combined))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	constructor := contracts[0].Constructor
+	if constructor == nil {
+		t.Fatal("expected a constructor")
+	}
+	refs := constructor.LinkReferences["libraryPlaceholder"]
+	if len(refs) != 1 || refs[0].Start != 1 || refs[0].Length != 12 {
+		t.Errorf("unexpected constructor link references: %+v", constructor.LinkReferences)
+	}
+}
@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/otherview/solgen/internal/gen"
+)
+
+func TestMulticall_Encode(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(bindTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	// Reference encoding of the two transfer calls as a bytes[] argument
+	transferSelector := []byte{0xa9, 0x05, 0x9c, 0xbb}
+	addressType, _ := abi.NewType("address", "", nil)
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+	transferArgs := abi.Arguments{{Type: addressType}, {Type: uint256Type}}
+	first, err := transferArgs.Pack(common.HexToAddress("0x1111111111111111111111111111111111111111"), big.NewInt(100))
+	if err != nil {
+		t.Fatalf("failed to pack transfer: %v", err)
+	}
+	second, err := transferArgs.Pack(common.HexToAddress("0x2222222222222222222222222222222222222222"), big.NewInt(200))
+	if err != nil {
+		t.Fatalf("failed to pack transfer: %v", err)
+	}
+	bytesArrayType, _ := abi.NewType("bytes[]", "", nil)
+	expected, err := abi.Arguments{{Type: bytesArrayType}}.Pack([][]byte{
+		append(transferSelector, first...),
+		append(transferSelector, second...),
+	})
+	if err != nil {
+		t.Fatalf("failed to pack bytes[]: %v", err)
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"simpletoken": fmt.Sprintf(`package simpletoken
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestEncodeMulticall(t *testing.T) {
+	first := Methods().TransferMethod().MustPack(AddressFromHex("0x1111111111111111111111111111111111111111"), big.NewInt(100))
+	second := Methods().TransferMethod().MustPack(AddressFromHex("0x2222222222222222222222222222222222222222"), big.NewInt(200))
+
+	encoded := EncodeMulticall(first, second)
+	if encoded != "0x%s" {
+		t.Errorf("unexpected bytes[] encoding %%s", encoded)
+	}
+
+	// Head: offset of the array, then its length and the offsets of both calls
+	data := encoded.Bytes()
+	for i, want := range []uint64{0x20, 2, 0x40, 0x40 + 0x20 + 0x60} {
+		if got := new(big.Int).SetBytes(data[32*i : 32*i+32]); got.Uint64() != want {
+			t.Errorf("word %%d: expected %%d, got %%s", i, want, got)
+		}
+	}
+
+	if got := EncodeMulticall(); got != "0x"+"0000000000000000000000000000000000000000000000000000000000000020"+"0000000000000000000000000000000000000000000000000000000000000000" {
+		t.Errorf("unexpected encoding of no calls %%s", got)
+	}
+}
+`, hex.EncodeToString(expected))})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+func TestMulticall_Decode(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(bindTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	// Two transfer results, true and false, as returned by multicall(bytes[])
+	boolType, _ := abi.NewType("bool", "", nil)
+	succeeded, err := abi.Arguments{{Type: boolType}}.Pack(true)
+	if err != nil {
+		t.Fatalf("failed to pack bool: %v", err)
+	}
+	failed, err := abi.Arguments{{Type: boolType}}.Pack(false)
+	if err != nil {
+		t.Fatalf("failed to pack bool: %v", err)
+	}
+	bytesArrayType, _ := abi.NewType("bytes[]", "", nil)
+	returnData, err := abi.Arguments{{Type: bytesArrayType}}.Pack([][]byte{succeeded, failed})
+	if err != nil {
+		t.Fatalf("failed to pack bytes[]: %v", err)
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"simpletoken": fmt.Sprintf(`package simpletoken
+
+import "testing"
+
+func TestDecodeMulticall(t *testing.T) {
+	results, err := DecodeMulticall(HexData("0x%s").Bytes())
+	if err != nil {
+		t.Fatalf("DecodeMulticall failed: %%v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %%d", len(results))
+	}
+	if ok := Methods().TransferMethod().MustDecode(results[0].Bytes()); !ok {
+		t.Error("expected the first transfer to succeed")
+	}
+	if ok := Methods().TransferMethod().MustDecode(results[1].Bytes()); ok {
+		t.Error("expected the second transfer to fail")
+	}
+
+	// Encoding and decoding the same calls round-trips
+	calls := []HexData{"0xa9059cbb", "0x", "0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f2021"}
+	decoded, err := DecodeMulticall(EncodeMulticall(calls...).Bytes())
+	if err != nil {
+		t.Fatalf("DecodeMulticall failed: %%v", err)
+	}
+	if len(decoded) != len(calls) {
+		t.Fatalf("expected %%d results, got %%d", len(calls), len(decoded))
+	}
+	for i := range calls {
+		if decoded[i] != calls[i] {
+			t.Errorf("result %%d: expected %%s, got %%s", i, calls[i], decoded[i])
+		}
+	}
+
+	// Truncated return data is rejected
+	data := HexData("0x%s").Bytes()
+	if _, err := DecodeMulticall(data[:len(data)-32]); err == nil {
+		t.Error("expected an error for truncated return data")
+	}
+}
+`, hex.EncodeToString(returnData), hex.EncodeToString(returnData))})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+func TestMulticall_SplitCalls(t *testing.T) {
+	input := `{
+		"contracts": {
+			"Batched.sol:Batched": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "transfer",
+						"inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}],
+						"outputs": [{"name": "", "type": "bool"}],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "function",
+						"name": "approve",
+						"inputs": [{"name": "spender", "type": "address"}, {"name": "amount", "type": "uint256"}],
+						"outputs": [{"name": "", "type": "bool"}],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "function",
+						"name": "setName",
+						"inputs": [{"name": "name", "type": "string"}],
+						"outputs": [],
+						"stateMutability": "nonpayable"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {
+					"transfer(address,uint256)": "a9059cbb",
+					"approve(address,uint256)": "095ea7b3",
+					"setName(string)": "c47f0027"
+				}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"batched": `package batched
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSplitCalls(t *testing.T) {
+	to := AddressFromHex("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4")
+	transfer := Methods().TransferMethod().MustPack(to, big.NewInt(1))
+	approve := Methods().ApproveMethod().MustPack(to, big.NewInt(2))
+
+	calls, err := SplitCalls(append(transfer.Bytes(), approve.Bytes()...))
+	if err != nil {
+		t.Fatalf("SplitCalls failed: %v", err)
+	}
+	if len(calls) != 2 || calls[0] != transfer || calls[1] != approve {
+		t.Fatalf("unexpected calls %v", calls)
+	}
+
+	// Dynamic arguments, unknown selectors and truncated calls cannot be split
+	setName := Methods().SetNameMethod().MustPack("solgen")
+	for name, data := range map[string][]byte{
+		"dynamic":   append(transfer.Bytes(), setName.Bytes()...),
+		"unknown":   append(transfer.Bytes(), 0xde, 0xad, 0xbe, 0xef),
+		"truncated": append(transfer.Bytes(), approve.Bytes()[:30]...),
+		"selector":  append(transfer.Bytes(), 0x09, 0x5e),
+	} {
+		if _, err := SplitCalls(data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+`})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
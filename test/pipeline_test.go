@@ -4,6 +4,7 @@ package test
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/otherview/solgen/internal/types"
@@ -240,4 +241,39 @@ func TestCombinedJSONTypes(t *testing.T) {
 	if len(contract.Hashes) != 1 {
 		t.Errorf("expected 1 hash, got %d", len(contract.Hashes))
 	}
-}
\ No newline at end of file
+}
+
+// TestCombinedJSONContractArray checks that contracts given as an array of
+// named contracts read like the map keyed by "file.sol:Contract"
+func TestCombinedJSONContractArray(t *testing.T) {
+	contract := `"abi": [{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"}],
+		"bin": "0x608060405234801561001057600080fd5b50",
+		"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+		"hashes": {"transfer(address,uint256)": "a9059cbb"}`
+
+	want, err := processCombinedJSON([]byte(`{"contracts": {"SimpleToken.sol:SimpleToken": {` + contract + `}}}`))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed for the map form: %v", err)
+	}
+
+	// A name without a source file is read as the contract of the file named after it
+	for _, name := range []string{"SimpleToken.sol:SimpleToken", "SimpleToken"} {
+		got, err := processCombinedJSON([]byte(`{"contracts": [{"name": "` + name + `", ` + contract + `}]}`))
+		if err != nil {
+			t.Fatalf("processCombinedJSON failed for the array form named %s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("array form named %s: expected the contract of the map form, got %+v", name, got[0])
+		}
+	}
+
+	for _, data := range []string{
+		`{"contracts": [{"abi": []}]}`,
+		`{"contracts": [{"name": "Token", "abi": []}, {"name": "Token.sol:Token", "abi": []}]}`,
+	} {
+		var combined types.CombinedJSON
+		if err := json.Unmarshal([]byte(data), &combined); err == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+}
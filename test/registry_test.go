@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/otherview/solgen/internal/gen"
+)
+
+func TestRegistry_MethodLookup(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(bindTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"simpletoken": `package simpletoken
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestMethodLookup(t *testing.T) {
+	method, ok := Methods().ByName("transfer")
+	if !ok {
+		t.Fatal("transfer not found by name")
+	}
+
+	to := AddressFromHex("0x742d35Cc6634C0532925a3b8c0b56D39C3F6C842")
+	packed, err := method.Pack(to, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	want := "0xa9059cbb" +
+		"000000000000000000000000742d35cc6634c0532925a3b8c0b56d39c3f6c842" +
+		"00000000000000000000000000000000000000000000000000000000000003e8"
+	if packed.Hex() != want {
+		t.Errorf("unexpected packed call data %s", packed.Hex())
+	}
+
+	bySelector, ok := Methods().BySelector([4]byte{0xa9, 0x05, 0x9c, 0xbb})
+	if !ok || bySelector != method {
+		t.Errorf("expected transfer by selector, got %+v (found %v)", bySelector, ok)
+	}
+
+	if _, ok := Methods().ByName("approve"); ok {
+		t.Error("unknown method should not be found")
+	}
+	if _, ok := Methods().BySelector([4]byte{}); ok {
+		t.Error("unknown selector should not be found")
+	}
+}
+`})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+func TestRegistry_OverloadAccessors(t *testing.T) {
+	input := `{
+		"contracts": {
+			"Collectible.sol:Collectible": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "safeTransferFrom",
+						"inputs": [
+							{"name": "from", "type": "address"},
+							{"name": "to", "type": "address"},
+							{"name": "tokenId", "type": "uint256"},
+							{"name": "data", "type": "bytes"}
+						],
+						"outputs": [],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "function",
+						"name": "safeTransferFrom",
+						"inputs": [
+							{"name": "from", "type": "address"},
+							{"name": "to", "type": "address"},
+							{"name": "tokenId", "type": "uint256"}
+						],
+						"outputs": [],
+						"stateMutability": "nonpayable"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {
+					"safeTransferFrom(address,address,uint256)": "42842e0e",
+					"safeTransferFrom(address,address,uint256,bytes)": "b88d4fde"
+				}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	// The suffixed accessors tell where their name comes from
+	content, err := os.ReadFile(filepath.Join(outputDir, "collectible", "collectible.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	for _, accessor := range []string{"SafeTransferFrom_Address_Address_Uint256Method", "SafeTransferFrom_Address_Address_Uint256_BytesMethod"} {
+		doc := "// Renamed from safeTransferFrom, which is overloaded in Solidity\nfunc (mr MethodRegistry) " + accessor + "()"
+		if !strings.Contains(string(content), doc) {
+			t.Errorf("expected %s to document its Solidity name", accessor)
+		}
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"collectible": `package collectible
+
+import "testing"
+
+func TestOverloadAccessors(t *testing.T) {
+	// The overload with the fewest parameters keeps the plain accessor
+	if got := Methods().SafeTransferFromMethod().Signature; got != "safeTransferFrom(address,address,uint256)" {
+		t.Errorf("unexpected default overload %s", got)
+	}
+
+	// Every overload keeps its suffixed accessor
+	if got := Methods().SafeTransferFrom_Address_Address_Uint256Method().Selector; got != "0x42842e0e" {
+		t.Errorf("unexpected selector %s", got)
+	}
+	if got := Methods().SafeTransferFrom_Address_Address_Uint256_BytesMethod().Selector; got != "0xb88d4fde" {
+		t.Errorf("unexpected selector %s", got)
+	}
+
+	// ByName finds the overloads by their generated names, and the default
+	// overload by its Solidity name
+	for name, selector := range map[string]HexData{
+		"safeTransferFrom": "0x42842e0e",
+		Methods().SafeTransferFrom_Address_Address_Uint256Method().Name:       "0x42842e0e",
+		Methods().SafeTransferFrom_Address_Address_Uint256_BytesMethod().Name: "0xb88d4fde",
+	} {
+		method, ok := Methods().ByName(name)
+		if !ok {
+			t.Errorf("%s: not found", name)
+			continue
+		}
+		if method.Selector != selector {
+			t.Errorf("%s: expected selector %s, got %s", name, selector, method.Selector)
+		}
+	}
+	if method, _ := Methods().ByName("safeTransferFrom"); method.Name != Methods().SafeTransferFromMethod().Name {
+		t.Errorf("expected the method of SafeTransferFromMethod, got %s", method.Name)
+	}
+}
+`})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+func TestRegistry_SelectorOf(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(bindTestInput))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	t.Run("single file", func(t *testing.T) {
+		outputDir := t.TempDir()
+		if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{SingleFile: true, WithKeccak: true}).Generate(contracts); err != nil {
+			t.Fatalf("code generation failed: %v", err)
+		}
+		if err := testGeneratedCode(t, outputDir); err != nil {
+			t.Errorf("generated code failed to compile: %v", err)
+		}
+	})
+
+	outputDir := t.TempDir()
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{WithKeccak: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	// An input longer than the 136-byte rate, split across arguments, spans two blocks
+	long := strings.Repeat("solgen", 40)
+	longHash := crypto.Keccak256Hash([]byte(long))
+
+	writeGeneratedTests(t, outputDir, map[string]string{"simpletoken": fmt.Sprintf(`package simpletoken
+
+import "testing"
+
+func TestSelectorOf(t *testing.T) {
+	if got := SelectorOf("transfer(address,uint256)"); got != "0xa9059cbb" {
+		t.Errorf("unexpected transfer selector %%s", got)
+	}
+
+	// Matches the selectors computed by solc
+	for name, method := range methodsByName {
+		if got := SelectorOf(method.Signature); got != method.Selector {
+			t.Errorf("%%s: expected selector %%s, got %%s", name, method.Selector, got)
+		}
+	}
+}
+
+func TestKeccak256(t *testing.T) {
+	if got := Keccak256().String(); got != "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470" {
+		t.Errorf("unexpected hash of no data %%s", got)
+	}
+	long := %q
+	if got := Keccak256([]byte(long[:100]), []byte(long[100:])).String(); got != %q {
+		t.Errorf("unexpected hash of multi-block data %%s", got)
+	}
+}
+`, long, longHash.Hex())})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
@@ -0,0 +1,681 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/otherview/solgen/internal/gen"
+)
+
+// roundTripTypes are the Solidity types Pack accepts, each echoed by a method
+// taking and returning it
+var roundTripTypes = []string{
+	"uint8", "uint16", "uint32", "uint64", "uint256",
+	"int8", "int16", "int32", "int64", "int256",
+	"address", "bool", "bytes32",
+	"string", "bytes",
+}
+
+// roundTripMixed mixes static and dynamic types in a single call
+var roundTripMixed = []string{"uint256", "string", "address", "bytes", "bool"}
+
+// roundTripSmall returns the narrow integer types as multiple return values
+var roundTripSmall = []string{"uint16", "uint32", "int8", "int16", "int32"}
+
+// roundTripInput builds a combined JSON contract with an echo method per type,
+// plus ones echoing all of roundTripMixed and roundTripSmall, and an error and
+// an event carrying roundTripSmall
+func roundTripInput(t *testing.T) string {
+	t.Helper()
+
+	var entries []map[string]any
+	hashes := make(map[string]string)
+	addMethod := func(name string, abiTypes []string) {
+		var params []map[string]string
+		for i, abiType := range abiTypes {
+			params = append(params, map[string]string{"name": fmt.Sprintf("v%d", i), "type": abiType})
+		}
+		entries = append(entries, map[string]any{
+			"type":            "function",
+			"name":            name,
+			"inputs":          params,
+			"outputs":         params,
+			"stateMutability": "pure",
+		})
+		signature := fmt.Sprintf("%s(%s)", name, strings.Join(abiTypes, ","))
+		hashes[signature] = hex.EncodeToString(crypto.Keccak256([]byte(signature))[:4])
+	}
+	for _, abiType := range roundTripTypes {
+		addMethod("echo"+strings.ToUpper(abiType[:1])+abiType[1:], []string{abiType})
+	}
+	addMethod("echoMixed", roundTripMixed)
+	addMethod("echoSmall", roundTripSmall)
+
+	var small []map[string]any
+	for i, abiType := range roundTripSmall {
+		small = append(small, map[string]any{"name": fmt.Sprintf("v%d", i), "type": abiType, "indexed": false})
+	}
+	entries = append(entries,
+		map[string]any{"type": "error", "name": "OutOfRange", "inputs": small},
+		map[string]any{"type": "event", "name": "Narrowed", "inputs": small, "anonymous": false},
+	)
+
+	input, err := json.Marshal(map[string]any{
+		"contracts": map[string]any{
+			"RoundTrip.sol:RoundTrip": map[string]any{
+				"abi":         entries,
+				"bin":         "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes":      hashes,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to build input: %v", err)
+	}
+	return string(input)
+}
+
+// TestRoundTrip_GeneratedPackages generates the round trip fixtures below into
+// one module and runs their in-package tests, which need the compiled code
+func TestRoundTrip_GeneratedPackages(t *testing.T) {
+	runGeneratedFixtures(t, map[string]generatedFixture{
+		"roundtrip":       roundTripFixture,
+		"tuple_arguments": tupleArgumentsFixture,
+	})
+}
+
+// roundTripFixture packs a value of every supported type with the generated
+// Pack and decodes it back with the generated Decode. An echo method returns
+// its arguments unchanged, so the calldata after the selector is also the
+// encoding of its return values. The package also checks the bounds and
+// variants of Pack, the integer bound helpers, StripMetadata and the text
+// encoding of Address and Hash.
+func roundTripFixture(t *testing.T, outputDir string) map[string]string {
+	contracts, err := processCombinedJSON([]byte(roundTripInput(t)))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"roundtrip": `package roundtrip
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// returnData packs the arguments and strips the selector
+func returnData(t *testing.T, method PackableMethod, args ...any) []byte {
+	t.Helper()
+	packed, err := method.Pack(args...)
+	if err != nil {
+		t.Fatalf("%s: Pack failed: %v", method.Name, err)
+	}
+	return packed.Bytes()[4:]
+}
+
+func TestRoundTrip(t *testing.T) {
+	minInt256, _ := new(big.Int).SetString("-57896044618658097711785492504343953926634992332820282019728792003956564819968", 10)
+	maxUint256, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	addr := AddressFromHex("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4")
+	word := [32]byte{0x01, 0x02, 0x03, 31: 0xff}
+	long := strings.Repeat("solgen ", 10)
+	blob := bytes.Repeat([]byte{0xca, 0xfe}, 33)
+
+	if got := Methods().EchoUint8Method().MustDecode(returnData(t, Methods().EchoUint8Method().PackableMethod, uint8(255))); got != 255 {
+		t.Errorf("uint8: got %d", got)
+	}
+	if got := Methods().EchoUint16Method().MustDecode(returnData(t, Methods().EchoUint16Method().PackableMethod, uint16(65535))); got != 65535 {
+		t.Errorf("uint16: got %d", got)
+	}
+	if got := Methods().EchoUint32Method().MustDecode(returnData(t, Methods().EchoUint32Method().PackableMethod, uint32(1<<32-1))); got != 1<<32-1 {
+		t.Errorf("uint32: got %d", got)
+	}
+	if got := Methods().EchoUint64Method().MustDecode(returnData(t, Methods().EchoUint64Method().PackableMethod, uint64(1<<64-1))); got != 1<<64-1 {
+		t.Errorf("uint64: got %d", got)
+	}
+	if got := Methods().EchoUint256Method().MustDecode(returnData(t, Methods().EchoUint256Method().PackableMethod, maxUint256)); got.Cmp(maxUint256) != 0 {
+		t.Errorf("uint256: got %s", got)
+	}
+	for _, v := range []int8{-128, -1, 0, 127} {
+		if got := Methods().EchoInt8Method().MustDecode(returnData(t, Methods().EchoInt8Method().PackableMethod, v)); got != v {
+			t.Errorf("int8: expected %d, got %d", v, got)
+		}
+	}
+	for _, v := range []int16{-1 << 15, -1, 0, 1<<15 - 1} {
+		if got := Methods().EchoInt16Method().MustDecode(returnData(t, Methods().EchoInt16Method().PackableMethod, v)); got != v {
+			t.Errorf("int16: expected %d, got %d", v, got)
+		}
+	}
+	for _, v := range []int32{-1 << 31, -1, 0, 1<<31 - 1} {
+		if got := Methods().EchoInt32Method().MustDecode(returnData(t, Methods().EchoInt32Method().PackableMethod, v)); got != v {
+			t.Errorf("int32: expected %d, got %d", v, got)
+		}
+	}
+	for _, v := range []int64{-1 << 63, -1, 0, 1<<63 - 1} {
+		if got := Methods().EchoInt64Method().MustDecode(returnData(t, Methods().EchoInt64Method().PackableMethod, v)); got != v {
+			t.Errorf("int64: expected %d, got %d", v, got)
+		}
+	}
+	for _, v := range []*big.Int{minInt256, big.NewInt(-1), big.NewInt(42)} {
+		if got := Methods().EchoInt256Method().MustDecode(returnData(t, Methods().EchoInt256Method().PackableMethod, v)); got.Cmp(v) != 0 {
+			t.Errorf("int256: expected %s, got %s", v, got)
+		}
+	}
+	if got := Methods().EchoAddressMethod().MustDecode(returnData(t, Methods().EchoAddressMethod().PackableMethod, addr)); got != addr {
+		t.Errorf("address: got %s", got)
+	}
+	for _, v := range []bool{true, false} {
+		if got := Methods().EchoBoolMethod().MustDecode(returnData(t, Methods().EchoBoolMethod().PackableMethod, v)); got != v {
+			t.Errorf("bool: expected %v, got %v", v, got)
+		}
+	}
+	if got := Methods().EchoBytes32Method().MustDecode(returnData(t, Methods().EchoBytes32Method().PackableMethod, word)); got != word {
+		t.Errorf("bytes32: got %x", got)
+	}
+	for _, v := range []string{"", "hello", long} {
+		if got := Methods().EchoStringMethod().MustDecode(returnData(t, Methods().EchoStringMethod().PackableMethod, v)); got != v {
+			t.Errorf("string: expected %q, got %q", v, got)
+		}
+	}
+	for _, v := range [][]byte{{}, {0x01}, blob} {
+		if got := Methods().EchoBytesMethod().MustDecode(returnData(t, Methods().EchoBytesMethod().PackableMethod, v)); !bytes.Equal(got, v) {
+			t.Errorf("bytes: expected %x, got %x", v, got)
+		}
+	}
+
+	mixed := Methods().EchoMixedMethod().MustDecode(returnData(t, Methods().EchoMixedMethod().PackableMethod, maxUint256, long, addr, blob, true))
+	if mixed.V0.Cmp(maxUint256) != 0 || mixed.V1 != long || mixed.V2 != addr || !bytes.Equal(mixed.V3, blob) || !mixed.V4 {
+		t.Errorf("mixed: got %+v", mixed)
+	}
+
+	small, err := Methods().EchoSmallMethod().Pack(uint16(65535), uint32(1<<32-1), int8(-128), int16(-1), int32(1<<31-1))
+	if err != nil {
+		t.Fatalf("small: Pack failed: %v", err)
+	}
+	if got := Methods().EchoSmallMethod().MustDecode(small.Bytes()[4:]); got.V0 != 65535 || got.V1 != 1<<32-1 || got.V2 != -128 || got.V3 != -1 || got.V4 != 1<<31-1 {
+		t.Errorf("small: got %+v", got)
+	}
+	if _, err := Methods().EchoInt8Method().Pack(int16(128)); err == nil {
+		t.Error("expected an error for an int8 overflow")
+	}
+}
+
+// Errors and events decode the narrow integers like return values do
+func TestSmallErrorAndEvent(t *testing.T) {
+	packed, err := Methods().EchoSmallMethod().Pack(uint16(65535), uint32(1<<32-1), int8(-128), int16(-1), int32(1<<31-1))
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	data := packed.Bytes()[4:]
+
+	decoded, err := Errors().OutOfRangeError().Decode(append(Errors().OutOfRangeError().Selector.Bytes(), data...))
+	if err != nil {
+		t.Fatalf("error Decode failed: %v", err)
+	}
+	if decoded.V0 != 65535 || decoded.V1 != 1<<32-1 || decoded.V2 != -128 || decoded.V3 != -1 || decoded.V4 != 1<<31-1 {
+		t.Errorf("error: got %+v", decoded)
+	}
+	event, err := Events().NarrowedEventDecoder().Decode(data)
+	if err != nil {
+		t.Fatalf("event Decode failed: %v", err)
+	}
+	if event.V0 != 65535 || event.V1 != 1<<32-1 || event.V2 != -128 || event.V3 != -1 || event.V4 != 1<<31-1 {
+		t.Errorf("event: got %+v", event)
+	}
+
+	// Values not fitting the narrow types are rejected rather than truncated
+	for word, value := range map[int]byte{2: 0x80, 3: 0x01, 4: 0xff} {
+		bad := append([]byte(nil), data...)
+		copy(bad[word*32:(word+1)*32], make([]byte, 32))
+		bad[word*32+27] = value
+		if _, err := Errors().OutOfRangeError().Decode(append(Errors().OutOfRangeError().Selector.Bytes(), bad...)); err == nil {
+			t.Errorf("error: expected value %d in v%d to be rejected", value, word)
+		}
+		if _, err := Events().NarrowedEventDecoder().Decode(bad); err == nil {
+			t.Errorf("event: expected value %d in v%d to be rejected", value, word)
+		}
+		if _, err := Methods().EchoSmallMethod().Decode(bad); err == nil {
+			t.Errorf("return: expected value %d in v%d to be rejected", value, word)
+		}
+	}
+	if _, err := Methods().EchoInt8Method().Decode(data[2*32:3*32]); err != nil {
+		t.Errorf("int8: %v", err)
+	}
+	if _, err := Methods().EchoInt8Method().Decode(data[4*32:]); err == nil {
+		t.Error("int8: expected an out of range value to be rejected")
+	}
+}
+
+func TestMaxConstants(t *testing.T) {
+	max := MaxUint256()
+	if max.BitLen() != 256 {
+		t.Errorf("expected 256 bits, got %d", max.BitLen())
+	}
+	for i := 0; i < 256; i++ {
+		if max.Bit(i) != 1 {
+			t.Fatalf("expected bit %d to be set", i)
+		}
+	}
+
+	// Every call returns a new value
+	max.SetInt64(0)
+	if MaxUint256().Sign() == 0 {
+		t.Error("modifying a returned MaxUint256 changed later ones")
+	}
+
+	if MaxUint128().BitLen() != 128 || MaxInt256().BitLen() != 255 {
+		t.Error("unexpected MaxUint128 or MaxInt256")
+	}
+	if new(big.Int).Add(MinInt256(), MaxInt256()).Int64() != -1 {
+		t.Error("expected MinInt256 to be -MaxInt256 - 1")
+	}
+	if MaxUint64 != ^uint64(0) || MaxUint8 != 255 {
+		t.Error("unexpected integer constants")
+	}
+
+	// The bounds pack and decode back unchanged
+	for _, v := range []*big.Int{MaxInt256(), MinInt256()} {
+		data, err := Methods().EchoInt256Method().Pack(v)
+		if err != nil {
+			t.Fatalf("packing %s: %v", v, err)
+		}
+		decoded, err := Methods().EchoInt256Method().Decode(data.Bytes()[4:])
+		if err != nil || decoded.Cmp(v) != 0 {
+			t.Errorf("expected %s back, got %v (%v)", v, decoded, err)
+		}
+	}
+	data, err := Methods().EchoUint256Method().Pack(MaxUint256())
+	if err != nil {
+		t.Fatalf("packing MaxUint256: %v", err)
+	}
+	if decoded := Methods().EchoUint256Method().MustDecode(data.Bytes()[4:]); decoded.Cmp(MaxUint256()) != 0 {
+		t.Errorf("expected MaxUint256 back, got %s", decoded)
+	}
+}
+
+func TestStripMetadata(t *testing.T) {
+	code := HexData("0x6080604052348015600f57600080fd5b50")
+
+	// solc metadata: {"ipfs": <34-byte multihash>, "solc": 0.8.24}, followed by its 2-byte length 0x33.
+	// Builds differing only in their metadata hash strip to the same code.
+	for _, hash := range []string{"ab", "cd"} {
+		built := code + HexData("a2646970667358221220"+strings.Repeat(hash, 32)+"64736f6c63430008180033")
+		if got := built.StripMetadata(); got != code {
+			t.Errorf("expected %s, got %s", code, got)
+		}
+	}
+
+	// Code without metadata is left unchanged
+	for _, code := range []HexData{code, "0x", "0x0033"} {
+		if got := code.StripMetadata(); got != code {
+			t.Errorf("expected %s unchanged, got %s", code, got)
+		}
+	}
+}
+
+func TestAddressHashText(t *testing.T) {
+	alice := AddressFromHex("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	root := HashFromHex("0x00000000000000000000000000000000000000000000000000000000000000ff")
+
+	// Addresses are usable as JSON map keys
+	balances := map[Address]uint64{alice: 7}
+	data, err := json.Marshal(balances)
+	if err != nil {
+		t.Fatalf("marshaling balances: %v", err)
+	}
+	if string(data) != ` + "`" + `{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed":7}` + "`" + ` {
+		t.Errorf("unexpected balances JSON %s", data)
+	}
+	var decoded map[Address]uint64
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshaling balances: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, balances) {
+		t.Errorf("expected %v, got %v", balances, decoded)
+	}
+
+	text, err := root.MarshalText()
+	if err != nil {
+		t.Fatalf("marshaling hash: %v", err)
+	}
+	var hash Hash
+	if err := hash.UnmarshalText(text); err != nil || hash != root {
+		t.Errorf("hash round trip: expected %s, got %s (%v)", root, hash, err)
+	}
+
+	// The 0x prefix is optional, the length and digits are checked
+	var addr Address
+	if err := addr.UnmarshalText([]byte("5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")); err != nil || addr != alice {
+		t.Errorf("expected %s without the prefix, got %s (%v)", alice, addr, err)
+	}
+	if err := addr.UnmarshalText([]byte("0x5aaeb6")); err == nil {
+		t.Error("expected an error for a short address")
+	}
+	if err := hash.UnmarshalText([]byte("0x" + string(make([]byte, 64)))); err == nil {
+		t.Error("expected an error for invalid hex digits")
+	}
+}
+
+// TestPackBounds checks that Pack rejects integers that do not fit
+// the size of their Solidity type instead of packing them as wider integers
+func TestPackBounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		method PackableMethod
+		arg    any
+		want   string
+	}{
+		{"uint8 overflow", Methods().EchoUint8Method().PackableMethod, big.NewInt(300), "packing echoUint8 argument 0: value 300 overflows uint8"},
+		{"widened uint8 overflow", Methods().EchoUint8Method().PackableMethod, uint64(300), "packing echoUint8 argument 0: value 300 overflows uint8"},
+		{"uint16 overflow", Methods().EchoUint16Method().PackableMethod, uint32(1 << 16), "packing echoUint16 argument 0: value 65536 overflows uint16"},
+		{"negative uint256", Methods().EchoUint256Method().PackableMethod, big.NewInt(-1), "packing echoUint256 argument 0: negative value -1 for uint256"},
+	}
+	for _, tt := range tests {
+		if _, err := tt.method.Pack(tt.arg); err == nil || err.Error() != tt.want {
+			t.Errorf("%s: expected %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// Values at the bounds still pack
+	if _, err := Methods().EchoUint8Method().Pack(big.NewInt(255)); err != nil {
+		t.Errorf("uint8 255: unexpected error %v", err)
+	}
+	if _, err := Methods().EchoInt64Method().Pack(int64(-1 << 63)); err != nil {
+		t.Errorf("int64 min: unexpected error %v", err)
+	}
+}
+
+// TestPackWithSelector checks that PackWithSelector encodes the
+// arguments like Pack after the caller's selector
+func TestPackWithSelector(t *testing.T) {
+	sel := [4]byte{0x12, 0x34, 0x56, 0x78}
+	args := []any{big.NewInt(7), "forwarded", AddressFromHex("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4"), []byte{0xca, 0xfe}, true}
+
+	packed, err := Methods().EchoMixedMethod().Pack(args...)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	forwarded, err := Methods().EchoMixedMethod().PackWithSelector(sel, args...)
+	if err != nil {
+		t.Fatalf("PackWithSelector failed: %v", err)
+	}
+	if !bytes.Equal(forwarded.Bytes()[:4], sel[:]) {
+		t.Errorf("expected selector %x, got %x", sel, forwarded.Bytes()[:4])
+	}
+	if !bytes.Equal(forwarded.Bytes()[4:], packed.Bytes()[4:]) {
+		t.Errorf("expected the arguments of Pack, got %s", forwarded)
+	}
+
+	// Arguments are checked like Pack
+	if _, err := Methods().EchoUint8Method().PackWithSelector(sel, big.NewInt(300)); err == nil {
+		t.Error("expected an error for a uint8 overflow")
+	}
+}
+
+// TestPackArgs checks that PackArgs encodes the arguments of Pack
+// without the method selector
+func TestPackArgs(t *testing.T) {
+	method := Methods().EchoMixedMethod()
+	args := []any{big.NewInt(7), "hashed", AddressFromHex("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4"), []byte{0xca, 0xfe}, true}
+
+	packed, err := method.Pack(args...)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	encoded, err := method.PackArgs(args...)
+	if err != nil {
+		t.Fatalf("PackArgs failed: %v", err)
+	}
+	if !bytes.Equal(packed.Bytes(), append(method.Selector.Bytes(), encoded.Bytes()...)) {
+		t.Errorf("expected Pack to be the selector followed by PackArgs, got %s and %s", packed, encoded)
+	}
+
+	// Arguments are checked like Pack
+	if _, err := Methods().EchoUint8Method().PackArgs(big.NewInt(300)); err == nil {
+		t.Error("expected an error for a uint8 overflow")
+	}
+}
+
+// TestPackPadded checks that PackPadded right-pads the calldata of
+// Pack with zero bytes to the requested alignment
+func TestPackPadded(t *testing.T) {
+	args := []any{big.NewInt(7), "padded", AddressFromHex("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4"), []byte{0xca, 0xfe}, true}
+
+	packed, err := Methods().EchoMixedMethod().Pack(args...)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	for _, alignment := range []int{1, 32, 64, 100} {
+		padded, err := Methods().EchoMixedMethod().PackPadded(alignment, args...)
+		if err != nil {
+			t.Fatalf("PackPadded(%d) failed: %v", alignment, err)
+		}
+		data := padded.Bytes()
+		if len(data)%alignment != 0 || len(data)-len(packed.Bytes()) >= alignment {
+			t.Errorf("alignment %d: unexpected length %d for %d packed bytes", alignment, len(data), len(packed.Bytes()))
+		}
+		if !bytes.Equal(data[:len(packed.Bytes())], packed.Bytes()) {
+			t.Errorf("alignment %d: expected the calldata of Pack first, got %s", alignment, padded)
+		}
+		if !bytes.Equal(data[len(packed.Bytes()):], make([]byte, len(data)-len(packed.Bytes()))) {
+			t.Errorf("alignment %d: expected zero padding, got %s", alignment, padded)
+		}
+	}
+
+	if _, err := Methods().EchoMixedMethod().PackPadded(0, args...); err == nil || err.Error() != "invalid calldata alignment 0" {
+		t.Errorf("expected an error for alignment 0, got %v", err)
+	}
+	if _, err := Methods().EchoUint8Method().PackPadded(32, big.NewInt(300)); err == nil {
+		t.Error("expected an error for a uint8 overflow")
+	}
+}
+`}
+}
+
+// tupleArgsABI takes a dynamic struct, nesting a static struct and an array of
+// structs, alone and next to other arguments
+const tupleArgsABI = `[
+	{"type": "function", "name": "createOrder", "stateMutability": "nonpayable", "outputs": [], "inputs": [
+		{"name": "o", "type": "tuple", "internalType": "struct Exchange.Order", "components": [
+			{"name": "maker", "type": "address", "internalType": "address"},
+			{"name": "price", "type": "tuple", "internalType": "struct Exchange.Price", "components": [
+				{"name": "value", "type": "uint256", "internalType": "uint256"},
+				{"name": "decimals", "type": "uint8", "internalType": "uint8"}
+			]},
+			{"name": "note", "type": "string", "internalType": "string"},
+			{"name": "legs", "type": "tuple[]", "internalType": "struct Exchange.Leg[]", "components": [
+				{"name": "id", "type": "bytes32", "internalType": "bytes32"},
+				{"name": "data", "type": "bytes", "internalType": "bytes"}
+			]}
+		]}
+	]},
+	{"type": "function", "name": "fillOrders", "stateMutability": "nonpayable", "outputs": [], "inputs": [
+		{"name": "orders", "type": "tuple[]", "internalType": "struct Exchange.Order[]", "components": [
+			{"name": "maker", "type": "address", "internalType": "address"},
+			{"name": "price", "type": "tuple", "internalType": "struct Exchange.Price", "components": [
+				{"name": "value", "type": "uint256", "internalType": "uint256"},
+				{"name": "decimals", "type": "uint8", "internalType": "uint8"}
+			]},
+			{"name": "note", "type": "string", "internalType": "string"},
+			{"name": "legs", "type": "tuple[]", "internalType": "struct Exchange.Leg[]", "components": [
+				{"name": "id", "type": "bytes32", "internalType": "bytes32"},
+				{"name": "data", "type": "bytes", "internalType": "bytes"}
+			]}
+		]},
+		{"name": "deadline", "type": "uint64", "internalType": "uint64"},
+		{"name": "price", "type": "tuple", "internalType": "struct Exchange.Price", "components": [
+			{"name": "value", "type": "uint256", "internalType": "uint256"},
+			{"name": "decimals", "type": "uint8", "internalType": "uint8"}
+		]}
+	]}
+]`
+
+// tupleArgumentsFixture packs struct arguments with the generated typed
+// Pack, compares the calldata with go-ethereum and decodes it back with UnpackInput
+func tupleArgumentsFixture(t *testing.T, outputDir string) map[string]string {
+	parsed, err := abi.JSON(strings.NewReader(tupleArgsABI))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+
+	type price struct {
+		Value    *big.Int
+		Decimals uint8
+	}
+	type leg struct {
+		Id   [32]byte
+		Data []byte
+	}
+	type order struct {
+		Maker common.Address
+		Price price
+		Note  string
+		Legs  []leg
+	}
+	first := order{
+		Maker: common.HexToAddress("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4"),
+		Price: price{Value: big.NewInt(1500), Decimals: 2},
+		Note:  "first order",
+		Legs:  []leg{{Id: [32]byte{1}, Data: []byte{0xca, 0xfe}}, {Id: [32]byte{2}, Data: bytes.Repeat([]byte{0xab}, 40)}},
+	}
+	second := order{
+		Maker: common.HexToAddress("0xAb8483F64d9C6d1EcF9b849Ae677dD3315835cb2"),
+		Price: price{Value: big.NewInt(3), Decimals: 18},
+		Note:  "",
+		Legs:  []leg{},
+	}
+	createOrder, err := parsed.Pack("createOrder", first)
+	if err != nil {
+		t.Fatalf("failed to pack createOrder: %v", err)
+	}
+	fillOrders, err := parsed.Pack("fillOrders", []order{first, second}, uint64(1700000000), price{Value: big.NewInt(42), Decimals: 6})
+	if err != nil {
+		t.Fatalf("failed to pack fillOrders: %v", err)
+	}
+
+	hashes := make(map[string]string)
+	for _, method := range parsed.Methods {
+		hashes[method.Sig] = hex.EncodeToString(method.ID)
+	}
+	input, err := json.Marshal(map[string]any{
+		"contracts": map[string]any{
+			"Exchange.sol:Exchange": map[string]any{
+				"abi":    json.RawMessage(tupleArgsABI),
+				"hashes": hashes,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to build input: %v", err)
+	}
+
+	contracts, err := processCombinedJSON(input)
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"exchange": fmt.Sprintf(`package exchange
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func equalOrder(a, b Order) bool {
+	if a.Maker != b.Maker || a.Price.Value.Cmp(b.Price.Value) != 0 || a.Price.Decimals != b.Price.Decimals ||
+		a.Note != b.Note || len(a.Legs) != len(b.Legs) {
+		return false
+	}
+	for i := range a.Legs {
+		if a.Legs[i].Id != b.Legs[i].Id || !bytes.Equal(a.Legs[i].Data, b.Legs[i].Data) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTupleArguments(t *testing.T) {
+	first := Order{
+		Maker: AddressFromHex("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4"),
+		Price: Price{Value: big.NewInt(1500), Decimals: 2},
+		Note:  "first order",
+		Legs:  []Leg{{Id: [32]byte{1}, Data: []byte{0xca, 0xfe}}, {Id: [32]byte{2}, Data: bytes.Repeat([]byte{0xab}, 40)}},
+	}
+	second := Order{
+		Maker: AddressFromHex("0xAb8483F64d9C6d1EcF9b849Ae677dD3315835cb2"),
+		Price: Price{Value: big.NewInt(3), Decimals: 18},
+	}
+
+	packed, err := Methods().CreateOrderMethod().Pack(first)
+	if err != nil {
+		t.Fatalf("Pack failed: %%v", err)
+	}
+	if packed != "0x%x" {
+		t.Errorf("unexpected createOrder calldata %%s", packed)
+	}
+	order, err := Methods().CreateOrderMethod().UnpackInput(packed.Bytes())
+	if err != nil {
+		t.Fatalf("UnpackInput failed: %%v", err)
+	}
+	if !equalOrder(order, first) {
+		t.Errorf("createOrder round trip: expected %%+v, got %%+v", first, order)
+	}
+
+	fill := Methods().FillOrdersMethod().MustPack([]Order{first, second}, 1700000000, Price{Value: big.NewInt(42), Decimals: 6})
+	if fill != "0x%x" {
+		t.Errorf("unexpected fillOrders calldata %%s", fill)
+	}
+	args, err := Methods().FillOrdersMethod().UnpackInput(fill.Bytes())
+	if err != nil {
+		t.Fatalf("UnpackInput failed: %%v", err)
+	}
+	if len(args.Orders) != 2 || !equalOrder(args.Orders[0], first) || !equalOrder(args.Orders[1], second) {
+		t.Errorf("fillOrders round trip: got %%+v", args.Orders)
+	}
+	if args.Deadline != 1700000000 || args.Price.Value.Int64() != 42 || args.Price.Decimals != 6 {
+		t.Errorf("fillOrders round trip: got deadline %%d and price %%+v", args.Deadline, args.Price)
+	}
+
+	// The typed PackWithSelector only swaps the selector
+	forwarded, err := Methods().CreateOrderMethod().PackWithSelector([4]byte{0xde, 0xad, 0xbe, 0xef}, first)
+	if err != nil {
+		t.Fatalf("PackWithSelector failed: %%v", err)
+	}
+	if forwarded != "0xdeadbeef"+packed[10:] {
+		t.Errorf("unexpected forwarded calldata %%s", forwarded)
+	}
+
+	// Calldata of another method is rejected
+	if _, err := Methods().CreateOrderMethod().UnpackInput(fill.Bytes()); err == nil {
+		t.Error("expected an error for calldata of another method")
+	}
+	if _, err := Methods().CreateOrderMethod().UnpackInput(packed.Bytes()[:100]); err == nil {
+		t.Error("expected an error for truncated calldata")
+	}
+}
+`, createOrder, fillOrders)}
+}
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/otherview/solgen/internal/gen"
+)
+
+func TestSingleFile_MultipleContracts(t *testing.T) {
+	// Both contracts declare a Transfer event and a transfer method so the
+	// generated names would collide without contract prefixes
+	input := `{
+		"contracts": {
+			"SimpleToken.sol:SimpleToken": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "transfer",
+						"inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}],
+						"outputs": [{"name": "", "type": "bool"}],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "event",
+						"name": "Transfer",
+						"inputs": [
+							{"name": "from", "type": "address", "indexed": true},
+							{"name": "to", "type": "address", "indexed": true},
+							{"name": "value", "type": "uint256", "indexed": false}
+						]
+					},
+					{
+						"type": "error",
+						"name": "InsufficientBalance",
+						"inputs": [{"name": "available", "type": "uint256"}]
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"transfer(address,uint256)": "a9059cbb"}
+			},
+			"Vault.sol:Vault": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "transfer",
+						"inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}],
+						"outputs": [{"name": "", "type": "bool"}],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "function",
+						"name": "getPosition",
+						"inputs": [{"name": "owner", "type": "address"}],
+						"outputs": [
+							{
+								"components": [
+									{"internalType": "uint256", "name": "amount", "type": "uint256"},
+									{"internalType": "address", "name": "owner", "type": "address"}
+								],
+								"internalType": "struct Vault.Position",
+								"name": "",
+								"type": "tuple"
+							}
+						],
+						"stateMutability": "view"
+					},
+					{
+						"type": "event",
+						"name": "Transfer",
+						"inputs": [
+							{"name": "from", "type": "address", "indexed": true},
+							{"name": "to", "type": "address", "indexed": true},
+							{"name": "value", "type": "uint256", "indexed": false}
+						]
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {
+					"transfer(address,uint256)": "a9059cbb",
+					"getPosition(address)": "16c19739"
+				}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	generator := gen.NewGeneratorWithOptions(outputDir, gen.Options{SingleFile: true, PackageName: "bindings"})
+	if err := generator.Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	// Only the single package directory should have been created
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatalf("failed to read output directory: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "bindings" {
+		t.Fatalf("expected only the bindings package directory, got %v", entries)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "bindings", "bindings.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	expectedContents := []string{
+		"package bindings",
+		"// Contracts: SimpleToken, Vault",
+		"func SimpleTokenMethods() SimpleTokenMethodRegistry",
+		"func VaultMethods() VaultMethodRegistry",
+		"type SimpleTokenTransferEvent struct",
+		"type VaultTransferEvent struct",
+		"type SimpleTokenInsufficientBalanceError struct",
+		"type VaultPosition struct",
+		"func SimpleTokenABI() string",
+		"func VaultABI() string",
+	}
+	for _, expected := range expectedContents {
+		if !strings.Contains(contentStr, expected) {
+			t.Errorf("generated file should contain %q", expected)
+		}
+	}
+
+	// Shared runtime must only be declared once
+	for _, runtimeDecl := range []string{"type Address [20]byte", "func decodeUint256(", "type PackableMethod struct"} {
+		if count := strings.Count(contentStr, runtimeDecl); count != 1 {
+			t.Errorf("expected %q to be declared once, found %d", runtimeDecl, count)
+		}
+	}
+
+	if err := testGeneratedCode(t, outputDir); err != nil {
+		t.Errorf("generated code compilation failed: %v", err)
+	}
+}
+
+func TestSingleFile_SharedStructs(t *testing.T) {
+	// Both contracts define Meta with the same shape, which is declared once,
+	// and Info with different shapes, which keeps the contract prefixes
+	input := `{
+		"contracts": {
+			"TokenA.sol:TokenA": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "meta",
+						"inputs": [],
+						"outputs": [
+							{
+								"components": [{"internalType": "string", "name": "name", "type": "string"}],
+								"internalType": "struct TokenA.Meta",
+								"name": "",
+								"type": "tuple"
+							}
+						],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "info",
+						"inputs": [],
+						"outputs": [
+							{
+								"components": [{"internalType": "uint256", "name": "supply", "type": "uint256"}],
+								"internalType": "struct TokenA.Info",
+								"name": "",
+								"type": "tuple"
+							}
+						],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"meta()": "c885044e", "info()": "370158ea"}
+			},
+			"TokenB.sol:TokenB": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "setMeta",
+						"inputs": [
+							{
+								"components": [{"internalType": "string", "name": "name", "type": "string"}],
+								"internalType": "struct TokenB.Meta",
+								"name": "meta",
+								"type": "tuple"
+							}
+						],
+						"outputs": [],
+						"stateMutability": "nonpayable"
+					},
+					{
+						"type": "function",
+						"name": "info",
+						"inputs": [],
+						"outputs": [
+							{
+								"components": [{"internalType": "address", "name": "owner", "type": "address"}],
+								"internalType": "struct TokenB.Info",
+								"name": "",
+								"type": "tuple"
+							}
+						],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"setMeta((string))": "b7129dc4", "info()": "370158ea"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	generator := gen.NewGeneratorWithOptions(outputDir, gen.Options{SingleFile: true, PackageName: "bindings"})
+	if err := generator.Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "bindings", "bindings.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, decl := range []string{"type Meta struct", "func decodeMeta(", "func encodeMeta("} {
+		if count := strings.Count(contentStr, decl); count != 1 {
+			t.Errorf("expected %q to be declared once, found %d", decl, count)
+		}
+	}
+	for _, unexpected := range []string{"TokenAMeta ", "TokenBMeta ", "type Info struct"} {
+		if strings.Contains(contentStr, unexpected) {
+			t.Errorf("generated file should not contain %q", unexpected)
+		}
+	}
+	for _, expected := range []string{"type TokenAInfo struct", "type TokenBInfo struct"} {
+		if !strings.Contains(contentStr, expected) {
+			t.Errorf("generated file should contain %q", expected)
+		}
+	}
+
+	// A Meta packed for TokenB decodes as the Meta returned by TokenA
+	writeGeneratedTests(t, outputDir, map[string]string{"bindings": `package bindings
+
+import "testing"
+
+func TestSharedMeta(t *testing.T) {
+	packed, err := TokenBMethods().SetMetaMethod().Pack(Meta{Name: "shared"})
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	meta, err := TokenAMethods().MetaMethod().Decode(packed.Bytes()[4:])
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if meta.Name != "shared" {
+		t.Errorf("expected shared, got %q", meta.Name)
+	}
+}
+`})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
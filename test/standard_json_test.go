@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"testing"
+
+	"github.com/otherview/solgen/internal/gen"
+	"github.com/otherview/solgen/internal/input"
+	"github.com/otherview/solgen/internal/parse"
+)
+
+func TestStandardJSON_LinkReferences(t *testing.T) {
+	// Vault links against MathLib, the placeholder starts at byte 3 of the creation bytecode
+	output := `{
+		"contracts": {
+			"contracts/Vault.sol": {
+				"Vault": {
+					"abi": [
+						{"type": "constructor", "inputs": [{"name": "owner", "type": "address"}], "stateMutability": "nonpayable"},
+						{"type": "function", "name": "total", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"}
+					],
+					"metadata": "{\"compiler\":{\"version\":\"0.8.20+commit.a1b79de6\"},\"language\":\"Solidity\"}",
+					"evm": {
+						"bytecode": {
+							"object": "608060__$3a1f0d6fb2b0a1e2c8f1d3e5b7a9c2d4e6$__6000",
+							"linkReferences": {
+								"contracts/MathLib.sol": {
+									"MathLib": [{"start": 3, "length": 20}]
+								}
+							}
+						},
+						"deployedBytecode": {"object": "6080604052"},
+						"methodIdentifiers": {"total()": "2ddbd13a"}
+					}
+				}
+			}
+		},
+		"sources": {"contracts/Vault.sol": {"id": 0}}
+	}`
+
+	result, solcVersion, err := input.StandardJSON([]byte(output))
+	if err != nil {
+		t.Fatalf("input.StandardJSON failed: %v", err)
+	}
+	if solcVersion != "0.8.20+commit.a1b79de6" {
+		t.Errorf("expected solc version from metadata, got %q", solcVersion)
+	}
+
+	contracts, err := parse.ResultWithVersion(result, solcVersion)
+	if err != nil {
+		t.Fatalf("parse.ResultWithVersion failed: %v", err)
+	}
+	if len(contracts) != 1 {
+		t.Fatalf("expected 1 contract, got %d", len(contracts))
+	}
+
+	contract := contracts[0]
+	if contract.Constructor == nil {
+		t.Fatal("expected a constructor")
+	}
+	refs := contract.Constructor.LinkReferences["MathLib"]
+	if len(refs) != 1 || refs[0].Start != 3 || refs[0].Length != 20 {
+		t.Errorf("unexpected MathLib link references: %+v", refs)
+	}
+	if len(contract.Methods) != 1 || contract.Methods[0].Selector != "0x2ddbd13a" {
+		t.Errorf("expected total() with selector 0x2ddbd13a, got %+v", contract.Methods)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+	if err := testGeneratedCode(t, outputDir); err != nil {
+		t.Errorf("generated code failed to compile: %v", err)
+	}
+}
+
+func TestStandardJSON_CompileErrors(t *testing.T) {
+	output := `{
+		"errors": [
+			{"component": "general", "formattedMessage": "ParserError", "message": "Expected ';'", "severity": "error", "type": "ParserError"}
+		],
+		"sources": {}
+	}`
+
+	if _, _, err := input.StandardJSON([]byte(output)); err == nil {
+		t.Error("expected solc errors to be reported")
+	}
+}
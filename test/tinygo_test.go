@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/otherview/solgen/internal/gen"
+)
+
+// tinyGoPoolJSON returns arrays as a single output, among several outputs and as struct fields
+const tinyGoPoolJSON = `{
+	"contracts": {
+		"Pool.sol:Pool": {
+			"abi": [
+				{
+					"type": "function",
+					"name": "owners",
+					"inputs": [],
+					"outputs": [{"name": "", "type": "address[]"}],
+					"stateMutability": "view"
+				},
+				{
+					"type": "function",
+					"name": "balances",
+					"inputs": [],
+					"outputs": [{"name": "amounts", "type": "uint256[]"}, {"name": "flags", "type": "bool[]"}],
+					"stateMutability": "view"
+				},
+				{
+					"type": "function",
+					"name": "snapshot",
+					"inputs": [],
+					"outputs": [{
+						"name": "",
+						"type": "tuple",
+						"internalType": "struct Pool.Snapshot",
+						"components": [
+							{"name": "deltas", "type": "int256[]"},
+							{"name": "roots", "type": "bytes32[]"}
+						]
+					}],
+					"stateMutability": "view"
+				}
+			],
+			"bin": "0x608060405234801561001057600080fd5b50",
+			"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+			"hashes": {"owners()": "affe39c1", "balances()": "7bb98a68", "snapshot()": "9711715a"}
+		}
+	}
+}`
+
+func TestTinyGo_TypedArrayDecoders(t *testing.T) {
+	contracts, err := processCombinedJSON([]byte(tinyGoPoolJSON))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{TinyGo: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "pool", "pool.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	for _, unwanted := range []string{"[]interface{}", "decodeArray(", "ArrayElement"} {
+		if strings.Contains(string(content), unwanted) {
+			t.Errorf("TinyGo code should not contain %q", unwanted)
+		}
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"pool": `package pool
+
+import (
+	"math/big"
+	"testing"
+)
+
+func word(v int64) []byte {
+	data := make([]byte, 32)
+	big.NewInt(v).FillBytes(data)
+	return data
+}
+
+func concat(words ...[]byte) []byte {
+	var data []byte
+	for _, w := range words {
+		data = append(data, w...)
+	}
+	return data
+}
+
+func TestTypedArrays(t *testing.T) {
+	owner := word(0)
+	owner[31] = 0xaa
+	owners, err := Methods().OwnersMethod().Decode(concat(word(32), word(2), owner, word(0)))
+	if err != nil {
+		t.Fatalf("decoding owners: %v", err)
+	}
+	if len(owners) != 2 || owners[0][19] != 0xaa || owners[1] != (Address{}) {
+		t.Errorf("unexpected owners %v", owners)
+	}
+
+	balances, err := Methods().BalancesMethod().Decode(concat(word(64), word(160), word(2), word(7), word(9), word(1), word(1)))
+	if err != nil {
+		t.Fatalf("decoding balances: %v", err)
+	}
+	if len(balances.Amounts) != 2 || balances.Amounts[1].Int64() != 9 || len(balances.Flags) != 1 || !balances.Flags[0] {
+		t.Errorf("unexpected balances %+v", balances)
+	}
+
+	minusOne := make([]byte, 32)
+	for i := range minusOne {
+		minusOne[i] = 0xff
+	}
+	snapshot, err := Methods().SnapshotMethod().Decode(concat(word(32), word(64), word(128), word(1), minusOne, word(1), word(5)))
+	if err != nil {
+		t.Fatalf("decoding snapshot: %v", err)
+	}
+	if len(snapshot.Deltas) != 1 || snapshot.Deltas[0].Int64() != -1 || len(snapshot.Roots) != 1 || snapshot.Roots[0][31] != 5 {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+
+	// A length running past the data is rejected before allocating
+	if _, err := Methods().OwnersMethod().Decode(concat(word(32), word(1 << 40))); err == nil {
+		t.Error("expected an error for a truncated array")
+	}
+}
+`})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated tests failed: %v", err)
+	}
+}
+
+func TestTinyGo_RejectsWithBind(t *testing.T) {
+	binaryPath := buildSolgen(t)
+	output, err := runSolgen(binaryPath, tinyGoPoolJSON, "--out", t.TempDir(), "--tinygo", "--with-bind")
+	if err == nil {
+		t.Fatal("expected --tinygo with --with-bind to fail")
+	}
+	if !strings.Contains(output, "--tinygo cannot be used with --with-bind") {
+		t.Errorf("unexpected output: %s", output)
+	}
+}
@@ -0,0 +1,1072 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/otherview/solgen/internal/gen"
+	"github.com/otherview/solgen/internal/parse"
+)
+
+// TestTypes_GeneratedPackages generates the type mapping fixtures below into
+// one module and runs their in-package tests, which need the compiled code
+func TestTypes_GeneratedPackages(t *testing.T) {
+	runGeneratedFixtures(t, map[string]generatedFixture{
+		"enum_return":                     enumReturnFixture,
+		"enum_is_valid":                   enumIsValidFixture,
+		"enum_struct_field":               enumStructFieldFixture,
+		"with_equal":                      withEqualFixture,
+		"getters_with_found":              gettersWithFoundFixture,
+		"single_struct_return":            singleStructReturnFixture,
+		"single_tuple_output_struct_name": singleTupleOutputStructNameFixture,
+		"anonymous_tuple_output":          anonymousTupleOutputFixture,
+		"struct_registry":                 structRegistryFixture,
+	})
+}
+
+func enumReturnFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "status",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "uint8", "internalType": "enum Vault.Status"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "info",
+						"inputs": [],
+						"outputs": [
+							{"name": "status", "type": "uint8", "internalType": "enum Vault.Status"},
+							{"name": "decimals", "type": "uint8", "internalType": "uint8"}
+						],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"status()": "200d2ed2", "info()": "370158ea"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	contract := contracts[0]
+	if len(contract.Enums) != 1 || contract.Enums[0].Name != "Status" {
+		t.Fatalf("expected a single Status enum, got %+v", contract.Enums)
+	}
+	for _, method := range contract.Methods {
+		if output := method.Outputs[0]; output.Type.TypeName != "Status" || !output.Type.IsEnum {
+			t.Errorf("method %s: expected Status enum output, got %+v", method.Name, output.Type)
+		}
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "vault", "vault.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	for _, expected := range []string{
+		"type Status uint8",
+		"func (m *StatusMethod) Decode(data []byte) (Status, error)",
+		"Status   Status",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("generated file should contain %q", expected)
+		}
+	}
+
+	return map[string]string{"vault": `package vault
+
+import "testing"
+
+func TestEnumDecode(t *testing.T) {
+	word := func(v byte) []byte {
+		data := make([]byte, 32)
+		data[31] = v
+		return data
+	}
+
+	status, err := Methods().StatusMethod().Decode(word(2))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if status != Status(2) {
+		t.Errorf("expected Status(2), got %d", status)
+	}
+
+	info, err := Methods().InfoMethod().Decode(append(word(1), word(18)...))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if info.Status != Status(1) {
+		t.Errorf("expected Status(1), got %d", info.Status)
+	}
+}
+`}
+}
+
+func enumIsValidFixture(t *testing.T, outputDir string) map[string]string {
+	// The AST defines Status with 3 members, Kind has no definition
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "status",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "uint8", "internalType": "enum Vault.Status"}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "info",
+						"inputs": [],
+						"outputs": [{"name": "", "type": "uint8", "internalType": "enum Vault.Kind"}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"status()": "200d2ed2", "info()": "370158ea"}
+			}
+		},
+		"sources": {
+			"Vault.sol": {
+				"AST": {
+					"nodeType": "SourceUnit",
+					"nodes": [
+						{
+							"nodeType": "ContractDefinition",
+							"name": "Vault",
+							"nodes": [
+								{
+									"nodeType": "EnumDefinition",
+									"name": "Status",
+									"canonicalName": "Vault.Status",
+									"members": [
+										{"nodeType": "EnumValue", "name": "Pending"},
+										{"nodeType": "EnumValue", "name": "Active"},
+										{"nodeType": "EnumValue", "name": "Closed"}
+									]
+								}
+							]
+						}
+					]
+				}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	members := make(map[string]int)
+	for _, enum := range contracts[0].Enums {
+		members[enum.Name] = enum.Members
+	}
+	if members["Status"] != 3 || members["Kind"] != 0 {
+		t.Fatalf("unexpected enum member counts %v", members)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "vault", "vault.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if strings.Contains(string(content), "func (e Kind) IsValid() bool") {
+		t.Error("Kind has no known members and should not get IsValid")
+	}
+
+	return map[string]string{"vault": `package vault
+
+import "testing"
+
+func TestEnumIsValid(t *testing.T) {
+	word := func(v byte) []byte {
+		data := make([]byte, 32)
+		data[31] = v
+		return data
+	}
+
+	for v, valid := range map[byte]bool{0: true, 2: true, 3: false, 200: false} {
+		status, err := Methods().StatusMethod().Decode(word(v))
+		if err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		if status.IsValid() != valid {
+			t.Errorf("Status(%d).IsValid(): expected %t", v, valid)
+		}
+	}
+}
+`}
+}
+
+func TestTypes_TypeMapStructField(t *testing.T) {
+	input := `{
+		"contracts": {
+			"Ledger.sol:Ledger": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "position",
+						"inputs": [],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"internalType": "struct Ledger.Position",
+							"components": [
+								{"name": "account", "type": "address", "internalType": "address"},
+								{"name": "amount", "type": "uint256", "internalType": "uint256"}
+							]
+						}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"position()": "09218e91"}
+			}
+		}
+	}`
+
+	typeMap, err := parse.LoadTypeMap([]byte(`{"address": {"type": "acct.Account", "import": "generated-test/acct"}}`))
+	if err != nil {
+		t.Fatalf("LoadTypeMap failed: %v", err)
+	}
+
+	contracts, err := processCombinedJSONWithOptions([]byte(input), parse.Options{TypeMap: typeMap})
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "ledger", "ledger.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	for _, expected := range []string{
+		`"generated-test/acct"`,
+		"Account acct.Account",
+		"result.Account = acct.Account(valAddr)",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("generated file should contain %q", expected)
+		}
+	}
+
+	// The custom type is an alias provided by the consuming module
+	if err := os.MkdirAll(filepath.Join(outputDir, "acct"), 0755); err != nil {
+		t.Fatalf("failed to create acct package: %v", err)
+	}
+	acctSource := "package acct\n\n// Account is a custom address type\ntype Account = [20]byte\n"
+	if err := os.WriteFile(filepath.Join(outputDir, "acct", "acct.go"), []byte(acctSource), 0644); err != nil {
+		t.Fatalf("failed to write acct package: %v", err)
+	}
+
+	writeGeneratedTests(t, outputDir, map[string]string{"ledger": `package ledger
+
+import (
+	"math/big"
+	"testing"
+
+	"generated-test/acct"
+)
+
+func TestTypeMapDecode(t *testing.T) {
+	data := make([]byte, 64)
+	data[31] = 0xaa
+	data[63] = 7
+
+	position, err := Methods().PositionMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	var want acct.Account
+	want[19] = 0xaa
+	if position.Account != want {
+		t.Errorf("expected account %x, got %x", want, position.Account)
+	}
+	if position.Amount.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("expected amount 7, got %s", position.Amount)
+	}
+}
+`})
+
+	if err := runGeneratedTests(t, outputDir); err != nil {
+		t.Errorf("generated package tests failed: %v", err)
+	}
+}
+
+// enumStructFieldFixture checks that struct fields, also in nested structs,
+// get their enum types like top-level parameters do
+func enumStructFieldFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "position",
+						"inputs": [],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"internalType": "struct Vault.Position",
+							"components": [
+								{"name": "owner", "type": "address", "internalType": "address"},
+								{"name": "status", "type": "uint8", "internalType": "enum Vault.Status"},
+								{"name": "limit", "type": "tuple", "internalType": "struct Vault.Limit", "components": [
+									{"name": "kind", "type": "uint8", "internalType": "enum Vault.Kind"},
+									{"name": "cap", "type": "uint256", "internalType": "uint256"}
+								]}
+							]
+						}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "open",
+						"inputs": [{
+							"name": "p",
+							"type": "tuple",
+							"internalType": "struct Vault.Position",
+							"components": [
+								{"name": "owner", "type": "address", "internalType": "address"},
+								{"name": "status", "type": "uint8", "internalType": "enum Vault.Status"},
+								{"name": "limit", "type": "tuple", "internalType": "struct Vault.Limit", "components": [
+									{"name": "kind", "type": "uint8", "internalType": "enum Vault.Kind"},
+									{"name": "cap", "type": "uint256", "internalType": "uint256"}
+								]}
+							]
+						}],
+						"outputs": [],
+						"stateMutability": "nonpayable"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"position()": "09218e91", "open((address,uint8,(uint8,uint256)))": "7caf60be"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	contract := contracts[0]
+	if len(contract.Enums) != 2 || contract.Enums[0].Name != "Kind" || contract.Enums[1].Name != "Status" {
+		t.Fatalf("expected the Kind and Status enums, got %+v", contract.Enums)
+	}
+	fieldTypes := make(map[string]string)
+	for _, s := range contract.Structs {
+		for _, field := range s.Fields {
+			if field.Type.IsEnum {
+				fieldTypes[s.Name+"."+field.Name] = field.Type.TypeName
+			}
+		}
+	}
+	if len(fieldTypes) != 2 || fieldTypes["Position.Status"] != "Status" || fieldTypes["Limit.Kind"] != "Kind" {
+		t.Errorf("expected enum typed Position.Status and Limit.Kind, got %v", fieldTypes)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"vault": `package vault
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestEnumStructField(t *testing.T) {
+	word := func(v byte) []byte {
+		data := make([]byte, 32)
+		data[31] = v
+		return data
+	}
+	data := bytes.Join([][]byte{word(0xa1), word(2), word(1), word(7)}, nil)
+
+	position, err := Methods().PositionMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	var status Status = position.Status
+	var kind Kind = position.Limit.Kind
+	if status != 2 || kind != 1 || position.Limit.Cap.Int64() != 7 {
+		t.Errorf("unexpected position %+v", position)
+	}
+
+	// The enum fields pack back as their uint8 values
+	packed, err := Methods().OpenMethod().Pack(Position{Owner: position.Owner, Status: 2, Limit: Limit{Kind: 1, Cap: big.NewInt(7)}})
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if !bytes.Equal(packed.Bytes()[4:], data) {
+		t.Errorf("expected the decoded words back, got %s", packed)
+	}
+
+	// Out of range values are rejected like for enum parameters
+	if _, err := Methods().PositionMethod().Decode(bytes.Join([][]byte{word(0xa1), append(word(0)[:30], 1, 0), word(1), word(7)}, nil)); err == nil {
+		t.Error("expected an error for a status above uint8")
+	}
+}
+`}
+}
+
+func withEqualFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Accounts.sol:Accounts": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "user",
+						"inputs": [{"name": "id", "type": "uint256"}],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"internalType": "struct Accounts.User",
+							"components": [
+								{"name": "balance", "type": "uint256", "internalType": "uint256"},
+								{"name": "wallet", "type": "address", "internalType": "address"},
+								{"name": "active", "type": "bool", "internalType": "bool"},
+								{"name": "id", "type": "bytes32", "internalType": "bytes32"},
+								{"name": "name", "type": "string", "internalType": "string"},
+								{"name": "scores", "type": "uint256[]", "internalType": "uint256[]"}
+							]
+						}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "limits",
+						"inputs": [{"name": "id", "type": "uint256"}],
+						"outputs": [
+							{"name": "cap", "type": "uint256", "internalType": "uint256"},
+							{"name": "owner", "type": "address", "internalType": "address"}
+						],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"user(uint256)": "b0467deb", "limits(uint256)": "5a8d0d35"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{WithEqual: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"accounts": `package accounts
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestIsZero(t *testing.T) {
+	var user User
+	if !user.IsZero() {
+		t.Error("expected the zero User to be zero")
+	}
+
+	// Decoded zero values are non-nil big integers and empty slices
+	decoded := User{Balance: big.NewInt(0), Scores: []*big.Int{}}
+	if !decoded.IsZero() {
+		t.Error("expected a decoded zero User to be zero")
+	}
+
+	for name, user := range map[string]User{
+		"balance": {Balance: big.NewInt(1)},
+		"wallet":  {Wallet: AddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")},
+		"active":  {Active: true},
+		"id":      {Id: [32]byte{1}},
+		"name":    {Name: "alice"},
+		"scores":  {Scores: []*big.Int{big.NewInt(0)}},
+	} {
+		if user.IsZero() {
+			t.Errorf("expected a User with %s set not to be zero", name)
+		}
+	}
+
+	if !(LimitsResult{}).IsZero() || (LimitsResult{Cap: big.NewInt(5)}).IsZero() {
+		t.Error("unexpected LimitsResult.IsZero result")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a := User{Balance: big.NewInt(7), Name: "alice", Scores: []*big.Int{big.NewInt(1), big.NewInt(2)}}
+	b := User{Balance: big.NewInt(7), Name: "alice", Scores: []*big.Int{big.NewInt(1), big.NewInt(2)}}
+	if !a.Equal(b) {
+		t.Error("expected users with equal values to be equal")
+	}
+
+	b.Scores[1] = big.NewInt(3)
+	if a.Equal(b) {
+		t.Error("expected users with different scores not to be equal")
+	}
+	if a.Equal(User{Name: "alice", Scores: a.Scores}) {
+		t.Error("expected a nil balance not to equal a set one")
+	}
+}
+`}
+}
+
+func gettersWithFoundFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Registry.sol:Registry": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "users",
+						"inputs": [{"name": "account", "type": "address"}],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"internalType": "struct Registry.User",
+							"components": [
+								{"name": "balance", "type": "uint256", "internalType": "uint256"},
+								{"name": "wallet", "type": "address", "internalType": "address"}
+							]
+						}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "limits",
+						"inputs": [{"name": "id", "type": "uint256"}],
+						"outputs": [
+							{"name": "cap", "type": "uint256", "internalType": "uint256"},
+							{"name": "active", "type": "bool", "internalType": "bool"}
+						],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "totals",
+						"inputs": [],
+						"outputs": [
+							{"name": "supply", "type": "uint256", "internalType": "uint256"},
+							{"name": "holders", "type": "uint256", "internalType": "uint256"}
+						],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"users(address)": "a87430ba", "limits(uint256)": "20e9daf1", "totals()": "c038a38e"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{WithFound: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "registry", "registry.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if strings.Contains(string(content), "func (m *TotalsMethod) DecodeFound") {
+		t.Error("totals takes no key and should not get DecodeFound")
+	}
+
+	return map[string]string{"registry": `package registry
+
+import (
+	"math/big"
+	"testing"
+)
+
+func word(v int64) []byte {
+	data := make([]byte, 32)
+	big.NewInt(v).FillBytes(data)
+	return data
+}
+
+func TestDecodeFound(t *testing.T) {
+	user, found, err := Methods().UsersMethod().DecodeFound(append(word(0), word(0)...))
+	if err != nil {
+		t.Fatalf("decoding missing user: %v", err)
+	}
+	if found || !user.IsZero() {
+		t.Errorf("expected a missing user, got %+v found=%v", user, found)
+	}
+
+	user, found, err = Methods().UsersMethod().DecodeFound(append(word(0), word(1)...))
+	if err != nil {
+		t.Fatalf("decoding user: %v", err)
+	}
+	if !found || user.Wallet[19] != 1 {
+		t.Errorf("expected a set user, got %+v found=%v", user, found)
+	}
+
+	limits, found, err := Methods().LimitsMethod().DecodeFound(append(word(0), word(1)...))
+	if err != nil {
+		t.Fatalf("decoding limits: %v", err)
+	}
+	if !found || !limits.Active {
+		t.Errorf("expected set limits, got %+v found=%v", limits, found)
+	}
+
+	if _, _, err := Methods().LimitsMethod().DecodeFound(word(0)); err == nil {
+		t.Error("expected an error for truncated data")
+	}
+}
+`}
+}
+
+func singleStructReturnFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Users.sol:Users": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "getUser",
+						"inputs": [{"name": "id", "type": "uint256"}],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"internalType": "struct Users.User",
+							"components": [
+								{"name": "name", "type": "string", "internalType": "string"},
+								{"name": "balance", "type": "uint256", "internalType": "uint256"}
+							]
+						}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "getUserAndId",
+						"inputs": [],
+						"outputs": [
+							{
+								"name": "user",
+								"type": "tuple",
+								"internalType": "struct Users.User",
+								"components": [
+									{"name": "name", "type": "string", "internalType": "string"},
+									{"name": "balance", "type": "uint256", "internalType": "uint256"}
+								]
+							},
+							{"name": "id", "type": "uint256", "internalType": "uint256"}
+						],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"getUser(uint256)": "b0467deb", "getUserAndId()": "74056222"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	userType, err := abi.NewType("tuple", "struct Users.User", []abi.ArgumentMarshaling{
+		{Name: "name", Type: "string"},
+		{Name: "balance", Type: "uint256"},
+	})
+	if err != nil {
+		t.Fatalf("failed to build tuple type: %v", err)
+	}
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+	user := struct {
+		Name    string
+		Balance *big.Int
+	}{"alice", big.NewInt(100)}
+	single, err := abi.Arguments{{Type: userType}}.Pack(user)
+	if err != nil {
+		t.Fatalf("failed to encode user: %v", err)
+	}
+	multi, err := abi.Arguments{{Type: userType}, {Type: uint256Type}}.Pack(user, big.NewInt(7))
+	if err != nil {
+		t.Fatalf("failed to encode user and id: %v", err)
+	}
+
+	if err := gen.NewGeneratorWithOptions(outputDir, gen.Options{WithEqual: true}).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "users", "users.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "func (m *GetUserMethod) Decode(data []byte) (User, error)") {
+		t.Error("a single struct return should decode to the struct itself")
+	}
+	if strings.Contains(string(content), "GetUserResult") {
+		t.Error("no result wrapper should be generated for a single struct return")
+	}
+	if !strings.Contains(string(content), "func (m *GetUserAndIdMethod) Decode(data []byte) (GetUserAndIdResult, error)") {
+		t.Error("multiple returns should still decode to a result struct")
+	}
+
+	return map[string]string{"users": `package users
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestSingleStructReturn(t *testing.T) {
+	want := User{Name: "alice", Balance: big.NewInt(100)}
+
+	single, _ := hex.DecodeString("` + hex.EncodeToString(single) + `")
+	user, err := Methods().GetUserMethod().Decode(single)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !user.Equal(want) {
+		t.Errorf("expected %+v, got %+v", want, user)
+	}
+
+	multi, _ := hex.DecodeString("` + hex.EncodeToString(multi) + `")
+	result, err := Methods().GetUserAndIdMethod().Decode(multi)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !result.User.Equal(want) || result.Id.Int64() != 7 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+`}
+}
+
+func singleTupleOutputStructNameFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Exchange.sol:Exchange": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "getOrder",
+						"inputs": [{"name": "id", "type": "uint256"}],
+						"outputs": [{
+							"name": "order",
+							"type": "tuple",
+							"internalType": "struct Exchange.OrderDetails",
+							"components": [
+								{"name": "maker", "type": "address", "internalType": "address"},
+								{"name": "amount", "type": "uint256", "internalType": "uint256"}
+							]
+						}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"getOrder(uint256)": "d09ef241"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	// The name comes from internalType, not from go-ethereum's dotless raw name
+	method := contracts[0].Methods[0]
+	if output := method.Outputs[0].Type; output.TypeName != "OrderDetails" || !output.IsStruct {
+		t.Fatalf("expected an OrderDetails return type, got %+v", output)
+	}
+	if method.OutputStruct != nil {
+		t.Errorf("expected no output struct for a single tuple output, got %s", method.OutputStruct.Name)
+	}
+	if len(contracts[0].Structs) != 1 || contracts[0].Structs[0].Name != "OrderDetails" {
+		t.Fatalf("expected the OrderDetails struct to be registered, got %+v", contracts[0].Structs)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "exchange", "exchange.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "Decode(data []byte) (OrderDetails, error)") {
+		t.Error("expected Decode to return OrderDetails")
+	}
+	if strings.Contains(string(content), "GetOrderOutput") {
+		t.Error("expected no GetOrderOutput type")
+	}
+	return nil
+}
+
+func anonymousTupleOutputFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Vault.sol:Vault": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "position",
+						"inputs": [],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"components": [
+								{"name": "amount", "type": "uint256"},
+								{"name": "owner", "type": "address"}
+							]
+						}],
+						"stateMutability": "view"
+					},
+					{
+						"type": "function",
+						"name": "positions",
+						"inputs": [],
+						"outputs": [{
+							"name": "",
+							"type": "tuple[]",
+							"components": [
+								{"name": "amount", "type": "uint256"},
+								{"name": "owner", "type": "address"}
+							]
+						}],
+						"stateMutability": "view"
+					}
+				],
+				"bin": "0x608060405234801561001057600080fd5b50",
+				"bin-runtime": "0x6080604052348015600f57600080fd5b50",
+				"hashes": {"position()": "09218e91", "positions()": "ba5b7982"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	// Both methods share the struct named after the tuple element types
+	if len(contracts[0].Structs) != 1 || contracts[0].Structs[0].Name != "TupleUint256Address" {
+		t.Fatalf("expected the TupleUint256Address struct to be registered, got %+v", contracts[0].Structs)
+	}
+
+	tupleType, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "amount", Type: "uint256"},
+		{Name: "owner", Type: "address"},
+	})
+	if err != nil {
+		t.Fatalf("failed to build tuple type: %v", err)
+	}
+	encoded, err := abi.Arguments{{Type: tupleType}}.Pack(struct {
+		Amount *big.Int
+		Owner  common.Address
+	}{big.NewInt(42), common.HexToAddress("0x000000000000000000000000000000000000beef")})
+	if err != nil {
+		t.Fatalf("failed to encode tuple: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"vault": `package vault
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestAnonymousTupleOutput(t *testing.T) {
+	data, _ := hex.DecodeString("` + hex.EncodeToString(encoded) + `")
+	position, err := Methods().PositionMethod().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if position.Amount.Int64() != 42 {
+		t.Errorf("expected 42, got %s", position.Amount)
+	}
+	if position.Owner != AddressFromHex("0x000000000000000000000000000000000000beef") {
+		t.Errorf("unexpected owner %s", position.Owner)
+	}
+}
+`}
+}
+
+func structRegistryFixture(t *testing.T, outputDir string) map[string]string {
+	input := `{
+		"contracts": {
+			"Accounts.sol:Accounts": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "user",
+						"inputs": [{"name": "id", "type": "uint256"}],
+						"outputs": [{
+							"name": "",
+							"type": "tuple",
+							"internalType": "struct Accounts.User",
+							"components": [
+								{"name": "name", "type": "string", "internalType": "string"},
+								{"name": "badge", "type": "tuple", "internalType": "struct Accounts.Badge", "components": [
+									{"name": "level", "type": "uint8", "internalType": "uint8"},
+									{"name": "issuer", "type": "address", "internalType": "address"}
+								]},
+								{"name": "scores", "type": "uint256[]", "internalType": "uint256[]"}
+							]
+						}],
+						"stateMutability": "view"
+					}
+				],
+				"hashes": {"user(uint256)": "b0467deb"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	return map[string]string{"accounts": `package accounts
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestStructRegistry(t *testing.T) {
+	if names := StructNames(); !reflect.DeepEqual(names, []string{"Badge", "User"}) {
+		t.Fatalf("unexpected struct names %v", names)
+	}
+
+	// Every struct round-trips through its codec
+	values := map[string]any{
+		"Badge": Badge{Level: 3, Issuer: AddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")},
+		"User":  User{Name: "alice", Badge: Badge{Level: 1}, Scores: []*big.Int{big.NewInt(7), big.NewInt(9)}},
+	}
+	for _, name := range StructNames() {
+		codec, ok := StructCodecByName(name)
+		if !ok || codec.Name != name {
+			t.Fatalf("missing codec for %s", name)
+		}
+		data, err := codec.Encode(values[name])
+		if err != nil {
+			t.Fatalf("encoding %s: %v", name, err)
+		}
+		decoded, err := codec.Decode(data)
+		if err != nil {
+			t.Fatalf("decoding %s: %v", name, err)
+		}
+		if !reflect.DeepEqual(decoded, values[name]) {
+			t.Errorf("%s round trip: expected %+v, got %+v", name, values[name], decoded)
+		}
+	}
+
+	codec, _ := StructCodecByName("User")
+	if _, err := codec.Encode(Badge{}); err == nil {
+		t.Error("expected an error encoding a Badge as a User")
+	}
+	if _, ok := StructCodecByName("Missing"); ok {
+		t.Error("expected no codec for an unknown struct")
+	}
+}
+`}
+}
+
+func TestTypes_AddressHashText(t *testing.T) {
+	input := `{
+		"contracts": {
+			"Balances.sol:Balances": {
+				"abi": [
+					{
+						"type": "function",
+						"name": "balanceOf",
+						"inputs": [{"name": "account", "type": "address"}],
+						"outputs": [{"name": "", "type": "uint256"}],
+						"stateMutability": "view"
+					}
+				],
+				"hashes": {"balanceOf(address)": "70a08231"}
+			}
+		}
+	}`
+
+	contracts, err := processCombinedJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("processCombinedJSON failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := gen.NewGenerator(outputDir).Generate(contracts); err != nil {
+		t.Fatalf("code generation failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "balances", "balances.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	for _, want := range []string{
+		"func (a Address) MarshalText() ([]byte, error) {",
+		"func (a *Address) UnmarshalText(text []byte) error {",
+		"func (h Hash) MarshalText() ([]byte, error) {",
+		"func (h *Hash) UnmarshalText(text []byte) error {",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected %q in the generated package", want)
+		}
+	}
+}
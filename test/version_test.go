@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: MIT
+
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/otherview/solgen/internal/input"
+	"github.com/otherview/solgen/internal/parse"
+	"github.com/otherview/solgen/internal/types"
+)
+
+func TestVersion_NormalizedAcrossInputFormats(t *testing.T) {
+	abiJSON := `[{"type": "function", "name": "total", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"}]`
+
+	combinedInput := `{
+		"contracts": {
+			"Vault.sol:Vault": {"abi": ` + abiJSON + `, "bin": "0x6080", "bin-runtime": "0x6080", "hashes": {"total()": "2ddbd13a"}}
+		},
+		"version": "0.8.20+commit.a1b79de6.Linux.g++"
+	}`
+	var combined types.CombinedJSON
+	if err := json.Unmarshal([]byte(combinedInput), &combined); err != nil {
+		t.Fatalf("failed to parse combined JSON: %v", err)
+	}
+	combinedResult, err := convertCombinedToStandard(combined)
+	if err != nil {
+		t.Fatalf("convertCombinedToStandard failed: %v", err)
+	}
+	combinedContracts, err := parse.ResultWithVersion(combinedResult, combined.Version)
+	if err != nil {
+		t.Fatalf("parsing combined JSON failed: %v", err)
+	}
+
+	standardInput := `{
+		"contracts": {
+			"Vault.sol": {
+				"Vault": {
+					"abi": ` + abiJSON + `,
+					"metadata": "{\"compiler\":{\"version\":\"0.8.20+commit.a1b79de6\"}}",
+					"evm": {"methodIdentifiers": {"total()": "2ddbd13a"}}
+				}
+			}
+		}
+	}`
+	standardResult, solcVersion, err := input.StandardJSON([]byte(standardInput))
+	if err != nil {
+		t.Fatalf("input.StandardJSON failed: %v", err)
+	}
+	standardContracts, err := parse.ResultWithVersion(standardResult, solcVersion)
+	if err != nil {
+		t.Fatalf("parsing standard JSON failed: %v", err)
+	}
+
+	const want = "0.8.20+commit.a1b79de6"
+	if got := combinedContracts[0].SolcVersion; got != want {
+		t.Errorf("combined JSON: expected version %q, got %q", want, got)
+	}
+	if got := standardContracts[0].SolcVersion; got != want {
+		t.Errorf("standard JSON: expected version %q, got %q", want, got)
+	}
+}
+
+func TestVersion_Normalize(t *testing.T) {
+	for version, want := range map[string]string{
+		"0.8.20+commit.a1b79de6.Linux.g++":         "0.8.20+commit.a1b79de6",
+		"0.8.20+commit.a1b79de6":                   "0.8.20+commit.a1b79de6",
+		"v0.8.21-nightly.2023.5.1+commit.1c1b9b7e": "0.8.21+commit.1c1b9b7e",
+		"0.8.20":  "0.8.20",
+		"unknown": "unknown",
+	} {
+		if got := parse.NormalizeSolcVersion(version); got != want {
+			t.Errorf("NormalizeSolcVersion(%q) = %q, want %q", version, got, want)
+		}
+	}
+}
+
+func TestVersion_SupportedRange(t *testing.T) {
+	binaryPath := buildSolgen(t)
+	input := `{
+		"contracts": {
+			"Legacy.sol:Legacy": {
+				"abi": [{"type": "function", "name": "total", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"}],
+				"bin": "0x6080",
+				"bin-runtime": "0x6080",
+				"hashes": {"total()": "2ddbd13a"}
+			}
+		},
+		"version": "0.7.6+commit.7338295f.Linux.g++"
+	}`
+
+	// Out of range versions only warn by default
+	output, err := runSolgen(binaryPath, input, "--out", t.TempDir(), "--min-solc", "0.8.4")
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Warning:") || !strings.Contains(output, "older than the minimum supported version 0.8.4") {
+		t.Errorf("expected a solc version warning, got:\n%s", output)
+	}
+
+	// --strict turns the warning into an error
+	output, err = runSolgen(binaryPath, input, "--out", t.TempDir(), "--min-solc", "0.8.4", "--strict")
+	if err == nil {
+		t.Errorf("expected --strict to fail for solc 0.7.6, got:\n%s", output)
+	}
+
+	// Versions inside the range pass silently
+	output, err = runSolgen(binaryPath, input, "--out", t.TempDir(), "--min-solc", "0.7.0", "--max-solc", "0.7.6", "--strict")
+	if err != nil || strings.Contains(output, "Warning:") {
+		t.Errorf("expected solc 0.7.6 to be accepted, err %v, output:\n%s", err, output)
+	}
+
+	if err := parse.CheckSolcVersion("0.8.20", "0.8", ""); err == nil {
+		t.Error("expected an invalid --min-solc to be rejected")
+	}
+}
+
+func TestVersion_Override(t *testing.T) {
+	binaryPath := buildSolgen(t)
+	input := `{
+		"contracts": {
+			"Legacy.sol:Legacy": {
+				"abi": [{"type": "function", "name": "total", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"}],
+				"bin": "0x6080",
+				"bin-runtime": "0x6080",
+				"hashes": {"total()": "2ddbd13a"}
+			}
+		}
+	}`
+
+	outputDir := t.TempDir()
+	output, err := runSolgen(binaryPath, input, "--out", outputDir, "--solc-version", "0.8.24+commit.e11b9ed9.Linux.g++")
+	if err != nil {
+		t.Fatalf("solgen failed: %v\nOutput: %s", err, output)
+	}
+	content, err := os.ReadFile(filepath.Join(outputDir, "legacy", "legacy.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "// Contract: Legacy (solc 0.8.24+commit.e11b9ed9)\n") {
+		t.Errorf("expected the overridden version in the header, got:\n%s", content[:min(len(content), 300)])
+	}
+
+	// The override is also the version checked against the supported range
+	output, err = runSolgen(binaryPath, input, "--out", t.TempDir(), "--solc-version", "0.7.6", "--min-solc", "0.8.4", "--strict")
+	if err == nil {
+		t.Errorf("expected --strict to fail for the overridden solc 0.7.6, got:\n%s", output)
+	}
+}